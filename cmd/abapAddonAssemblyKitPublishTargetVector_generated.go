@@ -9,8 +9,10 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/metrics"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
@@ -77,6 +79,13 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
 			}
 
+			if err = tracing.Initialize(GeneralConfig.CorrelationID,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Endpoint,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Token,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.File); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up OpenTelemetry tracing")
+			}
+
 			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
 			if err != nil {
 				return err
@@ -114,10 +123,22 @@ For Terminology refer to the [Scenario Description](https://www.project-piper.io
 						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 					splunkClient.Send(telemetryClient.GetData(), logCollector)
 				}
+				stepMetrics := metrics.StepData{
+					StepName:      STEP_NAME,
+					StageName:     GeneralConfig.StageName,
+					Duration:      time.Since(startTime),
+					ErrorCode:     stepTelemetryData.ErrorCode,
+					ErrorCategory: stepTelemetryData.ErrorCategory,
+				}
+				metrics.Send(stepMetrics,
+					metrics.NewPushgatewaySink(GeneralConfig.HookConfig.PrometheusConfig.Pushgateway, GeneralConfig.HookConfig.PrometheusConfig.Job),
+					metrics.NewOTLPSink(GeneralConfig.HookConfig.OpenTelemetryConfig.MetricsEndpoint, GeneralConfig.HookConfig.OpenTelemetryConfig.Token))
+				tracing.EndStepSpan(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			tracing.StartStepSpan(STEP_NAME, GeneralConfig.StageName)
 			abapAddonAssemblyKitPublishTargetVector(stepConfig, &stepTelemetryData)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
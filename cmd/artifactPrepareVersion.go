@@ -6,10 +6,12 @@ import (
 	"io"
 	netHttp "net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/Masterminds/semver"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 
@@ -41,6 +43,7 @@ type gitRepository interface {
 }
 
 type gitWorktree interface {
+	Add(string) (plumbing.Hash, error)
 	Checkout(*git.CheckoutOptions) error
 	Commit(string, *git.CommitOptions) (plumbing.Hash, error)
 }
@@ -123,6 +126,19 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 		VersionSource:       config.DockerVersionSource,
 	}
 
+	// support former groovy versioning template and translate into new options
+	if len(config.VersioningTemplate) > 0 {
+		config.VersioningType, _, config.IncludeCommitID = templateCompatibility(config.VersioningTemplate)
+	}
+
+	if len(config.Artifacts) > 0 {
+		if config.VersioningType != "semantic-release" {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("multiple artifacts are only supported with versioningType 'semantic-release', not with '%v'", config.VersioningType)
+		}
+		return runMonorepoVersioning(config, commonPipelineEnvironment, &artifactOpts, utils, repository, getWorktree)
+	}
+
 	var err error
 	if artifact == nil {
 		artifact, err = versioning.GetArtifact(config.BuildTool, config.FilePath, &artifactOpts, utils)
@@ -132,11 +148,6 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 		}
 	}
 
-	// support former groovy versioning template and translate into new options
-	if len(config.VersioningTemplate) > 0 {
-		config.VersioningType, _, config.IncludeCommitID = templateCompatibility(config.VersioningTemplate)
-	}
-
 	version, err := artifact.GetVersion()
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
@@ -170,6 +181,16 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 		if err != nil {
 			return err
 		}
+		if config.VersioningType == "cloud" {
+			released, err := releasedVersions(repository, config.TagPrefix)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return errors.Wrap(err, "failed to retrieve released versions")
+			}
+			if err := validateVersionIncrease(artifact.VersioningScheme(), released, newVersion); err != nil {
+				return err
+			}
+		}
 
 		worktree, err := getWorktree(repository)
 		if err != nil {
@@ -212,6 +233,16 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 				return errors.Wrapf(err, "failed to push changes for version '%v'", newVersion)
 			}
 		}
+	} else if config.VersioningType == "calver" {
+		newVersion, gitCommitID, err = runCalendarVersioning(config, utils, &artifactOpts, artifact, gitCommit, repository, getWorktree, now)
+		if err != nil {
+			return err
+		}
+	} else if config.VersioningType == "semantic-release" {
+		newVersion, gitCommitID, err = runSemanticRelease(config, utils, &artifactOpts, artifact, version, gitCommit, repository, getWorktree, now)
+		if err != nil {
+			return err
+		}
 	} else {
 		// propagate version information to additional descriptors
 		if len(config.AdditionalTargetTools) > 0 {
@@ -244,6 +275,511 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 	return nil
 }
 
+// runSemanticRelease increases the version based on the conventional commits since the last version tag.
+// The updated build descriptors and changelog are commited and tagged unless a pull request is built.
+func runSemanticRelease(config *artifactPrepareVersionOptions, utils artifactPrepareVersionUtils, artifactOpts *versioning.Options, artifact versioning.Artifact, version string, gitCommit plumbing.Hash, repository gitRepository, getWorktree func(gitRepository) (gitWorktree, error), now time.Time) (string, string, error) {
+	gitCommitID := gitCommit.String()
+
+	lastTag, messages, err := commitMessagesSinceLastVersion(repository, config.TagPrefix)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to retrieve commits since last version")
+	}
+	bump := versioning.ConventionalCommitBump(messages)
+	if bump == versioning.BumpNone {
+		log.Entry().Infof("No feature or fix commits since last version, keeping version '%v'", version)
+		return version, gitCommitID, nil
+	}
+
+	// only the tag is pushed, thus the build descriptor may still contain the version before the last release
+	baseVersion := releasedVersion(version, lastTag, config.TagPrefix)
+	newVersion, err := versioning.BumpVersion(baseVersion, bump)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to calculate new version")
+	}
+	if len(lastTag) > 0 {
+		if err := validateVersionIncrease("semver2", []string{strings.TrimPrefix(lastTag, config.TagPrefix)}, newVersion); err != nil {
+			return "", "", err
+		}
+	}
+	log.Entry().Infof("Increasing %v version of '%v' based on %v commits since last version", bump, baseVersion, len(messages))
+
+	createTag := !config.IsOptimizedAndScheduled
+	provider, err := utils.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Warning("Cannot infer config from CI environment")
+	} else if provider.IsPullRequest() {
+		createTag = false
+	}
+
+	worktree, err := getWorktree(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to retrieve git worktree")
+	}
+	err = initializeWorktree(gitCommit, worktree)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = artifact.SetVersion(newVersion)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to write version")
+	}
+	if len(config.AdditionalTargetTools) > 0 {
+		err = propagateVersion(config, utils, artifactOpts, newVersion, gitCommitID, now)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	err = restoreReleasedChangelog(utils, repository, lastTag, config.ChangelogFile)
+	if err != nil {
+		return "", "", err
+	}
+	err = addToChangelog(utils, config.ChangelogFile, versioning.Changelog(newVersion, messages, now))
+	if err != nil {
+		return "", "", err
+	}
+
+	if !createTag {
+		return newVersion, gitCommitID, nil
+	}
+	if _, err := worktree.Add(config.ChangelogFile); err != nil {
+		return "", "", errors.Wrapf(err, "failed to add '%v'", config.ChangelogFile)
+	}
+	gitCommitID, err = pushChanges(config, newVersion, repository, worktree, now)
+	if err != nil {
+		if strings.Contains(fmt.Sprint(err), "reference already exists") {
+			log.SetErrorCategory(log.ErrorCustom)
+		}
+		return "", "", errors.Wrapf(err, "failed to push changes for version '%v'", newVersion)
+	}
+	return newVersion, gitCommitID, nil
+}
+
+// runCalendarVersioning sets the calendar version YYYY.MM.DD.N with the build number N following the version tags of the same day.
+// The updated build descriptors are commited and tagged unless a pull request is built.
+func runCalendarVersioning(config *artifactPrepareVersionOptions, utils artifactPrepareVersionUtils, artifactOpts *versioning.Options, artifact versioning.Artifact, gitCommit plumbing.Hash, repository gitRepository, getWorktree func(gitRepository) (gitWorktree, error), now time.Time) (string, string, error) {
+	gitCommitID := gitCommit.String()
+
+	released, err := releasedVersions(repository, config.TagPrefix)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to retrieve released versions")
+	}
+	newVersion := versioning.NextCalendarVersion(now, released, artifact.VersioningScheme())
+	if err := validateVersionIncrease("calver", released, newVersion); err != nil {
+		return "", "", err
+	}
+
+	createTag := !config.IsOptimizedAndScheduled
+	provider, err := utils.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Warning("Cannot infer config from CI environment")
+	} else if provider.IsPullRequest() {
+		createTag = false
+	}
+
+	worktree, err := getWorktree(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to retrieve git worktree")
+	}
+	err = initializeWorktree(gitCommit, worktree)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = artifact.SetVersion(newVersion)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", "", errors.Wrap(err, "failed to write version")
+	}
+	if len(config.AdditionalTargetTools) > 0 {
+		err = propagateVersion(config, utils, artifactOpts, newVersion, gitCommitID, now)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if !createTag {
+		return newVersion, gitCommitID, nil
+	}
+	gitCommitID, err = pushChanges(config, newVersion, repository, worktree, now)
+	if err != nil {
+		if strings.Contains(fmt.Sprint(err), "reference already exists") {
+			log.SetErrorCategory(log.ErrorCustom)
+		}
+		return "", "", errors.Wrapf(err, "failed to push changes for version '%v'", newVersion)
+	}
+	return newVersion, gitCommitID, nil
+}
+
+// validateVersionIncrease makes sure that the new version is greater than the latest released version according to the versioning scheme
+func validateVersionIncrease(scheme string, released []string, newVersion string) error {
+	versioningScheme, err := versioning.GetScheme(scheme)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	if err := versioning.ValidateVersionIncrease(versioningScheme, versioning.LatestVersion(versioningScheme, released), newVersion); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	return nil
+}
+
+// versionedArtifact is an artifact of a repository with multiple independently versioned artifacts
+type versionedArtifact struct {
+	name            string
+	buildTool       string
+	filePath        string
+	artifact        versioning.Artifact
+	originalVersion string
+	version         string
+	tag             string
+}
+
+// runMonorepoVersioning increases the versions of the artifacts with changes since their last version tag.
+// The updated build descriptors and changelogs are commited and each new version is pushed as tag with the artifact name as prefix.
+func runMonorepoVersioning(config *artifactPrepareVersionOptions, commonPipelineEnvironment *artifactPrepareVersionCommonPipelineEnvironment, artifactOpts *versioning.Options, utils artifactPrepareVersionUtils, repository gitRepository, getWorktree func(gitRepository) (gitWorktree, error)) error {
+	artifacts, err := resolveArtifacts(config, artifactOpts, utils)
+	if err != nil {
+		return err
+	}
+
+	gitCommit, gitCommitMessage, err := getGitCommitID(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	gitCommitID := gitCommit.String()
+	commonPipelineEnvironment.git.headCommitID = gitCommitID
+	commonPipelineEnvironment.git.commitMessage = gitCommitMessage
+	now := time.Now()
+
+	worktree, err := getWorktree(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to retrieve git worktree")
+	}
+	err = initializeWorktree(gitCommit, worktree)
+	if err != nil {
+		return err
+	}
+
+	var released []*versionedArtifact
+	for _, artifact := range artifacts {
+		changed, err := versionArtifact(config, utils, repository, artifact, now)
+		if err != nil {
+			return errors.Wrapf(err, "failed to version artifact '%v'", artifact.name)
+		}
+		if changed {
+			released = append(released, artifact)
+			if _, err := worktree.Add(artifactChangelogFile(config, artifact)); err != nil {
+				return errors.Wrapf(err, "failed to add changelog of artifact '%v'", artifact.name)
+			}
+		}
+	}
+
+	createTags := len(released) > 0 && !config.IsOptimizedAndScheduled
+	if provider, err := utils.NewOrchestratorSpecificConfigProvider(); err != nil {
+		log.Entry().WithError(err).Warning("Cannot infer config from CI environment")
+	} else if provider.IsPullRequest() {
+		createTags = false
+	}
+	if createTags {
+		tags, versions := []string{}, []string{}
+		for _, artifact := range released {
+			artifact.tag = fmt.Sprintf("%v/%v%v", artifact.name, config.TagPrefix, artifact.version)
+			tags = append(tags, artifact.tag)
+			versions = append(versions, fmt.Sprintf("%v %v", artifact.name, artifact.version))
+		}
+		commit, err := commitChanges(config, worktree, fmt.Sprintf("update versions %v", strings.Join(versions, ", ")), now)
+		if err != nil {
+			return err
+		}
+		gitCommitID = commit.String()
+		if err := pushTags(config, commit, tags, repository); err != nil {
+			if strings.Contains(fmt.Sprint(err), "reference already exists") {
+				log.SetErrorCategory(log.ErrorCustom)
+			}
+			return errors.Wrapf(err, "failed to push changes for tags %v", strings.Join(tags, ", "))
+		}
+	}
+
+	commonPipelineEnvironment.git.commitID = gitCommitID
+	for _, artifact := range artifacts {
+		entry := map[string]interface{}{
+			"name":            artifact.name,
+			"buildTool":       artifact.buildTool,
+			"filePath":        artifact.filePath,
+			"version":         artifact.version,
+			"originalVersion": artifact.originalVersion,
+		}
+		if len(artifact.tag) > 0 {
+			entry["tag"] = artifact.tag
+		}
+		if coordinates, err := artifact.artifact.GetCoordinates(); err == nil {
+			entry["groupId"] = coordinates.GroupID
+			entry["artifactId"] = coordinates.ArtifactID
+			entry["packaging"] = coordinates.Packaging
+		} else {
+			log.Entry().WithError(err).Warnf("failed to get coordinates of artifact '%v'", artifact.name)
+		}
+		commonPipelineEnvironment.custom.versionedArtifacts = append(commonPipelineEnvironment.custom.versionedArtifacts, entry)
+		log.Entry().Infof("Version of artifact '%v': '%v'", artifact.name, artifact.version)
+	}
+	return nil
+}
+
+// resolveArtifacts resolves the configured artifacts, file paths with glob patterns result in one artifact per matching build descriptor
+func resolveArtifacts(config *artifactPrepareVersionOptions, artifactOpts *versioning.Options, utils artifactPrepareVersionUtils) ([]*versionedArtifact, error) {
+	artifacts := []*versionedArtifact{}
+	names := map[string]string{}
+	for _, entry := range config.Artifacts {
+		pattern, _ := entry["filePath"].(string)
+		if len(pattern) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("artifact without filePath, each entry of artifacts requires the path of its build descriptor")
+		}
+		filePaths, err := utils.Glob(pattern)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to find build descriptors for '%v'", pattern)
+		}
+		if len(filePaths) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("no build descriptor found for '%v'", pattern)
+		}
+		for _, filePath := range filePaths {
+			if strings.Contains(filepath.ToSlash(filePath), "node_modules/") {
+				continue
+			}
+			buildTool, _ := entry["buildTool"].(string)
+			if len(buildTool) == 0 {
+				buildTool, _ = versioning.BuildToolForDescriptor(filePath)
+			}
+			if len(buildTool) == 0 {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, fmt.Errorf("cannot derive build tool of '%v', please provide buildTool", filePath)
+			}
+			name, _ := entry["name"].(string)
+			if len(name) == 0 || len(filePaths) > 1 {
+				name = artifactName(filePath, buildTool)
+			}
+			if other, exists := names[name]; exists {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, fmt.Errorf("artifacts '%v' and '%v' have the same name '%v', please provide a name", other, filePath, name)
+			}
+			names[name] = filePath
+
+			artifact, err := versioning.GetArtifact(buildTool, filePath, artifactOpts, utils)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return nil, errors.Wrapf(err, "failed to retrieve artifact '%v'", filePath)
+			}
+			artifacts = append(artifacts, &versionedArtifact{name: name, buildTool: buildTool, filePath: filePath, artifact: artifact})
+		}
+	}
+	return artifacts, nil
+}
+
+// artifactName derives the name of an artifact from the directory of its build descriptor, e.g. "services/backend" for "services/backend/pom.xml"
+func artifactName(filePath, buildTool string) string {
+	dir := filepath.ToSlash(filepath.Dir(filePath))
+	if dir == "." {
+		return buildTool
+	}
+	return dir
+}
+
+// versionArtifact increases the version of the artifact based on the commits changing files in its directory since its last version tag
+func versionArtifact(config *artifactPrepareVersionOptions, utils artifactPrepareVersionUtils, repository gitRepository, artifact *versionedArtifact, now time.Time) (bool, error) {
+	version, err := artifact.artifact.GetVersion()
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return false, errors.Wrap(err, "failed to retrieve version")
+	}
+	artifact.originalVersion, artifact.version = version, version
+
+	tagPrefix := fmt.Sprintf("%v/%v", artifact.name, config.TagPrefix)
+	lastTag, changes, err := changesSinceLastVersion(repository, tagPrefix)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return false, errors.Wrap(err, "failed to retrieve commits since last version")
+	}
+	dir := filepath.ToSlash(filepath.Dir(artifact.filePath))
+	messages := []string{}
+	for _, change := range changes {
+		if changesDirectory(change.Paths, dir) {
+			messages = append(messages, change.Message)
+		}
+	}
+	if len(messages) == 0 {
+		log.Entry().Infof("No changes of artifact '%v' since last version, keeping version '%v'", artifact.name, version)
+		return false, nil
+	}
+
+	bump := versioning.ConventionalCommitBump(messages)
+	if bump == versioning.BumpNone {
+		log.Entry().Infof("No feature or fix commits of artifact '%v' since last version, keeping version '%v'", artifact.name, version)
+		return false, nil
+	}
+	baseVersion := releasedVersion(version, lastTag, tagPrefix)
+	artifact.version, err = versioning.BumpVersion(baseVersion, bump)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return false, errors.Wrap(err, "failed to calculate new version")
+	}
+	if len(lastTag) > 0 {
+		if err := validateVersionIncrease("semver2", []string{strings.TrimPrefix(lastTag, tagPrefix)}, artifact.version); err != nil {
+			return false, errors.Wrapf(err, "invalid version of artifact '%v'", artifact.name)
+		}
+	}
+	log.Entry().Infof("Increasing %v version of artifact '%v' from '%v' to '%v' based on %v commits", bump, artifact.name, baseVersion, artifact.version, len(messages))
+
+	if err := artifact.artifact.SetVersion(artifact.version); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return false, errors.Wrap(err, "failed to write version")
+	}
+	changelogFile := artifactChangelogFile(config, artifact)
+	if err := restoreReleasedChangelog(utils, repository, lastTag, changelogFile); err != nil {
+		return false, err
+	}
+	return true, addToChangelog(utils, changelogFile, versioning.Changelog(artifact.version, messages, now))
+}
+
+func artifactChangelogFile(config *artifactPrepareVersionOptions, artifact *versionedArtifact) string {
+	return filepath.Join(filepath.Dir(artifact.filePath), config.ChangelogFile)
+}
+
+// changesDirectory checks whether one of the paths is located within dir, all paths are within the root directory "."
+func changesDirectory(paths []string, dir string) bool {
+	for _, path := range paths {
+		if dir == "." || strings.HasPrefix(path, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// changesSinceLastVersion returns the latest version tag and the commits since then together with their changed paths
+var changesSinceLastVersion = func(repository gitRepository, tagPrefix string) (string, []gitUtils.Change, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return "", nil, fmt.Errorf("commit history not available")
+	}
+	tag, err := gitUtils.LatestVersionTag(repo, tagPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	changes, err := gitUtils.ChangesSince(repo, tag)
+	return tag, changes, err
+}
+
+// commitMessagesSinceLastVersion returns the latest version tag and the messages of the commits since then
+var commitMessagesSinceLastVersion = func(repository gitRepository, tagPrefix string) (string, []string, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return "", nil, fmt.Errorf("commit history not available")
+	}
+	tag, err := gitUtils.LatestVersionTag(repo, tagPrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tag) == 0 {
+		log.Entry().Infof("No version tag with prefix '%v' found, considering all commits", tagPrefix)
+	} else {
+		log.Entry().Infof("Considering commits since version tag '%v'", tag)
+	}
+	messages, err := gitUtils.CommitMessagesSince(repo, tag)
+	return tag, messages, err
+}
+
+// releasedVersions returns the versions of the tags with the tag prefix
+var releasedVersions = func(repository gitRepository, tagPrefix string) ([]string, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return nil, fmt.Errorf("tags not available")
+	}
+	return gitUtils.VersionTags(repo, tagPrefix)
+}
+
+// releasedVersion returns the version of the last version tag in case it is higher than the version of the build descriptor
+func releasedVersion(version, lastTag, tagPrefix string) string {
+	if len(lastTag) == 0 {
+		return version
+	}
+	tagVersion, err := semver.NewVersion(strings.TrimPrefix(lastTag, tagPrefix))
+	if err != nil {
+		return version
+	}
+	if current, err := semver.NewVersion(version); err == nil && !tagVersion.GreaterThan(current) {
+		return version
+	}
+	return tagVersion.String()
+}
+
+// changelogOfVersion returns the content of the changelog file in the commit of a version tag
+var changelogOfVersion = func(repository gitRepository, tag, changelogFile string) ([]byte, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return nil, fmt.Errorf("commit history not available")
+	}
+	return gitUtils.FileContentAt(repo, tag, filepath.ToSlash(filepath.Clean(changelogFile)))
+}
+
+// restoreReleasedChangelog replaces the changelog of the workspace with the changelog of the last version tag.
+// Only the tags are pushed, thus the changelog of the branch does not contain the changes of the previous versions.
+func restoreReleasedChangelog(utils artifactPrepareVersionUtils, repository gitRepository, lastTag, changelogFile string) error {
+	if len(lastTag) == 0 {
+		return nil
+	}
+	content, err := changelogOfVersion(repository, lastTag, changelogFile)
+	if err != nil {
+		log.Entry().WithError(err).Debugf("No changelog found for version tag '%v', using '%v' of the workspace", lastTag, changelogFile)
+		return nil
+	}
+	if err := utils.FileWrite(changelogFile, content, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write changelog '%v'", changelogFile)
+	}
+	return nil
+}
+
+// addToChangelog adds the changes of a version at the top of the changelog file, below its title
+func addToChangelog(utils artifactPrepareVersionUtils, changelogFile, changes string) error {
+	title, content := "# Changelog\n", ""
+	if exists, _ := utils.FileExists(changelogFile); exists {
+		existing, err := utils.FileRead(changelogFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read changelog '%v'", changelogFile)
+		}
+		title, content = "", string(existing)
+		if strings.HasPrefix(content, "# ") {
+			parts := strings.SplitN(content, "\n", 2)
+			title = parts[0] + "\n"
+			content = ""
+			if len(parts) > 1 {
+				content = strings.TrimLeft(parts[1], "\n")
+			}
+		}
+	}
+	changelog := changes
+	if len(title) > 0 {
+		changelog = title + "\n" + changelog
+	}
+	if len(content) > 0 {
+		changelog += "\n" + content
+	}
+	if err := utils.FileWrite(changelogFile, []byte(changelog), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write changelog '%v'", changelogFile)
+	}
+	return nil
+}
+
 func openGit() (gitRepository, error) {
 	workdir, _ := os.Getwd()
 	return gitUtils.PlainOpen(workdir)
@@ -265,23 +801,11 @@ func getGitCommitID(repository gitRepository) (plumbing.Hash, string, error) {
 
 func versioningTemplate(scheme string) (string, error) {
 	// generally: timestamp acts as build number providing a proper order
-	switch scheme {
-	case "docker":
-		// from Docker documentation:
-		// A tag name must be valid ASCII and may contain lowercase and uppercase letters, digits, underscores, periods and dashes.
-		// A tag name may not start with a period or a dash and may contain a maximum of 128 characters.
-		return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}-{{.CommitID}}{{end}}{{end}}", nil
-	case "maven":
-		// according to https://www.mojohaus.org/versions-maven-plugin/version-rules.html
-		return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}_{{.CommitID}}{{end}}{{end}}", nil
-	case "pep440":
-		// according to https://www.python.org/dev/peps/pep-0440/
-		return "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}", nil
-	case "semver2":
-		// according to https://semver.org/spec/v2.0.0.html
-		return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}", nil
-	}
-	return "", fmt.Errorf("versioning scheme '%v' not supported", scheme)
+	versioningScheme, err := versioning.GetScheme(scheme)
+	if err != nil {
+		return "", err
+	}
+	return versioningScheme.Template(), nil
 }
 
 func calculateNewVersion(versioningTemplate, currentVersion, commitID string, includeCommitID, shortCommitID, unixTimestamp bool, t time.Time) (string, error) {
@@ -346,27 +870,30 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 	commitID = commit.String()
 
 	tag := fmt.Sprintf("%v%v", config.TagPrefix, newVersion)
-	_, err = repository.CreateTag(tag, commit, nil)
-	if err != nil {
-		return commitID, err
-	}
-
-	ref := gitConfig.RefSpec(fmt.Sprintf("refs/tags/%v:refs/tags/%v", tag, tag))
+	return commitID, pushTags(config, commit, []string{tag}, repository)
+}
 
-	pushOptions := git.PushOptions{
-		RefSpecs: []gitConfig.RefSpec{gitConfig.RefSpec(ref)},
+// pushTags creates the tags on the commit and pushes them to the remote origin
+func pushTags(config *artifactPrepareVersionOptions, commit plumbing.Hash, tags []string, repository gitRepository) error {
+	pushOptions := git.PushOptions{}
+	for _, tag := range tags {
+		_, err := repository.CreateTag(tag, commit, nil)
+		if err != nil {
+			return err
+		}
+		pushOptions.RefSpecs = append(pushOptions.RefSpecs, gitConfig.RefSpec(fmt.Sprintf("refs/tags/%v:refs/tags/%v", tag, tag)))
 	}
 
 	currentRemoteOrigin, err := repository.Remote("origin")
 	if err != nil {
-		return commitID, errors.Wrap(err, "failed to retrieve current remote origin")
+		return errors.Wrap(err, "failed to retrieve current remote origin")
 	}
 	var updatedRemoteOrigin *git.Remote
 
 	urls := originUrls(repository)
 	if len(urls) == 0 {
 		log.SetErrorCategory(log.ErrorConfiguration)
-		return commitID, fmt.Errorf("no remote url maintained")
+		return fmt.Errorf("no remote url maintained")
 	}
 	if strings.HasPrefix(urls[0], "http") {
 		if len(config.Username) == 0 || len(config.Password) == 0 {
@@ -378,17 +905,17 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 			// update remote origin url to point to ssh url instead of http(s) url
 			err = repository.DeleteRemote("origin")
 			if err != nil {
-				return commitID, errors.Wrap(err, "failed to update remote origin - remove")
+				return errors.Wrap(err, "failed to update remote origin - remove")
 			}
 			updatedRemoteOrigin, err = repository.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{remoteURL}})
 			if err != nil {
-				return commitID, errors.Wrap(err, "failed to update remote origin - create")
+				return errors.Wrap(err, "failed to update remote origin - create")
 			}
 
 			pushOptions.Auth, err = sshAgentAuth("git")
 			if err != nil {
 				log.SetErrorCategory(log.ErrorConfiguration)
-				return commitID, errors.Wrap(err, "failed to retrieve ssh authentication")
+				return errors.Wrap(err, "failed to retrieve ssh authentication")
 			}
 			log.Entry().Infof("using remote '%v'", remoteURL)
 		} else {
@@ -398,7 +925,7 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 		pushOptions.Auth, err = sshAgentAuth("git")
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return commitID, errors.Wrap(err, "failed to retrieve ssh authentication")
+			return errors.Wrap(err, "failed to retrieve ssh authentication")
 		}
 	}
 
@@ -422,26 +949,30 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 		case strings.Contains(errText, "connection timed out"):
 			log.SetErrorCategory(log.ErrorInfrastructure)
 		}
-		return commitID, err
+		return err
 	}
 
 	if updatedRemoteOrigin != currentRemoteOrigin {
 		err = repository.DeleteRemote("origin")
 		if err != nil {
-			return commitID, errors.Wrap(err, "failed to restore remote origin - remove")
+			return errors.Wrap(err, "failed to restore remote origin - remove")
 		}
 		_, err := repository.CreateRemote(currentRemoteOrigin.Config())
 		if err != nil {
-			return commitID, errors.Wrap(err, "failed to restore remote origin - create")
+			return errors.Wrap(err, "failed to restore remote origin - create")
 		}
 	}
 
-	return commitID, nil
+	return nil
 }
 
 func addAndCommit(config *artifactPrepareVersionOptions, worktree gitWorktree, newVersion string, t time.Time) (plumbing.Hash, error) {
+	return commitChanges(config, worktree, fmt.Sprintf("update version %v", newVersion), t)
+}
+
+func commitChanges(config *artifactPrepareVersionOptions, worktree gitWorktree, message string, t time.Time) (plumbing.Hash, error) {
 	//maybe more options are required: https://github.com/go-git/go-git/blob/master/_examples/commit/main.go
-	commit, err := worktree.Commit(fmt.Sprintf("update version %v", newVersion), &git.CommitOptions{All: true, Author: &object.Signature{Name: config.CommitUserName, When: t}})
+	commit, err := worktree.Commit(message, &git.CommitOptions{All: true, Author: &object.Signature{Name: config.CommitUserName, When: t}})
 	if err != nil {
 		return commit, errors.Wrap(err, "failed to commit new version")
 	}
@@ -10,36 +10,40 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/metrics"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
 
 type artifactPrepareVersionOptions struct {
-	AdditionalTargetTools       []string `json:"additionalTargetTools,omitempty" validate:"possible-values=custom docker dub golang gradle helm maven mta npm pip sbt yarn"`
-	AdditionalTargetDescriptors []string `json:"additionalTargetDescriptors,omitempty"`
-	BuildTool                   string   `json:"buildTool,omitempty" validate:"possible-values=custom docker dub golang gradle helm maven mta npm pip sbt yarn"`
-	CommitUserName              string   `json:"commitUserName,omitempty"`
-	CustomVersionField          string   `json:"customVersionField,omitempty"`
-	CustomVersionSection        string   `json:"customVersionSection,omitempty"`
-	CustomVersioningScheme      string   `json:"customVersioningScheme,omitempty" validate:"possible-values=docker maven pep440 semver2"`
-	DockerVersionSource         string   `json:"dockerVersionSource,omitempty"`
-	FetchCoordinates            bool     `json:"fetchCoordinates,omitempty"`
-	FilePath                    string   `json:"filePath,omitempty"`
-	GlobalSettingsFile          string   `json:"globalSettingsFile,omitempty"`
-	IncludeCommitID             bool     `json:"includeCommitId,omitempty"`
-	IsOptimizedAndScheduled     bool     `json:"isOptimizedAndScheduled,omitempty"`
-	M2Path                      string   `json:"m2Path,omitempty"`
-	Password                    string   `json:"password,omitempty"`
-	ProjectSettingsFile         string   `json:"projectSettingsFile,omitempty"`
-	ShortCommitID               bool     `json:"shortCommitId,omitempty"`
-	TagPrefix                   string   `json:"tagPrefix,omitempty"`
-	UnixTimestamp               bool     `json:"unixTimestamp,omitempty"`
-	Username                    string   `json:"username,omitempty"`
-	VersioningTemplate          string   `json:"versioningTemplate,omitempty"`
-	VersioningType              string   `json:"versioningType,omitempty" validate:"possible-values=cloud cloud_noTag library"`
+	AdditionalTargetTools       []string                 `json:"additionalTargetTools,omitempty" validate:"possible-values=cargo composer custom docker dotnet dub golang gradle helm maven mta npm pip sbt yarn"`
+	AdditionalTargetDescriptors []string                 `json:"additionalTargetDescriptors,omitempty"`
+	Artifacts                   []map[string]interface{} `json:"artifacts,omitempty"`
+	BuildTool                   string                   `json:"buildTool,omitempty" validate:"possible-values=cargo composer custom docker dotnet dub golang gradle helm maven mta npm pip sbt yarn"`
+	ChangelogFile               string                   `json:"changelogFile,omitempty"`
+	CommitUserName              string                   `json:"commitUserName,omitempty"`
+	CustomVersionField          string                   `json:"customVersionField,omitempty"`
+	CustomVersionSection        string                   `json:"customVersionSection,omitempty"`
+	CustomVersioningScheme      string                   `json:"customVersioningScheme,omitempty" validate:"possible-values=calver docker maven pep440 semver2"`
+	DockerVersionSource         string                   `json:"dockerVersionSource,omitempty"`
+	FetchCoordinates            bool                     `json:"fetchCoordinates,omitempty"`
+	FilePath                    string                   `json:"filePath,omitempty"`
+	GlobalSettingsFile          string                   `json:"globalSettingsFile,omitempty"`
+	IncludeCommitID             bool                     `json:"includeCommitId,omitempty"`
+	IsOptimizedAndScheduled     bool                     `json:"isOptimizedAndScheduled,omitempty"`
+	M2Path                      string                   `json:"m2Path,omitempty"`
+	Password                    string                   `json:"password,omitempty"`
+	ProjectSettingsFile         string                   `json:"projectSettingsFile,omitempty"`
+	ShortCommitID               bool                     `json:"shortCommitId,omitempty"`
+	TagPrefix                   string                   `json:"tagPrefix,omitempty"`
+	UnixTimestamp               bool                     `json:"unixTimestamp,omitempty"`
+	Username                    string                   `json:"username,omitempty"`
+	VersioningTemplate          string                   `json:"versioningTemplate,omitempty"`
+	VersioningType              string                   `json:"versioningType,omitempty" validate:"possible-values=cloud cloud_noTag calver library semantic-release"`
 }
 
 type artifactPrepareVersionCommonPipelineEnvironment struct {
@@ -53,6 +57,9 @@ type artifactPrepareVersionCommonPipelineEnvironment struct {
 		headCommitID  string
 		commitMessage string
 	}
+	custom struct {
+		versionedArtifacts []map[string]interface{}
+	}
 }
 
 func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resourceName string) {
@@ -69,6 +76,7 @@ func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resource
 		{category: "git", name: "commitId", value: p.git.commitID},
 		{category: "git", name: "headCommitId", value: p.git.headCommitID},
 		{category: "git", name: "commitMessage", value: p.git.commitMessage},
+		{category: "custom", name: "versionedArtifacts", value: p.custom.versionedArtifacts},
 	}
 
 	errCount := 0
@@ -137,6 +145,35 @@ Unlike for the _Continuous Deloyment_ pattern described above, in this case ther
 
 Configuration of this pattern is done via ` + "`" + `versioningType: library` + "`" + `.
 
+### 3. Semantic release based on commit messages
+
+Instead of increasing the version manually, the version can be derived from commit messages following the [Conventional Commits](https://www.conventionalcommits.org) specification.
+All commits since the last version tag (` + "`" + `<tagPrefix><major>.<minor>.<patch>` + "`" + `) are considered:
+
+* a breaking change (` + "`" + `feat!: ...` + "`" + ` or a ` + "`" + `BREAKING CHANGE:` + "`" + ` footer) increases the major version
+* a feature (` + "`" + `feat: ...` + "`" + `) increases the minor version
+* a fix (` + "`" + `fix: ...` + "`" + ` or ` + "`" + `perf: ...` + "`" + `) increases the patch version
+
+The changes are added to the [` + "`" + `changelogFile` + "`" + `](#changelogfile), the build descriptors and the changelog are commited and the new version is pushed as tag.
+Since only the tag is pushed, the changelog of the branch is not updated. The changes are therefore added to the changelog of the last version tag, thus the changelog of each tag contains all versions.
+Without features or fixes since the last version tag the version remains unchanged.
+
+Configuration of this pattern is done via ` + "`" + `versioningType: semantic-release` + "`" + `. Please make sure that the complete git history including tags is available in the workspace, i.e. no shallow clone is used.
+
+Repositories containing several artifacts with independent versions, e.g. a backend, a UI and a Helm chart, are supported via the parameter [` + "`" + `artifacts` + "`" + `](#artifacts).
+Each artifact is versioned based on the commits changing files in the directory of its build descriptor and tagged as ` + "`" + `<artifact name>/<tagPrefix><version>` + "`" + `.
+The versions of all artifacts are provided as list ` + "`" + `custom/versionedArtifacts` + "`" + ` in the common pipeline environment.
+
+### 4. Calendar versioning
+
+Teams releasing continuously may prefer a version based on the date, i.e. ` + "`" + `YYYY.MM.DD.N` + "`" + ` according to [CalVer](https://calver.org).
+The build number ` + "`" + `N` + "`" + ` starts with ` + "`" + `1` + "`" + ` every day and is increased based on the version tags (` + "`" + `<tagPrefix><version>` + "`" + `) of the same day.
+Build descriptors requiring a semantic version (e.g. ` + "`" + `npm` + "`" + `) receive the version ` + "`" + `YYYY.MMDD.N` + "`" + ` instead since semantic versions are limited to three numbers.
+
+Configuration of this pattern is done via ` + "`" + `versioningType: calver` + "`" + `. Like for ` + "`" + `semantic-release` + "`" + ` the complete git history including tags needs to be available.
+
+For ` + "`" + `cloud` + "`" + `, ` + "`" + `semantic-release` + "`" + ` and ` + "`" + `calver` + "`" + ` the new version must be greater than the version of the latest tag, otherwise the step fails.
+
 ### Support of additional build tools
 
 Besides the ` + "`" + `buildTools` + "`" + ` provided out of the box (like ` + "`" + `maven` + "`" + `, ` + "`" + `mta` + "`" + `, ` + "`" + `npm` + "`" + `, ...) it is possible to set ` + "`" + `buildTool: custom` + "`" + `.
@@ -196,6 +233,13 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
 			}
 
+			if err = tracing.Initialize(GeneralConfig.CorrelationID,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Endpoint,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Token,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.File); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up OpenTelemetry tracing")
+			}
+
 			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
 			if err != nil {
 				return err
@@ -234,10 +278,22 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 					splunkClient.Send(telemetryClient.GetData(), logCollector)
 				}
+				stepMetrics := metrics.StepData{
+					StepName:      STEP_NAME,
+					StageName:     GeneralConfig.StageName,
+					Duration:      time.Since(startTime),
+					ErrorCode:     stepTelemetryData.ErrorCode,
+					ErrorCategory: stepTelemetryData.ErrorCategory,
+				}
+				metrics.Send(stepMetrics,
+					metrics.NewPushgatewaySink(GeneralConfig.HookConfig.PrometheusConfig.Pushgateway, GeneralConfig.HookConfig.PrometheusConfig.Job),
+					metrics.NewOTLPSink(GeneralConfig.HookConfig.OpenTelemetryConfig.MetricsEndpoint, GeneralConfig.HookConfig.OpenTelemetryConfig.Token))
+				tracing.EndStepSpan(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			tracing.StartStepSpan(STEP_NAME, GeneralConfig.StageName)
 			artifactPrepareVersion(stepConfig, &stepTelemetryData, &commonPipelineEnvironment)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
@@ -251,10 +307,12 @@ Define ` + "`" + `buildTool: custom` + "`" + `, ` + "`" + `filePath: <path to yo
 func addArtifactPrepareVersionFlags(cmd *cobra.Command, stepConfig *artifactPrepareVersionOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.AdditionalTargetTools, "additionalTargetTools", []string{}, "Additional buildTool targets where descriptors need to be updated besides the main `buildTool`.")
 	cmd.Flags().StringSliceVar(&stepConfig.AdditionalTargetDescriptors, "additionalTargetDescriptors", []string{}, "Defines patterns for build descriptors which should be used for option [`additionalTargetTools`](additionaltargettools).")
+
 	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", os.Getenv("PIPER_buildTool"), "Defines the tool which is used for building the artifact.")
+	cmd.Flags().StringVar(&stepConfig.ChangelogFile, "changelogFile", `CHANGELOG.md`, "Defines the file to which the changes of a new version are added (only `versioningType: semantic-release`).")
 	cmd.Flags().StringVar(&stepConfig.CommitUserName, "commitUserName", `Project Piper`, "Defines the user name which appears in version control for the versioning update (in case `versioningType: cloud`).")
 	cmd.Flags().StringVar(&stepConfig.CustomVersionField, "customVersionField", os.Getenv("PIPER_customVersionField"), "For `buildTool: custom`: Defines the field which contains the version in the descriptor file.")
-	cmd.Flags().StringVar(&stepConfig.CustomVersionSection, "customVersionSection", os.Getenv("PIPER_customVersionSection"), "For `buildTool: custom`: Defines the section for version retrieval in vase a *.ini/*.cfg file is used.")
+	cmd.Flags().StringVar(&stepConfig.CustomVersionSection, "customVersionSection", os.Getenv("PIPER_customVersionSection"), "For `buildTool: custom`: Defines the section for version retrieval in case a *.ini/*.cfg or *.toml file is used.")
 	cmd.Flags().StringVar(&stepConfig.CustomVersioningScheme, "customVersioningScheme", `maven`, "For `buildTool: custom`: Defines the versioning scheme to be used.")
 	cmd.Flags().StringVar(&stepConfig.DockerVersionSource, "dockerVersionSource", os.Getenv("PIPER_dockerVersionSource"), "For `buildTool: docker`: Defines the source of the version. Can be `FROM`, any supported _buildTool_ or an environment variable name.")
 	cmd.Flags().BoolVar(&stepConfig.FetchCoordinates, "fetchCoordinates", false, "If set to `true` the step will retreive artifact coordinates and store them in the common pipeline environment.")
@@ -266,7 +324,7 @@ func addArtifactPrepareVersionFlags(cmd *cobra.Command, stepConfig *artifactPrep
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for git authentication.")
 	cmd.Flags().StringVar(&stepConfig.ProjectSettingsFile, "projectSettingsFile", os.Getenv("PIPER_projectSettingsFile"), "Maven only - Path to the mvn settings file that should be used as project settings file.")
 	cmd.Flags().BoolVar(&stepConfig.ShortCommitID, "shortCommitId", false, "Defines if a short version of the commitId should be used. GitHub format is used (first 7 characters).")
-	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud`).")
+	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud`, `semantic-release` and `calver`).")
 	cmd.Flags().BoolVar(&stepConfig.UnixTimestamp, "unixTimestamp", false, "Defines if the Unix timestamp number should be used as build number instead of the standard date format.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for git authentication")
 	cmd.Flags().StringVar(&stepConfig.VersioningTemplate, "versioningTemplate", os.Getenv("PIPER_versioningTemplate"), "DEPRECATED: Defines the template for the automatic version which will be created")
@@ -308,6 +366,14 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "artifacts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "buildTool",
 						ResourceRef: []config.ResourceReference{},
@@ -317,6 +383,15 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_buildTool"),
 					},
+					{
+						Name:        "changelogFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `CHANGELOG.md`,
+					},
 					{
 						Name:        "commitUserName",
 						ResourceRef: []config.ResourceReference{},
@@ -536,6 +611,7 @@ func artifactPrepareVersionMetadata() config.StepData {
 							{"name": "git/commitId"},
 							{"name": "git/headCommitId"},
 							{"name": "git/commitMessage"},
+							{"name": "custom/versionedArtifacts", "type": "[]map[string]interface{}"},
 						},
 					},
 				},
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	gitUtils "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
@@ -149,6 +150,7 @@ func (r *gitRepositoryMock) Worktree() (*git.Worktree, error) {
 }
 
 type gitWorktreeMock struct {
+	addedPaths    []string
 	checkoutError string
 	checkoutOpts  *git.CheckoutOptions
 	commitHash    plumbing.Hash
@@ -157,6 +159,11 @@ type gitWorktreeMock struct {
 	commitError   string
 }
 
+func (w *gitWorktreeMock) Add(path string) (plumbing.Hash, error) {
+	w.addedPaths = append(w.addedPaths, path)
+	return plumbing.Hash{}, nil
+}
+
 func (w *gitWorktreeMock) Checkout(opts *git.CheckoutOptions) error {
 	if len(w.checkoutError) > 0 {
 		return fmt.Errorf(w.checkoutError)
@@ -196,6 +203,11 @@ func (a *artifactPrepareVersionMockUtils) NewOrchestratorSpecificConfigProvider(
 }
 
 func TestRunArtifactPrepareVersion(t *testing.T) {
+	versions := releasedVersions
+	defer func() { releasedVersions = versions }()
+	releasedVersions = func(repository gitRepository, tagPrefix string) ([]string, error) {
+		return []string{"1.2.2", "1.2.3-20200101000000_abc"}, nil
+	}
 
 	t.Run("success case - cloud", func(t *testing.T) {
 
@@ -291,6 +303,39 @@ func TestRunArtifactPrepareVersion(t *testing.T) {
 		assert.Equal(t, repo.revisionHash.String(), cpe.git.commitID)
 	})
 
+	t.Run("error case - cloud version not greater than released version", func(t *testing.T) {
+		defer func() {
+			releasedVersions = func(repository gitRepository, tagPrefix string) ([]string, error) {
+				return []string{"1.2.2", "1.2.3-20200101000000_abc"}, nil
+			}
+		}()
+		releasedVersions = func(repository gitRepository, tagPrefix string) ([]string, error) {
+			return []string{"1.2.2", "1.2.4"}, nil
+		}
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			Password:       "****",
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "cloud",
+		}
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3",
+			versioningScheme: "maven",
+		}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, &versioningMock, newArtifactPrepareVersionMockUtils(), &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.Contains(t, fmt.Sprint(err), "is not greater than last released version '1.2.4'")
+		assert.False(t, repo.pushCalled)
+	})
+
 	t.Run("success case - compatibility", func(t *testing.T) {
 		config := artifactPrepareVersionOptions{
 			BuildTool:          "maven",
@@ -550,6 +595,340 @@ func TestRunArtifactPrepareVersion(t *testing.T) {
 	})
 }
 
+func TestRunArtifactPrepareVersionSemanticRelease(t *testing.T) {
+	commitMessages := commitMessagesSinceLastVersion
+	defer func() { commitMessagesSinceLastVersion = commitMessages }()
+	changelogOfTag := changelogOfVersion
+
+	prepare := func(tag string, messages []string) (*artifactPrepareVersionOptions, *artifactVersioningMock, *artifactPrepareVersionMockUtils, *gitRepositoryMock, *gitWorktreeMock) {
+		commitMessagesSinceLastVersion = func(repository gitRepository, tagPrefix string) (string, []string, error) {
+			return tag, messages, nil
+		}
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			ChangelogFile:  "CHANGELOG.md",
+			Password:       "****",
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "semantic-release",
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+		return &config, &artifactVersioningMock{originalVersion: "1.2.3", versioningScheme: "maven"}, newArtifactPrepareVersionMockUtils(), &repo, &worktree
+	}
+
+	t.Run("success case - minor release", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare("v1.2.3", []string{"fix: crash", "feat(api): export"})
+		utils.AddFile("CHANGELOG.md", []byte("# Changelog\n\n## 1.2.3 (2022-09-01)\n"))
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.3.0", versioningMock.newVersion)
+		assert.Equal(t, "1.3.0", cpe.artifactVersion)
+		assert.Equal(t, "1.2.3", cpe.originalArtifactVersion)
+		assert.Equal(t, "v1.3.0", repo.tag)
+		assert.True(t, repo.pushCalled)
+		assert.Equal(t, []string{"CHANGELOG.md"}, worktree.addedPaths)
+		assert.Equal(t, "update version 1.3.0", worktree.commitMsg)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+		changelog, _ := utils.FileRead("CHANGELOG.md")
+		assert.Contains(t, string(changelog), "# Changelog\n\n## 1.3.0 ")
+		assert.Contains(t, string(changelog), "### Features\n\n* **api:** export\n")
+		assert.Contains(t, string(changelog), "\n## 1.2.3 (2022-09-01)\n")
+	})
+
+	t.Run("success case - changelog of last version tag", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare("v1.2.3", []string{"fix: crash"})
+		utils.AddFile("CHANGELOG.md", []byte("# Changelog\n\n## 1.0.0 (2022-08-01)\n"))
+		changelogOfVersion = func(repository gitRepository, tag, changelogFile string) ([]byte, error) {
+			assert.Equal(t, "v1.2.3", tag)
+			assert.Equal(t, "CHANGELOG.md", changelogFile)
+			return []byte("# Changelog\n\n## 1.2.3 (2022-09-01)\n\n## 1.0.0 (2022-08-01)\n"), nil
+		}
+		defer func() { changelogOfVersion = changelogOfTag }()
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		changelog, _ := utils.FileRead("CHANGELOG.md")
+		assert.Contains(t, string(changelog), "# Changelog\n\n## 1.2.4 ")
+		assert.Contains(t, string(changelog), "\n## 1.2.3 (2022-09-01)\n\n## 1.0.0 (2022-08-01)\n")
+	})
+
+	t.Run("success case - version of last tag is higher than version of build descriptor", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare("v2.0.0", []string{"fix: crash"})
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.1", versioningMock.newVersion)
+		assert.Equal(t, "v2.0.1", repo.tag)
+		assert.True(t, utils.HasWrittenFile("CHANGELOG.md"))
+	})
+
+	t.Run("success case - no release relevant commits", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare("v1.2.3", []string{"docs: readme", "chore: update dependencies"})
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "", versioningMock.newVersion)
+		assert.Equal(t, "1.2.3", cpe.artifactVersion)
+		assert.False(t, repo.pushCalled)
+		assert.False(t, utils.HasWrittenFile("CHANGELOG.md"))
+	})
+
+	t.Run("success case - no tag in optimized and scheduled mode", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare("", []string{"feat!: new API"})
+		config.IsOptimizedAndScheduled = true
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", cpe.artifactVersion)
+		assert.Equal(t, repo.revisionHash.String(), cpe.git.commitID)
+		assert.False(t, repo.pushCalled)
+		assert.Empty(t, repo.tag)
+	})
+
+	t.Run("error case - commit history not available", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare("", nil)
+		commitMessagesSinceLastVersion = func(repository gitRepository, tagPrefix string) (string, []string, error) {
+			return "", nil, fmt.Errorf("commit history not available")
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.EqualError(t, err, "failed to retrieve commits since last version: commit history not available")
+	})
+}
+
+func runSemanticReleaseForTest(config *artifactPrepareVersionOptions, cpe *artifactPrepareVersionCommonPipelineEnvironment, artifact versioning.Artifact, utils artifactPrepareVersionUtils, repo *gitRepositoryMock, worktree *gitWorktreeMock) error {
+	return runArtifactPrepareVersion(config, &telemetry.CustomData{}, cpe, artifact, utils, repo, func(r gitRepository) (gitWorktree, error) { return worktree, nil })
+}
+
+func TestRunArtifactPrepareVersionCalendarVersioning(t *testing.T) {
+	versions := releasedVersions
+	defer func() { releasedVersions = versions }()
+
+	prepare := func(released []string) (*artifactPrepareVersionOptions, *artifactVersioningMock, *artifactPrepareVersionMockUtils, *gitRepositoryMock, *gitWorktreeMock) {
+		releasedVersions = func(repository gitRepository, tagPrefix string) ([]string, error) {
+			return released, nil
+		}
+		config := artifactPrepareVersionOptions{
+			BuildTool:      "maven",
+			Password:       "****",
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "calver",
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+		return &config, &artifactVersioningMock{originalVersion: "2022.10.1.1", versioningScheme: "maven"}, newArtifactPrepareVersionMockUtils(), &repo, &worktree
+	}
+	today := time.Now().Format("2006.01.02")
+
+	t.Run("success case - first build of the day", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare([]string{"2022.10.01.1", "2022.10.01.2"})
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, today+".1", versioningMock.newVersion)
+		assert.Equal(t, today+".1", cpe.artifactVersion)
+		assert.Equal(t, "2022.10.1.1", cpe.originalArtifactVersion)
+		assert.Equal(t, "v"+today+".1", repo.tag)
+		assert.True(t, repo.pushCalled)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("success case - build number increased", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare([]string{today + ".1", today + ".9", today + ".10", "next"})
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, today+".11", versioningMock.newVersion)
+		assert.Equal(t, "v"+today+".11", repo.tag)
+	})
+
+	t.Run("success case - no tag in optimized and scheduled mode", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare(nil)
+		config.IsOptimizedAndScheduled = true
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.NoError(t, err)
+		assert.Equal(t, today+".1", cpe.artifactVersion)
+		assert.False(t, repo.pushCalled)
+	})
+
+	t.Run("error case - released version is newer", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare([]string{"2099.01.01.1"})
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.EqualError(t, err, fmt.Sprintf("new version '%v.1' is not greater than last released version '2099.01.01.1'", today))
+		assert.False(t, repo.pushCalled)
+	})
+
+	t.Run("error case - tags not available", func(t *testing.T) {
+		config, versioningMock, utils, repo, worktree := prepare(nil)
+		releasedVersions = func(repository gitRepository, tagPrefix string) ([]string, error) {
+			return nil, fmt.Errorf("tags not available")
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runSemanticReleaseForTest(config, &cpe, versioningMock, utils, repo, worktree)
+
+		assert.EqualError(t, err, "failed to retrieve released versions: tags not available")
+	})
+}
+
+func TestRunArtifactPrepareVersionMonorepo(t *testing.T) {
+	changesSince := changesSinceLastVersion
+	defer func() { changesSinceLastVersion = changesSince }()
+	changesSinceLastVersion = func(repository gitRepository, tagPrefix string) (string, []gitUtils.Change, error) {
+		changes := []gitUtils.Change{
+			{Message: "feat(a): new template", Paths: []string{"charts/a/templates/service.yaml"}},
+			{Message: "docs: readme", Paths: []string{"README.md", "charts/b-docs/README.md"}},
+			{Message: "chore(b): update dependencies", Paths: []string{"charts/b/requirements.yaml"}},
+		}
+		if tagPrefix == "charts/a/v" {
+			return "charts/a/v1.2.3", changes, nil
+		}
+		return "", changes, nil
+	}
+
+	prepare := func() (*artifactPrepareVersionOptions, *artifactPrepareVersionMockUtils, *gitRepositoryMock, *gitWorktreeMock) {
+		config := artifactPrepareVersionOptions{
+			Artifacts:      []map[string]interface{}{{"filePath": "charts/*/Chart.yaml"}},
+			BuildTool:      "maven",
+			ChangelogFile:  "CHANGELOG.md",
+			Password:       "****",
+			TagPrefix:      "v",
+			Username:       "testUser",
+			VersioningType: "semantic-release",
+		}
+		utils := newArtifactPrepareVersionMockUtils()
+		for _, name := range []string{"a", "b"} {
+			content, _ := yaml.Marshal(chart.Metadata{Name: name, Version: "1.2.3"})
+			utils.AddFile(fmt.Sprintf("charts/%v/Chart.yaml", name), content)
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+		worktree := gitWorktreeMock{commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4})}
+		return &config, utils, &repo, &worktree
+	}
+
+	t.Run("success case - only changed artifacts are versioned", func(t *testing.T) {
+		config, utils, repo, worktree := prepare()
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		err := runArtifactPrepareVersion(config, &telemetry.CustomData{}, &cpe, nil, utils, repo, func(r gitRepository) (gitWorktree, error) { return worktree, nil })
+
+		assert.NoError(t, err)
+		chartA, _ := utils.FileRead("charts/a/Chart.yaml")
+		assert.Contains(t, string(chartA), "version: 1.3.0")
+		chartB, _ := utils.FileRead("charts/b/Chart.yaml")
+		assert.Contains(t, string(chartB), "version: 1.2.3")
+		assert.True(t, utils.HasWrittenFile("charts/a/CHANGELOG.md"))
+		assert.False(t, utils.HasWrittenFile("charts/b/CHANGELOG.md"))
+		assert.Equal(t, []string{"charts/a/CHANGELOG.md"}, worktree.addedPaths)
+		assert.Equal(t, "update versions charts/a 1.3.0", worktree.commitMsg)
+		assert.Equal(t, "charts/a/v1.3.0", repo.tag)
+		assert.Equal(t, []gitConfig.RefSpec{"refs/tags/charts/a/v1.3.0:refs/tags/charts/a/v1.3.0"}, repo.pushOptions.RefSpecs)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+		assert.Equal(t, repo.revisionHash.String(), cpe.git.headCommitID)
+		assert.Equal(t, []map[string]interface{}{
+			{"name": "charts/a", "buildTool": "helm", "filePath": "charts/a/Chart.yaml", "version": "1.3.0", "originalVersion": "1.2.3", "tag": "charts/a/v1.3.0", "groupId": "", "artifactId": "a", "packaging": ""},
+			{"name": "charts/b", "buildTool": "helm", "filePath": "charts/b/Chart.yaml", "version": "1.2.3", "originalVersion": "1.2.3", "groupId": "", "artifactId": "b", "packaging": ""},
+		}, cpe.custom.versionedArtifacts)
+	})
+
+	t.Run("error case - versioning type not supported", func(t *testing.T) {
+		config, utils, repo, worktree := prepare()
+		config.VersioningType = "cloud"
+
+		err := runArtifactPrepareVersion(config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, nil, utils, repo, func(r gitRepository) (gitWorktree, error) { return worktree, nil })
+
+		assert.EqualError(t, err, "multiple artifacts are only supported with versioningType 'semantic-release', not with 'cloud'")
+	})
+
+	t.Run("error case - unknown build descriptor", func(t *testing.T) {
+		config, utils, repo, worktree := prepare()
+		config.Artifacts = []map[string]interface{}{{"filePath": "ui/version.txt"}}
+		utils.AddFile("ui/version.txt", []byte("1.2.3"))
+
+		err := runArtifactPrepareVersion(config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, nil, utils, repo, func(r gitRepository) (gitWorktree, error) { return worktree, nil })
+
+		assert.EqualError(t, err, "cannot derive build tool of 'ui/version.txt', please provide buildTool")
+	})
+
+	t.Run("error case - duplicate artifact name", func(t *testing.T) {
+		config, utils, repo, worktree := prepare()
+		config.Artifacts = []map[string]interface{}{{"filePath": "charts/a/Chart.yaml", "name": "chart"}, {"filePath": "charts/b/Chart.yaml", "name": "chart"}}
+
+		err := runArtifactPrepareVersion(config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, nil, utils, repo, func(r gitRepository) (gitWorktree, error) { return worktree, nil })
+
+		assert.EqualError(t, err, "artifacts 'charts/a/Chart.yaml' and 'charts/b/Chart.yaml' have the same name 'chart', please provide a name")
+	})
+}
+
+func TestChangesDirectory(t *testing.T) {
+	assert.True(t, changesDirectory([]string{"README.md"}, "."))
+	assert.True(t, changesDirectory([]string{"README.md", "ui/src/app.js"}, "ui"))
+	assert.False(t, changesDirectory([]string{"ui-tests/app.js"}, "ui"))
+	assert.False(t, changesDirectory([]string{}, "."))
+}
+
+func TestReleasedVersion(t *testing.T) {
+	assert.Equal(t, "1.2.3", releasedVersion("1.2.3", "", "v"))
+	assert.Equal(t, "1.2.3", releasedVersion("1.2.3", "v1.2.3", "v"))
+	assert.Equal(t, "1.3.0", releasedVersion("1.2.3", "v1.3.0", "v"))
+	assert.Equal(t, "2.0.0", releasedVersion("2.0.0", "v1.3.0", "v"))
+	assert.Equal(t, "1.2.3", releasedVersion("1.2.3", "vnext", "v"))
+}
+
+func TestAddToChangelog(t *testing.T) {
+	t.Run("new changelog", func(t *testing.T) {
+		utils := newArtifactPrepareVersionMockUtils()
+		assert.NoError(t, addToChangelog(utils, "CHANGELOG.md", "## 1.0.0 (2022-10-03)\n"))
+		content, _ := utils.FileRead("CHANGELOG.md")
+		assert.Equal(t, "# Changelog\n\n## 1.0.0 (2022-10-03)\n", string(content))
+	})
+
+	t.Run("changelog without title", func(t *testing.T) {
+		utils := newArtifactPrepareVersionMockUtils()
+		utils.AddFile("CHANGES.md", []byte("## 1.0.0 (2022-10-03)\n"))
+		assert.NoError(t, addToChangelog(utils, "CHANGES.md", "## 1.1.0 (2022-10-04)\n"))
+		content, _ := utils.FileRead("CHANGES.md")
+		assert.Equal(t, "## 1.1.0 (2022-10-04)\n\n## 1.0.0 (2022-10-03)\n", string(content))
+	})
+}
+
 func TestVersioningTemplate(t *testing.T) {
 	tt := []struct {
 		scheme      string
@@ -13,9 +13,11 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/gcs"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/metrics"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/bmatcuk/doublestar"
 	"github.com/spf13/cobra"
@@ -117,63 +119,60 @@ type checkmarxExecuteScanInflux struct {
 	}
 }
 
-func (i *checkmarxExecuteScanInflux) persist(path, resourceName string) {
-	measurementContent := []struct {
-		measurement string
-		valType     string
-		name        string
-		value       interface{}
-	}{
-		{valType: config.InfluxField, measurement: "step_data", name: "checkmarx", value: i.step_data.fields.checkmarx},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_issues", value: i.checkmarx_data.fields.high_issues},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_not_false_positive", value: i.checkmarx_data.fields.high_not_false_positive},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_not_exploitable", value: i.checkmarx_data.fields.high_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_confirmed", value: i.checkmarx_data.fields.high_confirmed},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_urgent", value: i.checkmarx_data.fields.high_urgent},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_proposed_not_exploitable", value: i.checkmarx_data.fields.high_proposed_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "high_to_verify", value: i.checkmarx_data.fields.high_to_verify},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_issues", value: i.checkmarx_data.fields.medium_issues},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_not_false_positive", value: i.checkmarx_data.fields.medium_not_false_positive},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_not_exploitable", value: i.checkmarx_data.fields.medium_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_confirmed", value: i.checkmarx_data.fields.medium_confirmed},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_urgent", value: i.checkmarx_data.fields.medium_urgent},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_proposed_not_exploitable", value: i.checkmarx_data.fields.medium_proposed_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "medium_to_verify", value: i.checkmarx_data.fields.medium_to_verify},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_issues", value: i.checkmarx_data.fields.low_issues},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_not_false_positive", value: i.checkmarx_data.fields.low_not_false_positive},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_not_exploitable", value: i.checkmarx_data.fields.low_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_confirmed", value: i.checkmarx_data.fields.low_confirmed},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_urgent", value: i.checkmarx_data.fields.low_urgent},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_proposed_not_exploitable", value: i.checkmarx_data.fields.low_proposed_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "low_to_verify", value: i.checkmarx_data.fields.low_to_verify},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_issues", value: i.checkmarx_data.fields.information_issues},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_not_false_positive", value: i.checkmarx_data.fields.information_not_false_positive},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_not_exploitable", value: i.checkmarx_data.fields.information_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_confirmed", value: i.checkmarx_data.fields.information_confirmed},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_urgent", value: i.checkmarx_data.fields.information_urgent},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_proposed_not_exploitable", value: i.checkmarx_data.fields.information_proposed_not_exploitable},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "information_to_verify", value: i.checkmarx_data.fields.information_to_verify},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "lines_of_code_scanned", value: i.checkmarx_data.fields.lines_of_code_scanned},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "files_scanned", value: i.checkmarx_data.fields.files_scanned},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "initiator_name", value: i.checkmarx_data.fields.initiator_name},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "owner", value: i.checkmarx_data.fields.owner},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "scan_id", value: i.checkmarx_data.fields.scan_id},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "project_id", value: i.checkmarx_data.fields.project_id},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "projectName", value: i.checkmarx_data.fields.projectName},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "team", value: i.checkmarx_data.fields.team},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "team_full_path_on_report_date", value: i.checkmarx_data.fields.team_full_path_on_report_date},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "scan_start", value: i.checkmarx_data.fields.scan_start},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "scan_time", value: i.checkmarx_data.fields.scan_time},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "checkmarx_version", value: i.checkmarx_data.fields.checkmarx_version},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "scan_type", value: i.checkmarx_data.fields.scan_type},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "preset", value: i.checkmarx_data.fields.preset},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "deep_link", value: i.checkmarx_data.fields.deep_link},
-		{valType: config.InfluxField, measurement: "checkmarx_data", name: "report_creation_time", value: i.checkmarx_data.fields.report_creation_time},
+func (i *checkmarxExecuteScanInflux) measurements() []metrics.InfluxValue {
+	return []metrics.InfluxValue{
+		{ValType: config.InfluxField, Measurement: "step_data", Name: "checkmarx", Value: i.step_data.fields.checkmarx},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_issues", Value: i.checkmarx_data.fields.high_issues},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_not_false_positive", Value: i.checkmarx_data.fields.high_not_false_positive},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_not_exploitable", Value: i.checkmarx_data.fields.high_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_confirmed", Value: i.checkmarx_data.fields.high_confirmed},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_urgent", Value: i.checkmarx_data.fields.high_urgent},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_proposed_not_exploitable", Value: i.checkmarx_data.fields.high_proposed_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_to_verify", Value: i.checkmarx_data.fields.high_to_verify},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_issues", Value: i.checkmarx_data.fields.medium_issues},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_not_false_positive", Value: i.checkmarx_data.fields.medium_not_false_positive},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_not_exploitable", Value: i.checkmarx_data.fields.medium_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_confirmed", Value: i.checkmarx_data.fields.medium_confirmed},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_urgent", Value: i.checkmarx_data.fields.medium_urgent},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_proposed_not_exploitable", Value: i.checkmarx_data.fields.medium_proposed_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "medium_to_verify", Value: i.checkmarx_data.fields.medium_to_verify},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_issues", Value: i.checkmarx_data.fields.low_issues},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_not_false_positive", Value: i.checkmarx_data.fields.low_not_false_positive},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_not_exploitable", Value: i.checkmarx_data.fields.low_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_confirmed", Value: i.checkmarx_data.fields.low_confirmed},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_urgent", Value: i.checkmarx_data.fields.low_urgent},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_proposed_not_exploitable", Value: i.checkmarx_data.fields.low_proposed_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "low_to_verify", Value: i.checkmarx_data.fields.low_to_verify},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_issues", Value: i.checkmarx_data.fields.information_issues},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_not_false_positive", Value: i.checkmarx_data.fields.information_not_false_positive},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_not_exploitable", Value: i.checkmarx_data.fields.information_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_confirmed", Value: i.checkmarx_data.fields.information_confirmed},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_urgent", Value: i.checkmarx_data.fields.information_urgent},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_proposed_not_exploitable", Value: i.checkmarx_data.fields.information_proposed_not_exploitable},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "information_to_verify", Value: i.checkmarx_data.fields.information_to_verify},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "lines_of_code_scanned", Value: i.checkmarx_data.fields.lines_of_code_scanned},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "files_scanned", Value: i.checkmarx_data.fields.files_scanned},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "initiator_name", Value: i.checkmarx_data.fields.initiator_name},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "owner", Value: i.checkmarx_data.fields.owner},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "scan_id", Value: i.checkmarx_data.fields.scan_id},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "project_id", Value: i.checkmarx_data.fields.project_id},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "projectName", Value: i.checkmarx_data.fields.projectName},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "team", Value: i.checkmarx_data.fields.team},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "team_full_path_on_report_date", Value: i.checkmarx_data.fields.team_full_path_on_report_date},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "scan_start", Value: i.checkmarx_data.fields.scan_start},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "scan_time", Value: i.checkmarx_data.fields.scan_time},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "checkmarx_version", Value: i.checkmarx_data.fields.checkmarx_version},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "scan_type", Value: i.checkmarx_data.fields.scan_type},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "preset", Value: i.checkmarx_data.fields.preset},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "deep_link", Value: i.checkmarx_data.fields.deep_link},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "report_creation_time", Value: i.checkmarx_data.fields.report_creation_time},
 	}
+}
 
+func (i *checkmarxExecuteScanInflux) persist(path, resourceName string) {
 	errCount := 0
-	for _, metric := range measurementContent {
-		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+	for _, metric := range i.measurements() {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.Measurement, fmt.Sprintf("%vs", metric.ValType), metric.Name), metric.Value)
 		if err != nil {
 			log.Entry().WithError(err).Error("Error persisting influx environment.")
 			errCount++
@@ -285,6 +284,13 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
 			}
 
+			if err = tracing.Initialize(GeneralConfig.CorrelationID,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Endpoint,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Token,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.File); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up OpenTelemetry tracing")
+			}
+
 			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
 			if err != nil {
 				return err
@@ -324,10 +330,23 @@ thresholds instead of ` + "`" + `percentage` + "`" + ` whereas we strongly recom
 						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 					splunkClient.Send(telemetryClient.GetData(), logCollector)
 				}
+				stepMetrics := metrics.StepData{
+					StepName:      STEP_NAME,
+					StageName:     GeneralConfig.StageName,
+					Duration:      time.Since(startTime),
+					ErrorCode:     stepTelemetryData.ErrorCode,
+					ErrorCategory: stepTelemetryData.ErrorCategory,
+				}
+				stepMetrics.Measurements = append(stepMetrics.Measurements, influx.measurements()...)
+				metrics.Send(stepMetrics,
+					metrics.NewPushgatewaySink(GeneralConfig.HookConfig.PrometheusConfig.Pushgateway, GeneralConfig.HookConfig.PrometheusConfig.Job),
+					metrics.NewOTLPSink(GeneralConfig.HookConfig.OpenTelemetryConfig.MetricsEndpoint, GeneralConfig.HookConfig.OpenTelemetryConfig.Token))
+				tracing.EndStepSpan(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			tracing.StartStepSpan(STEP_NAME, GeneralConfig.StageName)
 			checkmarxExecuteScan(stepConfig, &stepTelemetryData, &influx)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
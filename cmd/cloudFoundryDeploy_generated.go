@@ -10,9 +10,11 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/metrics"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/spf13/cobra"
 )
@@ -67,28 +69,25 @@ type cloudFoundryDeployInflux struct {
 	}
 }
 
-func (i *cloudFoundryDeployInflux) persist(path, resourceName string) {
-	measurementContent := []struct {
-		measurement string
-		valType     string
-		name        string
-		value       interface{}
-	}{
-		{valType: config.InfluxField, measurement: "deployment_data", name: "artifactUrl", value: i.deployment_data.fields.artifactURL},
-		{valType: config.InfluxField, measurement: "deployment_data", name: "deployTime", value: i.deployment_data.fields.deployTime},
-		{valType: config.InfluxField, measurement: "deployment_data", name: "commitHash", value: i.deployment_data.fields.commitHash},
-		{valType: config.InfluxField, measurement: "deployment_data", name: "jobTrigger", value: i.deployment_data.fields.jobTrigger},
-		{valType: config.InfluxTag, measurement: "deployment_data", name: "artifactVersion", value: i.deployment_data.tags.artifactVersion},
-		{valType: config.InfluxTag, measurement: "deployment_data", name: "deployUser", value: i.deployment_data.tags.deployUser},
-		{valType: config.InfluxTag, measurement: "deployment_data", name: "deployResult", value: i.deployment_data.tags.deployResult},
-		{valType: config.InfluxTag, measurement: "deployment_data", name: "cfApiEndpoint", value: i.deployment_data.tags.cfAPIEndpoint},
-		{valType: config.InfluxTag, measurement: "deployment_data", name: "cfOrg", value: i.deployment_data.tags.cfOrg},
-		{valType: config.InfluxTag, measurement: "deployment_data", name: "cfSpace", value: i.deployment_data.tags.cfSpace},
+func (i *cloudFoundryDeployInflux) measurements() []metrics.InfluxValue {
+	return []metrics.InfluxValue{
+		{ValType: config.InfluxField, Measurement: "deployment_data", Name: "artifactUrl", Value: i.deployment_data.fields.artifactURL},
+		{ValType: config.InfluxField, Measurement: "deployment_data", Name: "deployTime", Value: i.deployment_data.fields.deployTime},
+		{ValType: config.InfluxField, Measurement: "deployment_data", Name: "commitHash", Value: i.deployment_data.fields.commitHash},
+		{ValType: config.InfluxField, Measurement: "deployment_data", Name: "jobTrigger", Value: i.deployment_data.fields.jobTrigger},
+		{ValType: config.InfluxTag, Measurement: "deployment_data", Name: "artifactVersion", Value: i.deployment_data.tags.artifactVersion},
+		{ValType: config.InfluxTag, Measurement: "deployment_data", Name: "deployUser", Value: i.deployment_data.tags.deployUser},
+		{ValType: config.InfluxTag, Measurement: "deployment_data", Name: "deployResult", Value: i.deployment_data.tags.deployResult},
+		{ValType: config.InfluxTag, Measurement: "deployment_data", Name: "cfApiEndpoint", Value: i.deployment_data.tags.cfAPIEndpoint},
+		{ValType: config.InfluxTag, Measurement: "deployment_data", Name: "cfOrg", Value: i.deployment_data.tags.cfOrg},
+		{ValType: config.InfluxTag, Measurement: "deployment_data", Name: "cfSpace", Value: i.deployment_data.tags.cfSpace},
 	}
+}
 
+func (i *cloudFoundryDeployInflux) persist(path, resourceName string) {
 	errCount := 0
-	for _, metric := range measurementContent {
-		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+	for _, metric := range i.measurements() {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.Measurement, fmt.Sprintf("%vs", metric.ValType), metric.Name), metric.Value)
 		if err != nil {
 			log.Entry().WithError(err).Error("Error persisting influx environment.")
 			errCount++
@@ -151,6 +150,13 @@ func CloudFoundryDeployCommand() *cobra.Command {
 				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
 			}
 
+			if err = tracing.Initialize(GeneralConfig.CorrelationID,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Endpoint,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Token,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.File); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up OpenTelemetry tracing")
+			}
+
 			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
 			if err != nil {
 				return err
@@ -189,10 +195,23 @@ func CloudFoundryDeployCommand() *cobra.Command {
 						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 					splunkClient.Send(telemetryClient.GetData(), logCollector)
 				}
+				stepMetrics := metrics.StepData{
+					StepName:      STEP_NAME,
+					StageName:     GeneralConfig.StageName,
+					Duration:      time.Since(startTime),
+					ErrorCode:     stepTelemetryData.ErrorCode,
+					ErrorCategory: stepTelemetryData.ErrorCategory,
+				}
+				stepMetrics.Measurements = append(stepMetrics.Measurements, influx.measurements()...)
+				metrics.Send(stepMetrics,
+					metrics.NewPushgatewaySink(GeneralConfig.HookConfig.PrometheusConfig.Pushgateway, GeneralConfig.HookConfig.PrometheusConfig.Job),
+					metrics.NewOTLPSink(GeneralConfig.HookConfig.OpenTelemetryConfig.MetricsEndpoint, GeneralConfig.HookConfig.OpenTelemetryConfig.Token))
+				tracing.EndStepSpan(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			tracing.StartStepSpan(STEP_NAME, GeneralConfig.StageName)
 			cloudFoundryDeploy(stepConfig, &stepTelemetryData, &influx)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
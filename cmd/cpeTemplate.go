@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/pkg/errors"
+)
+
+// templateStepConfig is the configuration of the running step, it provides the Vault settings for reading secrets in templates
+var templateStepConfig config.StepConfig
+
+// cpeTemplateOptions returns the options for rendering CPE templates with access to the workspace, Vault and the orchestrator
+func cpeTemplateOptions(readFile func(path string) ([]byte, error), strict bool) piperenv.TemplateOptions {
+	options := piperenv.TemplateOptions{
+		Strict:   strict,
+		ReadFile: readFile,
+	}
+	if provider, err := orchestrator.NewOrchestratorSpecificConfigProvider(); err == nil {
+		options.Orchestrator = provider
+	} else {
+		log.Entry().WithError(err).Debug("orchestrator information not available in templates")
+	}
+	if serverURL, _ := templateStepConfig.Config["vaultServerUrl"].(string); len(serverURL) > 0 {
+		options.SecretReader = &templateVaultClient{stepConfig: templateStepConfig}
+	}
+	return options
+}
+
+// templateVaultClient creates the Vault client on first use, thus templates without secrets do not require a Vault login
+type templateVaultClient struct {
+	stepConfig config.StepConfig
+	once       sync.Once
+	client     config.VaultClient
+	err        error
+}
+
+func (t *templateVaultClient) GetKvSecret(path string) (map[string]string, error) {
+	t.once.Do(func() {
+		credentials := config.VaultCredentials{AppRoleID: GeneralConfig.VaultRoleID, AppRoleSecretID: GeneralConfig.VaultRoleSecretID, VaultToken: GeneralConfig.VaultToken}
+		t.client, t.err = config.GetVaultClientFromConfig(t.stepConfig, credentials)
+		if t.err == nil && t.client == nil {
+			t.err = errors.New("no Vault credentials available, provide a Vault token or AppRole credentials")
+		}
+	})
+	if t.err != nil {
+		return nil, errors.Wrap(t.err, "failed to create Vault client")
+	}
+	return t.client.GetKvSecret(path)
+}
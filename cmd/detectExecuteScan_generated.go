@@ -13,9 +13,11 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/gcs"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/metrics"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/bmatcuk/doublestar"
 	"github.com/spf13/cobra"
@@ -82,24 +84,21 @@ type detectExecuteScanInflux struct {
 	}
 }
 
-func (i *detectExecuteScanInflux) persist(path, resourceName string) {
-	measurementContent := []struct {
-		measurement string
-		valType     string
-		name        string
-		value       interface{}
-	}{
-		{valType: config.InfluxField, measurement: "step_data", name: "detect", value: i.step_data.fields.detect},
-		{valType: config.InfluxField, measurement: "detect_data", name: "vulnerabilities", value: i.detect_data.fields.vulnerabilities},
-		{valType: config.InfluxField, measurement: "detect_data", name: "major_vulnerabilities", value: i.detect_data.fields.major_vulnerabilities},
-		{valType: config.InfluxField, measurement: "detect_data", name: "minor_vulnerabilities", value: i.detect_data.fields.minor_vulnerabilities},
-		{valType: config.InfluxField, measurement: "detect_data", name: "components", value: i.detect_data.fields.components},
-		{valType: config.InfluxField, measurement: "detect_data", name: "policy_violations", value: i.detect_data.fields.policy_violations},
+func (i *detectExecuteScanInflux) measurements() []metrics.InfluxValue {
+	return []metrics.InfluxValue{
+		{ValType: config.InfluxField, Measurement: "step_data", Name: "detect", Value: i.step_data.fields.detect},
+		{ValType: config.InfluxField, Measurement: "detect_data", Name: "vulnerabilities", Value: i.detect_data.fields.vulnerabilities},
+		{ValType: config.InfluxField, Measurement: "detect_data", Name: "major_vulnerabilities", Value: i.detect_data.fields.major_vulnerabilities},
+		{ValType: config.InfluxField, Measurement: "detect_data", Name: "minor_vulnerabilities", Value: i.detect_data.fields.minor_vulnerabilities},
+		{ValType: config.InfluxField, Measurement: "detect_data", Name: "components", Value: i.detect_data.fields.components},
+		{ValType: config.InfluxField, Measurement: "detect_data", Name: "policy_violations", Value: i.detect_data.fields.policy_violations},
 	}
+}
 
+func (i *detectExecuteScanInflux) persist(path, resourceName string) {
 	errCount := 0
-	for _, metric := range measurementContent {
-		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+	for _, metric := range i.measurements() {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.Measurement, fmt.Sprintf("%vs", metric.ValType), metric.Name), metric.Value)
 		if err != nil {
 			log.Entry().WithError(err).Error("Error persisting influx environment.")
 			errCount++
@@ -208,6 +207,13 @@ Please configure your BlackDuck server Url using the serverUrl parameter and the
 				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
 			}
 
+			if err = tracing.Initialize(GeneralConfig.CorrelationID,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Endpoint,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Token,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.File); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up OpenTelemetry tracing")
+			}
+
 			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
 			if err != nil {
 				return err
@@ -247,10 +253,23 @@ Please configure your BlackDuck server Url using the serverUrl parameter and the
 						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 					splunkClient.Send(telemetryClient.GetData(), logCollector)
 				}
+				stepMetrics := metrics.StepData{
+					StepName:      STEP_NAME,
+					StageName:     GeneralConfig.StageName,
+					Duration:      time.Since(startTime),
+					ErrorCode:     stepTelemetryData.ErrorCode,
+					ErrorCategory: stepTelemetryData.ErrorCategory,
+				}
+				stepMetrics.Measurements = append(stepMetrics.Measurements, influx.measurements()...)
+				metrics.Send(stepMetrics,
+					metrics.NewPushgatewaySink(GeneralConfig.HookConfig.PrometheusConfig.Pushgateway, GeneralConfig.HookConfig.PrometheusConfig.Job),
+					metrics.NewOTLPSink(GeneralConfig.HookConfig.OpenTelemetryConfig.MetricsEndpoint, GeneralConfig.HookConfig.OpenTelemetryConfig.Token))
+				tracing.EndStepSpan(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			tracing.StartStepSpan(STEP_NAME, GeneralConfig.StageName)
 			detectExecuteScan(stepConfig, &stepTelemetryData, &influx)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
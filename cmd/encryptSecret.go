@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type encryptSecretCommandOptions struct {
+	secretName      string
+	field           string
+	secretStoreFile string
+	generateKey     bool
+	stdin           io.Reader
+	stdout          io.Writer
+}
+
+var encryptSecretOptions encryptSecretCommandOptions
+
+// EncryptSecretCommand is the entry command for adding encrypted values to the secret store used by the secret provider 'file'
+func EncryptSecretCommand() *cobra.Command {
+	encryptSecretOptions.stdin = os.Stdin
+	encryptSecretOptions.stdout = os.Stdout
+	var encryptSecretCmd = &cobra.Command{
+		Use:   "encryptSecret",
+		Short: "Encrypts a secret value and adds it to the secret store used by the secret provider 'file'.",
+		Long: `Encrypts the value read from stdin with the key provided via the environment variable PIPER_SECRET_STORE_KEY
+and sets it as field of a secret in the secret store (default .pipeline/secrets.yml). The secret store is created if it does not exist.
+A new key is generated via --generateKey.
+
+Example:
+  export PIPER_SECRET_STORE_KEY=$(piper encryptSecret --generateKey)
+  printf '%s' "$SONAR_TOKEN" | piper encryptSecret --secret sonar --field token`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			log.SetVerbose(GeneralConfig.Verbose)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := &piperutils.Files{}
+			if err := runEncryptSecret(utils); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Fatal("failed to encrypt secret")
+			}
+		},
+	}
+	addEncryptSecretFlags(encryptSecretCmd)
+	return encryptSecretCmd
+}
+
+func runEncryptSecret(utils piperutils.FileUtils) error {
+	if encryptSecretOptions.generateKey {
+		key, err := config.GenerateSecretStoreKey()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(encryptSecretOptions.stdout, key)
+		return err
+	}
+
+	if len(encryptSecretOptions.secretName) == 0 || len(encryptSecretOptions.field) == 0 {
+		return errors.New("the name of the secret via --secret and the field via --field have to be provided")
+	}
+	key, err := config.SecretStoreKeyFromEnv()
+	if err != nil {
+		return err
+	}
+	value, err := io.ReadAll(encryptSecretOptions.stdin)
+	if err != nil {
+		return errors.Wrap(err, "failed to read value from stdin")
+	}
+	plainValue := strings.TrimRight(string(value), "\r\n")
+	if len(plainValue) == 0 {
+		return errors.New("no value provided via stdin")
+	}
+	encrypted, err := config.EncryptSecretValue(key, plainValue)
+	if err != nil {
+		return err
+	}
+
+	secrets := map[string]map[string]string{}
+	exists, err := utils.FileExists(encryptSecretOptions.secretStoreFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check for secret store '%v'", encryptSecretOptions.secretStoreFile)
+	}
+	if exists {
+		content, err := utils.FileRead(encryptSecretOptions.secretStoreFile)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read secret store '%v'", encryptSecretOptions.secretStoreFile)
+		}
+		if err := yaml.Unmarshal(content, &secrets); err != nil {
+			return errors.Wrapf(err, "failed to parse secret store '%v'", encryptSecretOptions.secretStoreFile)
+		}
+		if secrets == nil {
+			secrets = map[string]map[string]string{}
+		}
+	}
+	if secrets[encryptSecretOptions.secretName] == nil {
+		secrets[encryptSecretOptions.secretName] = map[string]string{}
+	}
+	secrets[encryptSecretOptions.secretName][encryptSecretOptions.field] = encrypted
+
+	content, err := yaml.Marshal(secrets)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal secret store")
+	}
+	if err := utils.FileWrite(encryptSecretOptions.secretStoreFile, content, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write secret store '%v'", encryptSecretOptions.secretStoreFile)
+	}
+	log.Entry().Infof("Field '%v' of secret '%v' written to secret store '%v'", encryptSecretOptions.field, encryptSecretOptions.secretName, encryptSecretOptions.secretStoreFile)
+	return nil
+}
+
+func addEncryptSecretFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&encryptSecretOptions.secretName, "secret", "", "Name of the secret, e.g. sonar")
+	cmd.Flags().StringVar(&encryptSecretOptions.field, "field", "", "Field of the secret, e.g. token")
+	cmd.Flags().StringVar(&encryptSecretOptions.secretStoreFile, "secretStoreFile", ".pipeline/secrets.yml", "Path to the secret store")
+	cmd.Flags().BoolVar(&encryptSecretOptions.generateKey, "generateKey", false, "Print a new base64 encoded key for the secret store")
+}
@@ -0,0 +1,109 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunEncryptSecret(t *testing.T) {
+	defer func() { encryptSecretOptions = encryptSecretCommandOptions{} }()
+	key := make([]byte, 32)
+	key[0] = 1
+
+	setup := func(value string) *bytes.Buffer {
+		stdout := &bytes.Buffer{}
+		encryptSecretOptions = encryptSecretCommandOptions{
+			secretName:      "sonar",
+			field:           "token",
+			secretStoreFile: ".pipeline/secrets.yml",
+			stdin:           strings.NewReader(value),
+			stdout:          stdout,
+		}
+		return stdout
+	}
+
+	readStore := func(t *testing.T, utils *mock.FilesMock) map[string]map[string]string {
+		content, err := utils.FileRead(".pipeline/secrets.yml")
+		assert.NoError(t, err)
+		secrets := map[string]map[string]string{}
+		assert.NoError(t, yaml.Unmarshal(content, &secrets))
+		return secrets
+	}
+
+	t.Run("create secret store", func(t *testing.T) {
+		t.Setenv(config.SecretStoreKeyEnv, base64.StdEncoding.EncodeToString(key))
+		setup("secretToken\n")
+		utils := &mock.FilesMock{}
+
+		err := runEncryptSecret(utils)
+
+		assert.NoError(t, err)
+		secrets := readStore(t, utils)
+		decrypted, err := config.DecryptSecretValue(key, secrets["sonar"]["token"])
+		assert.NoError(t, err)
+		assert.Equal(t, "secretToken", decrypted)
+	})
+
+	t.Run("add field to existing secret store", func(t *testing.T) {
+		t.Setenv(config.SecretStoreKeyEnv, base64.StdEncoding.EncodeToString(key))
+		setup("secretToken")
+		utils := &mock.FilesMock{}
+		utils.AddFile(".pipeline/secrets.yml", []byte("sonar:\n  user: ENC[AES256_GCM,data:AA==,iv:AA==]\n"))
+
+		err := runEncryptSecret(utils)
+
+		assert.NoError(t, err)
+		secrets := readStore(t, utils)
+		assert.Equal(t, "ENC[AES256_GCM,data:AA==,iv:AA==]", secrets["sonar"]["user"])
+		assert.Contains(t, secrets["sonar"]["token"], "ENC[AES256_GCM,")
+	})
+
+	t.Run("generate key", func(t *testing.T) {
+		stdout := setup("")
+		encryptSecretOptions.generateKey = true
+
+		err := runEncryptSecret(&mock.FilesMock{})
+
+		assert.NoError(t, err)
+		generated, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stdout.String()))
+		assert.NoError(t, err)
+		assert.Len(t, generated, 32)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Setenv(config.SecretStoreKeyEnv, "")
+		setup("secretToken")
+
+		err := runEncryptSecret(&mock.FilesMock{})
+
+		assert.EqualError(t, err, "environment variable PIPER_SECRET_STORE_KEY is not set")
+	})
+
+	t.Run("missing value", func(t *testing.T) {
+		t.Setenv(config.SecretStoreKeyEnv, base64.StdEncoding.EncodeToString(key))
+		setup("\n")
+
+		err := runEncryptSecret(&mock.FilesMock{})
+
+		assert.EqualError(t, err, "no value provided via stdin")
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		setup("secretToken")
+		encryptSecretOptions.field = ""
+
+		err := runEncryptSecret(&mock.FilesMock{})
+
+		assert.EqualError(t, err, "the name of the secret via --secret and the field via --field have to be provided")
+	})
+}
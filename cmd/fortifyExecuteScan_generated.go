@@ -13,9 +13,11 @@ import (
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/gcs"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/metrics"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/splunk"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/SAP/jenkins-library/pkg/validation"
 	"github.com/bmatcuk/doublestar"
 	"github.com/spf13/cobra"
@@ -115,34 +117,31 @@ type fortifyExecuteScanInflux struct {
 	}
 }
 
-func (i *fortifyExecuteScanInflux) persist(path, resourceName string) {
-	measurementContent := []struct {
-		measurement string
-		valType     string
-		name        string
-		value       interface{}
-	}{
-		{valType: config.InfluxField, measurement: "step_data", name: "fortify", value: i.step_data.fields.fortify},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "projectID", value: i.fortify_data.fields.projectID},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "projectName", value: i.fortify_data.fields.projectName},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "projectVersion", value: i.fortify_data.fields.projectVersion},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "projectVersionId", value: i.fortify_data.fields.projectVersionID},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "violations", value: i.fortify_data.fields.violations},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "corporateTotal", value: i.fortify_data.fields.corporateTotal},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "corporateAudited", value: i.fortify_data.fields.corporateAudited},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "auditAllTotal", value: i.fortify_data.fields.auditAllTotal},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "auditAllAudited", value: i.fortify_data.fields.auditAllAudited},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "spotChecksTotal", value: i.fortify_data.fields.spotChecksTotal},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "spotChecksAudited", value: i.fortify_data.fields.spotChecksAudited},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "spotChecksGap", value: i.fortify_data.fields.spotChecksGap},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "suspicious", value: i.fortify_data.fields.suspicious},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "exploitable", value: i.fortify_data.fields.exploitable},
-		{valType: config.InfluxField, measurement: "fortify_data", name: "suppressed", value: i.fortify_data.fields.suppressed},
+func (i *fortifyExecuteScanInflux) measurements() []metrics.InfluxValue {
+	return []metrics.InfluxValue{
+		{ValType: config.InfluxField, Measurement: "step_data", Name: "fortify", Value: i.step_data.fields.fortify},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "projectID", Value: i.fortify_data.fields.projectID},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "projectName", Value: i.fortify_data.fields.projectName},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "projectVersion", Value: i.fortify_data.fields.projectVersion},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "projectVersionId", Value: i.fortify_data.fields.projectVersionID},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "violations", Value: i.fortify_data.fields.violations},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "corporateTotal", Value: i.fortify_data.fields.corporateTotal},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "corporateAudited", Value: i.fortify_data.fields.corporateAudited},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "auditAllTotal", Value: i.fortify_data.fields.auditAllTotal},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "auditAllAudited", Value: i.fortify_data.fields.auditAllAudited},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "spotChecksTotal", Value: i.fortify_data.fields.spotChecksTotal},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "spotChecksAudited", Value: i.fortify_data.fields.spotChecksAudited},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "spotChecksGap", Value: i.fortify_data.fields.spotChecksGap},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "suspicious", Value: i.fortify_data.fields.suspicious},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "exploitable", Value: i.fortify_data.fields.exploitable},
+		{ValType: config.InfluxField, Measurement: "fortify_data", Name: "suppressed", Value: i.fortify_data.fields.suppressed},
 	}
+}
 
+func (i *fortifyExecuteScanInflux) persist(path, resourceName string) {
 	errCount := 0
-	for _, metric := range measurementContent {
-		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+	for _, metric := range i.measurements() {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.Measurement, fmt.Sprintf("%vs", metric.ValType), metric.Name), metric.Value)
 		if err != nil {
 			log.Entry().WithError(err).Error("Error persisting influx environment.")
 			errCount++
@@ -256,6 +255,13 @@ Besides triggering a scan the step verifies the results after they have been upl
 				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
 			}
 
+			if err = tracing.Initialize(GeneralConfig.CorrelationID,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Endpoint,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.Token,
+				GeneralConfig.HookConfig.OpenTelemetryConfig.File); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up OpenTelemetry tracing")
+			}
+
 			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
 			if err != nil {
 				return err
@@ -295,10 +301,23 @@ Besides triggering a scan the step verifies the results after they have been upl
 						GeneralConfig.HookConfig.SplunkConfig.SendLogs)
 					splunkClient.Send(telemetryClient.GetData(), logCollector)
 				}
+				stepMetrics := metrics.StepData{
+					StepName:      STEP_NAME,
+					StageName:     GeneralConfig.StageName,
+					Duration:      time.Since(startTime),
+					ErrorCode:     stepTelemetryData.ErrorCode,
+					ErrorCategory: stepTelemetryData.ErrorCategory,
+				}
+				stepMetrics.Measurements = append(stepMetrics.Measurements, influx.measurements()...)
+				metrics.Send(stepMetrics,
+					metrics.NewPushgatewaySink(GeneralConfig.HookConfig.PrometheusConfig.Pushgateway, GeneralConfig.HookConfig.PrometheusConfig.Job),
+					metrics.NewOTLPSink(GeneralConfig.HookConfig.OpenTelemetryConfig.MetricsEndpoint, GeneralConfig.HookConfig.OpenTelemetryConfig.Token))
+				tracing.EndStepSpan(stepTelemetryData.ErrorCode, stepTelemetryData.ErrorCategory)
 			}
 			log.DeferExitHandler(handler)
 			defer handler()
 			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			tracing.StartStepSpan(STEP_NAME, GeneralConfig.StageName)
 			fortifyExecuteScan(stepConfig, &stepTelemetryData, &influx)
 			stepTelemetryData.ErrorCode = "0"
 			log.Entry().Info("SUCCESS")
@@ -25,6 +25,7 @@ type configCommandOptions struct {
 	stepMetadata                  string // metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
 	stepName                      string
 	contextConfig                 bool
+	explain                       bool // if set: output the sources of all configuration values instead of the configuration
 	openFile                      func(s string, t map[string]string) (io.ReadCloser, error)
 }
 
@@ -222,9 +223,14 @@ func generateConfig(utils getConfigUtils) error {
 		return err
 	}
 
-	myConfigJSON, err := config.GetJSON(stepConfig.Config)
-	if err != nil {
-		return fmt.Errorf("failed to get JSON from config: %w", err)
+	var myConfigJSON string
+	if configOptions.explain {
+		myConfigJSON = stepConfig.Explain()
+	} else {
+		myConfigJSON, err = config.GetJSON(stepConfig.Config)
+		if err != nil {
+			return fmt.Errorf("failed to get JSON from config: %w", err)
+		}
 	}
 
 	if len(configOptions.outputFile) > 0 {
@@ -251,6 +257,7 @@ func addConfigFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&configOptions.stepMetadata, "stepMetadata", "", "Step metadata, passed as path to yaml")
 	cmd.Flags().StringVar(&configOptions.stepName, "stepName", "", "Step name, used to get step metadata if yaml path is not set")
 	cmd.Flags().BoolVar(&configOptions.contextConfig, "contextConfig", false, "Defines if step context configuration should be loaded instead of step config")
+	cmd.Flags().BoolVar(&configOptions.explain, "explain", false, "Defines if the source of each configuration value (step metadata, defaults, config file section, alias, Vault, environment, flag) should be printed instead of the configuration")
 
 }
 
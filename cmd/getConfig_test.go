@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
@@ -51,7 +52,7 @@ func TestConfigCommand(t *testing.T) {
 	})
 
 	t.Run("Optional flags", func(t *testing.T) {
-		exp := []string{"contextConfig", "output", "outputFile", "parametersJSON", "stageConfig", "stageConfigAcceptedParams", "stepMetadata", "stepName"}
+		exp := []string{"contextConfig", "explain", "output", "outputFile", "parametersJSON", "stageConfig", "stageConfigAcceptedParams", "stepMetadata", "stepName"}
 		assert.Equal(t, exp, gotOpt, "optional flags incorrect")
 	})
 
@@ -309,3 +310,23 @@ func TestPrepareOutputEnvironment(t *testing.T) {
 	assert.NoDirExists(t, filepath.Join(dir, "influx", "measurement0", "influx0_0"))
 	assert.NoDirExists(t, filepath.Join(dir, "influx", "measurement1", "influx0_1"))
 }
+
+func TestGenerateConfig(t *testing.T) {
+	configOptions.openFile = configOpenFileMock
+	configOptions.stepName = "githubCreateIssue"
+	configOptions.outputFile = "config.out"
+	defer func() { configOptions.outputFile = "" }()
+
+	t.Run("explain", func(t *testing.T) {
+		configOptions.explain = true
+		defer func() { configOptions.explain = false }()
+		utils := &mock.FilesMock{}
+
+		err := generateConfig(utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("config.out")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "apiUrl: https://api.github.com\n  - stepMetadata (effective)\n")
+	})
+}
@@ -485,7 +485,7 @@ func prepareLdflags(config *golangBuildOptions, utils golangBuildUtils, envRootP
 	}
 
 	log.Entry().Debugf("ldflagsTemplate in use: %v", config.LdflagsTemplate)
-	return cpe.ParseTemplate(config.LdflagsTemplate)
+	return cpe.ParseTemplateWithOptions(config.LdflagsTemplate, cpeTemplateOptions(utils.FileRead, config.LdflagsTemplateStrict))
 }
 
 func runGolangBuildPerArchitecture(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils, ldflags string, architecture multiarch.Platform) ([]string, error) {
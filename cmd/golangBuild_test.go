@@ -721,6 +721,21 @@ func TestPrepareLdflags(t *testing.T) {
 		assert.Equal(t, "-X version=1.2.3", (*result).String())
 	})
 
+	t.Run("success - template functions", func(t *testing.T) {
+		config := golangBuildOptions{LdflagsTemplate: "-X version={{ bumpMinor .CPE.artifactVersion | quote }}"}
+		utils := newGolangBuildTestsUtils()
+		result, err := prepareLdflags(&config, utils, dir)
+		assert.NoError(t, err)
+		assert.Equal(t, `-X version="1.3.0"`, (*result).String())
+	})
+
+	t.Run("error - strict template with missing value", func(t *testing.T) {
+		config := golangBuildOptions{LdflagsTemplate: "-X commit={{ .CPE.git_commitId }}", LdflagsTemplateStrict: true}
+		utils := newGolangBuildTestsUtils()
+		_, err := prepareLdflags(&config, utils, dir)
+		assert.Contains(t, fmt.Sprint(err), `map has no entry for key "git_commitId"`)
+	})
+
 	t.Run("error - template parsing", func(t *testing.T) {
 		config := golangBuildOptions{LdflagsTemplate: "-X version={{ .CPE.artifactVersion "}
 		utils := newGolangBuildTestsUtils()
@@ -170,12 +170,16 @@ func parseAndRenderCPETemplate(config helmExecuteOptions, rootPath string, utils
 	}
 	valueFiles = append(valueFiles, config.HelmValues...)
 
+	templateOptions := cpeTemplateOptions(utils.FileRead, config.TemplateStrict)
+	templateOptions.StartDelimiter = config.TemplateStartDelimiter
+	templateOptions.EndDelimiter = config.TemplateEndDelimiter
+
 	for _, valueFile := range valueFiles {
 		cpeTemplate, err := utils.FileRead(valueFile)
 		if err != nil {
 			return fmt.Errorf("failed to read file: %v", err)
 		}
-		generated, err := cpe.ParseTemplateWithDelimiter(string(cpeTemplate), config.TemplateStartDelimiter, config.TemplateEndDelimiter)
+		generated, err := cpe.ParseTemplateWithOptions(string(cpeTemplate), templateOptions)
 		if err != nil {
 			return fmt.Errorf("failed to parse template: %v", err)
 		}
@@ -433,6 +433,13 @@ tag: {{ imageTag "test-image" }}
 			expectedErr:      fmt.Errorf("failed to parse template: failed to parse cpe template '\nimage: \"image_3\"\ntag: {{ .CPE.artVersion\n': template: cpetemplate:4: unclosed action started at cpetemplate:3"),
 			valueFile:        values3Yaml,
 		},
+		{
+			name:             "Strict template with missing value",
+			defaultValueFile: defaultValueFile,
+			config:           helmExecuteOptions{ChartPath: ".", TemplateStrict: true},
+			expectedErr:      fmt.Errorf("failed to parse template: failed to execute cpe template '\nimage: \"image_2\"\ntag: {{ cpe \"artVersion\" }}\n': template: cpetemplate:3:8: executing \"cpetemplate\" at <cpe \"artVersion\">: error calling cpe: cpe: no value for 'artVersion'"),
+			valueFile:        values1Yaml,
+		},
 		{
 			name:             "Multiple value files",
 			defaultValueFile: defaultValueFile,
@@ -9,8 +9,11 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -20,39 +23,44 @@ import (
 
 // GeneralConfigOptions contains all global configuration options for piper binary
 type GeneralConfigOptions struct {
-	GitHubAccessTokens   map[string]string // map of tokens with url as key in order to maintain url-specific tokens
-	CorrelationID        string
-	CustomConfig         string
-	GitHubTokens         []string // list of entries in form of <server>:<token> to allow token authentication for downloading config / defaults
-	DefaultConfig        []string //ordered list of Piper default configurations. Can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
-	IgnoreCustomDefaults bool
-	ParametersJSON       string
-	EnvRootPath          string
-	NoTelemetry          bool
-	StageName            string
-	StepConfigJSON       string
-	StepMetadata         string //metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
-	StepName             string
-	Verbose              bool
-	LogFormat            string
-	VaultRoleID          string
-	VaultRoleSecretID    string
-	VaultToken           string
-	VaultServerURL       string
-	VaultNamespace       string
-	VaultPath            string
-	HookConfig           HookConfiguration
-	MetaDataResolver     func() map[string]config.StepData
-	GCPJsonKeyFilePath   string
-	GCSFolderPath        string
-	GCSBucketId          string
-	GCSSubFolder         string
+	GitHubAccessTokens    map[string]string // map of tokens with url as key in order to maintain url-specific tokens
+	CorrelationID         string
+	CustomConfig          string
+	GitHubTokens          []string // list of entries in form of <server>:<token> to allow token authentication for downloading config / defaults
+	DefaultConfig         []string //ordered list of Piper default configurations. Can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
+	IgnoreCustomDefaults  bool
+	ParametersJSON        string
+	EnvRootPath           string
+	NoTelemetry           bool
+	StageName             string
+	StepConfigJSON        string
+	StepMetadata          string //metadata to be considered, can be filePath or ENV containing JSON in format 'ENV:MY_ENV_VAR'
+	StepName              string
+	Verbose               bool
+	LogFormat             string
+	VaultRoleID           string
+	VaultRoleSecretID     string
+	VaultToken            string
+	VaultServerURL        string
+	VaultNamespace        string
+	VaultPath             string
+	HookConfig            HookConfiguration
+	MetaDataResolver      func() map[string]config.StepData
+	GCPJsonKeyFilePath    string
+	GCSFolderPath         string
+	GCSBucketId           string
+	GCSSubFolder          string
+	HTTPClientCertificate piperhttp.ClientCertificate
+	CommandTimeout        string
+	CommandOutputLimit    string
 }
 
-// HookConfiguration contains the configuration for supported hooks, so far Sentry and Splunk are supported.
+// HookConfiguration contains the configuration for supported hooks, so far Sentry, Splunk, OpenTelemetry and Prometheus are supported.
 type HookConfiguration struct {
-	SentryConfig SentryConfiguration `json:"sentry,omitempty"`
-	SplunkConfig SplunkConfiguration `json:"splunk,omitempty"`
+	SentryConfig        SentryConfiguration        `json:"sentry,omitempty"`
+	SplunkConfig        SplunkConfiguration        `json:"splunk,omitempty"`
+	OpenTelemetryConfig OpenTelemetryConfiguration `json:"openTelemetry,omitempty"`
+	PrometheusConfig    PrometheusConfiguration    `json:"prometheus,omitempty"`
 }
 
 // SentryConfiguration defines the configuration options for the Sentry logging system
@@ -71,6 +79,26 @@ type SplunkConfiguration struct {
 	ProdCriblIndex    string `json:"prodCriblIndex,omitempty"`
 }
 
+// OpenTelemetryConfiguration defines the configuration options for exporting OpenTelemetry spans
+type OpenTelemetryConfiguration struct {
+	// Endpoint of an OTLP/HTTP receiver, e.g. https://otel-collector:4318
+	Endpoint string `json:"endpoint,omitempty"`
+	// Token is sent as Authorization header to the endpoint
+	Token string `json:"token,omitempty"`
+	// File the spans are appended to for offline analysis
+	File string `json:"file,omitempty"`
+	// MetricsEndpoint of an OTLP/HTTP receiver for step metrics, e.g. https://otel-collector:4318/v1/metrics
+	MetricsEndpoint string `json:"metricsEndpoint,omitempty"`
+}
+
+// PrometheusConfiguration defines the configuration options for pushing step metrics to Prometheus
+type PrometheusConfiguration struct {
+	// Pushgateway is the URL of the Prometheus Pushgateway, e.g. https://pushgateway:9091
+	Pushgateway string `json:"pushgateway,omitempty"`
+	// Job is the name of the job the metrics are grouped by, piper by default
+	Job string `json:"job,omitempty"`
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "piper",
 	Short: "Executes CI/CD steps from project 'Piper' ",
@@ -89,6 +117,7 @@ func Execute() {
 	rootCmd.AddCommand(ArtifactPrepareVersionCommand())
 	rootCmd.AddCommand(ConfigCommand())
 	rootCmd.AddCommand(DefaultsCommand())
+	rootCmd.AddCommand(ValidateConfigCommand())
 	rootCmd.AddCommand(ContainerSaveImageCommand())
 	rootCmd.AddCommand(CommandLineCompletionCommand())
 	rootCmd.AddCommand(VersionCommand())
@@ -182,6 +211,8 @@ func Execute() {
 	rootCmd.AddCommand(InfluxWriteDataCommand())
 	rootCmd.AddCommand(AbapEnvironmentRunAUnitTestCommand())
 	rootCmd.AddCommand(CheckStepActiveCommand())
+	rootCmd.AddCommand(RunPipelineCommand())
+	rootCmd.AddCommand(EncryptSecretCommand())
 	rootCmd.AddCommand(GolangBuildCommand())
 	rootCmd.AddCommand(ShellExecuteCommand())
 	rootCmd.AddCommand(ApiProxyDownloadCommand())
@@ -230,7 +261,7 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.StepConfigJSON, "stepConfigJSON", os.Getenv("PIPER_stepConfigJSON"), "Step configuration in JSON format")
 	rootCmd.PersistentFlags().BoolVar(&GeneralConfig.NoTelemetry, "noTelemetry", false, "Disables telemetry reporting")
 	rootCmd.PersistentFlags().BoolVarP(&GeneralConfig.Verbose, "verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full.")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.LogFormat, "logFormat", "default", "Log format to use. Options: default, timestamp, plain, full, json.")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultServerURL, "vaultServerUrl", "", "The Vault server which should be used to fetch credentials")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultNamespace, "vaultNamespace", "", "The Vault namespace which should be used to fetch credentials")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.VaultPath, "vaultPath", "", "The path which should be used to fetch credentials")
@@ -238,6 +269,10 @@ func addRootFlags(rootCmd *cobra.Command) {
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSFolderPath, "gcsFolderPath", "", "GCS folder path. One of the components of GCS target folder")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSBucketId, "gcsBucketId", "", "Bucket name for Google Cloud Storage")
 	rootCmd.PersistentFlags().StringVar(&GeneralConfig.GCSSubFolder, "gcsSubFolder", "", "Used to logically separate results of the same step result type")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.HTTPClientCertificate.Certificate, "httpClientCertificate", "", "Path to the PEM or PKCS#12 client certificate used for mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.HTTPClientCertificate.Key, "httpClientKey", "", "Path to the PEM private key of the client certificate used for mutual TLS")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.CommandTimeout, "commandTimeout", "", "Maximum duration of each command executed by the step, e.g. '30m'")
+	rootCmd.PersistentFlags().StringVar(&GeneralConfig.CommandOutputLimit, "commandOutputLimit", "", "Maximum size of the output captured per output stream of each command executed by the step, e.g. '100MB'")
 
 }
 
@@ -326,6 +361,8 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	log.SetFormatter(GeneralConfig.LogFormat)
 
 	initStageName(true)
+	log.SetStageName(GeneralConfig.StageName)
+	log.SetCorrelationID(GeneralConfig.CorrelationID)
 
 	filters := metadata.GetParameterFilters()
 
@@ -334,6 +371,15 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	filters.General = append(filters.General, "collectTelemetryData")
 	filters.Parameters = append(filters.Parameters, "collectTelemetryData")
 
+	// add "commandTimeout", "commandOutputLimit" and "errorClassificationRules" to all filters, thus they can be configured in general as well as per step and stage
+	for _, name := range []string{"commandTimeout", "commandOutputLimit", "errorClassificationRules"} {
+		filters.All = append(filters.All, name)
+		filters.General = append(filters.General, name)
+		filters.Steps = append(filters.Steps, name)
+		filters.Stages = append(filters.Stages, name)
+		filters.Parameters = append(filters.Parameters, name)
+	}
+
 	envParams := metadata.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	reportingEnvParams := config.ReportingParameters.GetResourceParameters(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")
 	resourceParams := mergeResourceParameters(envParams, reportingEnvParams)
@@ -430,6 +476,40 @@ func PrepareConfig(cmd *cobra.Command, metadata *config.StepData, stepName strin
 	if GeneralConfig.GCSSubFolder == "" {
 		GeneralConfig.GCSSubFolder, _ = stepConfig.Config["gcsSubFolder"].(string)
 	}
+	if GeneralConfig.HTTPClientCertificate.Certificate == "" {
+		GeneralConfig.HTTPClientCertificate.Certificate, _ = stepConfig.Config["httpClientCertificate"].(string)
+	}
+	if GeneralConfig.HTTPClientCertificate.Key == "" {
+		GeneralConfig.HTTPClientCertificate.Key, _ = stepConfig.Config["httpClientKey"].(string)
+	}
+	if GeneralConfig.HTTPClientCertificate.Password == "" {
+		GeneralConfig.HTTPClientCertificate.Password, _ = stepConfig.Config["httpClientKeyPassword"].(string)
+	}
+	piperhttp.SetDefaultClientCertificate(GeneralConfig.HTTPClientCertificate)
+	templateStepConfig = stepConfig
+	if GeneralConfig.CommandTimeout == "" && stepConfig.Config["commandTimeout"] != nil {
+		GeneralConfig.CommandTimeout = fmt.Sprint(stepConfig.Config["commandTimeout"])
+	}
+	timeout, err := parseCommandTimeout(GeneralConfig.CommandTimeout)
+	if err != nil {
+		return err
+	}
+	command.SetDefaultTimeout(timeout)
+	if GeneralConfig.CommandOutputLimit == "" && stepConfig.Config["commandOutputLimit"] != nil {
+		GeneralConfig.CommandOutputLimit = fmt.Sprint(stepConfig.Config["commandOutputLimit"])
+	}
+	outputLimit, err := parseCommandOutputLimit(GeneralConfig.CommandOutputLimit)
+	if err != nil {
+		return err
+	}
+	command.SetDefaultOutputLimit(outputLimit)
+	rules, err := errorClassificationRules(stepConfig.Config["errorClassificationRules"])
+	if err != nil {
+		return err
+	}
+	if err := command.SetErrorClassificationRules(rules); err != nil {
+		return errors.Wrap(err, "invalid parameter errorClassificationRules")
+	}
 	return nil
 }
 
@@ -617,3 +697,54 @@ func mergeResourceParameters(resParams ...map[string]interface{}) map[string]int
 	}
 	return result
 }
+
+// parseCommandTimeout accepts a duration like "1h30m" or a number of seconds
+func parseCommandTimeout(value string) (time.Duration, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Errorf("invalid value '%v' for parameter commandTimeout, expected a duration like '30m' or a number of seconds", value)
+	}
+	return timeout, nil
+}
+
+// parseCommandOutputLimit accepts a size like "100MB" with the unit KB, MB or GB or a number of bytes
+func parseCommandOutputLimit(value string) (int64, error) {
+	if len(value) == 0 {
+		return 0, nil
+	}
+	size := strings.ToUpper(strings.TrimSpace(value))
+	unit := int64(1)
+	for suffix, factor := range map[string]int64{"KB": 1 << 10, "MB": 1 << 20, "GB": 1 << 30} {
+		if strings.HasSuffix(size, suffix) {
+			size, unit = strings.TrimSpace(strings.TrimSuffix(size, suffix)), factor
+			break
+		}
+	}
+	limit, err := strconv.ParseInt(size, 10, 64)
+	if err != nil || limit < 0 {
+		return 0, errors.Errorf("invalid value '%v' for parameter commandOutputLimit, expected a size like '100MB' or a number of bytes", value)
+	}
+	return limit * unit, nil
+}
+
+// errorClassificationRules converts the configured list of rules, each one with the keys pattern, category and hint
+func errorClassificationRules(value interface{}) ([]command.ErrorClassificationRule, error) {
+	rules := []command.ErrorClassificationRule{}
+	if value == nil {
+		return rules, nil
+	}
+	rulesJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid parameter errorClassificationRules")
+	}
+	if err := json.Unmarshal(rulesJSON, &rules); err != nil {
+		return nil, errors.Wrap(err, "invalid parameter errorClassificationRules, expected a list of rules with pattern, category and hint")
+	}
+	return rules, nil
+}
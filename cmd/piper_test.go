@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
@@ -19,6 +20,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/config"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -192,6 +194,76 @@ func TestRetrieveHookConfig(t *testing.T) {
 	}
 }
 
+func TestParseCommandTimeout(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected time.Duration
+		err      string
+	}{
+		{value: "", expected: 0},
+		{value: "1h30m", expected: 90 * time.Minute},
+		{value: "600", expected: 10 * time.Minute},
+		{value: "later", err: "invalid value 'later' for parameter commandTimeout, expected a duration like '30m' or a number of seconds"},
+	}
+
+	for _, test := range tt {
+		timeout, err := parseCommandTimeout(test.value)
+		if len(test.err) > 0 {
+			assert.EqualError(t, err, test.err)
+		} else if assert.NoError(t, err) {
+			assert.Equal(t, test.expected, timeout, test.value)
+		}
+	}
+}
+
+func TestParseCommandOutputLimit(t *testing.T) {
+	tt := []struct {
+		value    string
+		expected int64
+		err      string
+	}{
+		{value: "", expected: 0},
+		{value: "2048", expected: 2048},
+		{value: "100MB", expected: 100 << 20},
+		{value: "1 gb", expected: 1 << 30},
+		{value: "much", err: "invalid value 'much' for parameter commandOutputLimit, expected a size like '100MB' or a number of bytes"},
+	}
+
+	for _, test := range tt {
+		limit, err := parseCommandOutputLimit(test.value)
+		if len(test.err) > 0 {
+			assert.EqualError(t, err, test.err)
+		} else if assert.NoError(t, err) {
+			assert.Equal(t, test.expected, limit, test.value)
+		}
+	}
+}
+
+func TestErrorClassificationRules(t *testing.T) {
+	t.Run("rules from config", func(t *testing.T) {
+		value := []interface{}{
+			map[string]interface{}{"pattern": "npm ERR! code E401", "category": "config", "hint": "check the npm credentials"},
+		}
+
+		rules, err := errorClassificationRules(value)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []command.ErrorClassificationRule{{Pattern: "npm ERR! code E401", Category: "config", Hint: "check the npm credentials"}}, rules)
+		}
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		rules, err := errorClassificationRules(nil)
+		assert.NoError(t, err)
+		assert.Empty(t, rules)
+	})
+
+	t.Run("invalid rules", func(t *testing.T) {
+		_, err := errorClassificationRules("npm ERR!")
+		assert.Contains(t, err.Error(), "expected a list of rules with pattern, category and hint")
+	})
+}
+
 func TestGetProjectConfigFile(t *testing.T) {
 
 	tt := []struct {
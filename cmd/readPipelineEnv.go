@@ -2,16 +2,25 @@ package cmd
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"text/tabwriter"
+
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
-	"os"
-	"path"
 )
 
 // ReadPipelineEnv reads the commonPipelineEnvironment from disk and outputs it as JSON
 func ReadPipelineEnv() *cobra.Command {
-	return &cobra.Command{
+	var describe bool
+	var exportVariables []string
+	readPipelineEnvCmd := &cobra.Command{
 		Use:   "readPipelineEnv",
 		Short: "Reads the commonPipelineEnvironment from disk and outputs it as JSON",
 		PreRun: func(cmd *cobra.Command, args []string) {
@@ -21,12 +30,35 @@ func ReadPipelineEnv() *cobra.Command {
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
+			if describe {
+				describePipelineEnv(os.Stdout)
+				return
+			}
+			if len(exportVariables) > 0 {
+				if err := runExportPipelineEnv(exportVariables); err != nil {
+					log.Entry().Fatalf("error when exporting Pipeline environment: %v", err)
+				}
+				return
+			}
 			err := runReadPipelineEnv()
 			if err != nil {
 				log.Entry().Fatalf("error when writing reading Pipeline environment: %v", err)
 			}
 		},
 	}
+	readPipelineEnvCmd.Flags().BoolVar(&describe, "describe", false, "Lists the keys of the commonPipelineEnvironment written by the steps together with their type and the producing steps")
+	readPipelineEnvCmd.Flags().StringSliceVar(&exportVariables, "exportVariables", nil, "Exports keys of the commonPipelineEnvironment as variables of the orchestrator instead of printing JSON, entries have the format <key>=<variable name>")
+	return readPipelineEnvCmd
+}
+
+// describePipelineEnv lists the known keys of the commonPipelineEnvironment
+func describePipelineEnv(out io.Writer) {
+	writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "KEY\tTYPE\tPRODUCED BY")
+	for _, key := range piperenv.KnownCPEKeys() {
+		fmt.Fprintf(writer, "%v\t%v\t%v\n", key.Name, key.Type, strings.Join(key.Producers, ", "))
+	}
+	writer.Flush()
 }
 
 func runReadPipelineEnv() error {
@@ -45,3 +77,32 @@ func runReadPipelineEnv() error {
 
 	return nil
 }
+
+func runExportPipelineEnv(mappingEntries []string) error {
+	mapping, err := piperenv.ParseVariableMapping(mappingEntries)
+	if err != nil {
+		return err
+	}
+	variables, err := orchestratorVariables()
+	if err != nil {
+		return err
+	}
+	cpe := piperenv.CPEMap{}
+	if err := cpe.LoadFromDisk(path.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")); err != nil {
+		return err
+	}
+	return cpe.ExportVariables(mapping, variables)
+}
+
+// orchestratorVariables returns the provider of the current orchestrator in case it supports native variables
+func orchestratorVariables() (orchestrator.VariableProviding, error) {
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		return nil, err
+	}
+	variables, ok := provider.(orchestrator.VariableProviding)
+	if !ok {
+		return nil, errors.Errorf("variables are not supported on orchestrator %v, only on Azure DevOps and GitHub Actions", provider.OrchestratorType())
+	}
+	return variables, nil
+}
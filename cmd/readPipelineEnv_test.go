@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribePipelineEnv(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	describePipelineEnv(out)
+
+	assert.Regexp(t, `^KEY\s+TYPE\s+PRODUCED BY\n`, out.String())
+	assert.Regexp(t, `\ncontainer/imageNameTags\s+\[\]string\s+cnbBuild, kanikoExecute\n`, out.String())
+}
+
+func TestExportAndImportPipelineEnv(t *testing.T) {
+	defer func(envRootPath string) { GeneralConfig.EnvRootPath = envRootPath }(GeneralConfig.EnvRootPath)
+	t.Setenv("PIPER_ORCHESTRATOR_MAPPING", "")
+	t.Setenv("AZURE_HTTP_USER_AGENT", "")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	outputFile := filepath.Join(t.TempDir(), "output")
+	t.Setenv("GITHUB_OUTPUT", outputFile)
+
+	t.Run("export", func(t *testing.T) {
+		GeneralConfig.EnvRootPath = t.TempDir()
+		cpe := piperenv.CPEMap{"artifactVersion": "1.2.3"}
+		assert.NoError(t, cpe.WriteToDisk(filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment")))
+
+		err := runExportPipelineEnv([]string{"artifactVersion=version"})
+
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(outputFile)
+		assert.Regexp(t, `^version<<ghadelimiter_\w+\n1\.2\.3\n`, string(content))
+	})
+
+	t.Run("import", func(t *testing.T) {
+		GeneralConfig.EnvRootPath = t.TempDir()
+		t.Setenv("IMAGES", `["app:1.2.3"]`)
+
+		err := runImportPipelineEnv([]string{"container/imageNameTags=IMAGES"})
+
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment", "container", "imageNameTags.json"))
+		assert.Equal(t, `["app:1.2.3"]`, string(content))
+	})
+
+	t.Run("unsupported orchestrator", func(t *testing.T) {
+		t.Setenv("GITHUB_ACTIONS", "")
+		t.Setenv("GITHUB_ACTION", "")
+		t.Setenv("JENKINS_URL", "https://jenkins.example.com")
+		t.Setenv("JENKINS_HOME", "/var/jenkins_home")
+
+		err := runImportPipelineEnv([]string{"artifactVersion"})
+
+		assert.EqualError(t, err, "variables are not supported on orchestrator Jenkins, only on Azure DevOps and GitHub Actions")
+	})
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	stepResultSuccess  = "SUCCESS"
+	stepResultFailure  = "FAILURE"
+	stepResultInactive = "INACTIVE"
+	stepResultPlanned  = "PLANNED"
+	stepResultNotRun   = "NOT RUN"
+)
+
+type runPipelineCommandOptions struct {
+	openFile        func(s string, t map[string]string) (io.ReadCloser, error)
+	fileExists      func(filename string) (bool, error)
+	runStep         func(stageName, stepName string) error
+	stageConfigFile string
+	stageName       string
+	all             bool
+	dryRun          bool
+}
+
+var runPipelineOptions runPipelineCommandOptions
+
+// stepRunResult contains the outcome of a step executed by the local pipeline runner
+type stepRunResult struct {
+	stageName string
+	stepName  string
+	result    string
+	duration  time.Duration
+}
+
+// RunPipelineCommand is the entry command for running the steps of a pipeline definition locally
+func RunPipelineCommand() *cobra.Command {
+	runPipelineOptions.openFile = config.OpenPiperFile
+	runPipelineOptions.fileExists = piperutils.FileExists
+	var runPipelineCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Runs the active steps of one or all stages of a pipeline definition locally.",
+		Long: `Runs the active steps of one or all stages of a CRD-style pipeline definition (e.g. .resources/piper-stage-config.yml) locally.
+The conditions of the steps are evaluated right before a stage is executed, the active steps are then executed in their defined order,
+within the current process using the general flags passed to this command. The common pipeline environment is shared between the steps via the directory defined by --envRootPath.
+The execution stops with the first failing step, in the end a summary of all stages and steps is printed.`,
+		PreRun: func(cmd *cobra.Command, _ []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			log.SetVerbose(GeneralConfig.Verbose)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+			for _, token := range GeneralConfig.GitHubAccessTokens {
+				log.RegisterSecret(token)
+			}
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := &piperutils.Files{}
+			runPipelineOptions.runStep = runStepInProcess(cmd.Root())
+			if err := runPipeline(utils); err != nil {
+				log.Entry().WithError(err).Fatal("Running the pipeline failed")
+			}
+		},
+	}
+	addRunPipelineFlags(runPipelineCmd)
+	return runPipelineCmd
+}
+
+func runPipeline(utils piperutils.FileUtils) error {
+	if runPipelineOptions.all == (len(runPipelineOptions.stageName) > 0) {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("either a stage name via --stage or --all has to be provided")
+	}
+
+	var pConfig config.Config
+	projectConfig, err := initializeConfig(&pConfig, runPipelineOptions.openFile, runPipelineOptions.fileExists)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to load project config")
+	}
+
+	stageConfigFile, err := runPipelineOptions.openFile(runPipelineOptions.stageConfigFile, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "config: open pipeline definition '%v' failed", runPipelineOptions.stageConfigFile)
+	}
+	runConfig := &config.RunConfigV1{RunConfig: config.RunConfig{StageConfigFile: stageConfigFile}}
+	if err := runConfig.LoadConditionsV1(); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "failed to load pipeline definition '%v'", runPipelineOptions.stageConfigFile)
+	}
+
+	stages := []config.Stage{}
+	for _, stage := range runConfig.PipelineConfig.Spec.Stages {
+		if runPipelineOptions.all || runPipelineOptions.stageName == stage.DisplayName || runPipelineOptions.stageName == stage.Name {
+			stages = append(stages, stage)
+		}
+	}
+	if len(stages) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("stage '%v' is not defined in pipeline definition '%v'", runPipelineOptions.stageName, runPipelineOptions.stageConfigFile)
+	}
+
+	results := []stepRunResult{}
+	var runErr error
+	for _, stage := range stages {
+		// conditions are evaluated per stage since they may depend on the common pipeline environment written by previous stages
+		if runErr == nil {
+			if err := runConfig.InitRunConfigV1(projectConfig, nil, nil, nil, nil, utils, GeneralConfig.EnvRootPath); err != nil {
+				runErr = errors.Wrapf(err, "failed to evaluate conditions of stage '%v'", stage.DisplayName)
+			}
+		}
+		for _, step := range stage.Steps {
+			result := stepRunResult{stageName: stage.DisplayName, stepName: step.Name}
+			switch {
+			case runErr != nil:
+				result.result = stepResultNotRun
+			case !runConfig.RunSteps[stage.DisplayName][step.Name]:
+				result.result = stepResultInactive
+			case runPipelineOptions.dryRun:
+				result.result = stepResultPlanned
+			default:
+				log.Entry().Infof("Running step '%v' of stage '%v'", step.Name, stage.DisplayName)
+				start := time.Now()
+				err := runPipelineOptions.runStep(stage.DisplayName, step.Name)
+				result.duration = time.Since(start)
+				result.result = stepResultSuccess
+				if err != nil {
+					result.result = stepResultFailure
+					runErr = errors.Wrapf(err, "step '%v' of stage '%v' failed", step.Name, stage.DisplayName)
+				}
+			}
+			results = append(results, result)
+		}
+	}
+
+	log.Entry().Info(formatRunSummary(results))
+	return runErr
+}
+
+func formatRunSummary(results []stepRunResult) string {
+	var b strings.Builder
+	b.WriteString("Pipeline summary:")
+	stageName := ""
+	for i, result := range results {
+		if i == 0 || result.stageName != stageName {
+			stageName = result.stageName
+			fmt.Fprintf(&b, "\n  %v", stageName)
+		}
+		fmt.Fprintf(&b, "\n    %-40v %v", result.stepName, result.result)
+		if result.result == stepResultSuccess || result.result == stepResultFailure {
+			fmt.Fprintf(&b, " (%v)", result.duration.Round(time.Millisecond))
+		}
+	}
+	return b.String()
+}
+
+// stepExit is used to abort a step which is executed in-process instead of exiting the process
+type stepExit struct {
+	code int
+}
+
+// runStepInProcess executes the cobra command of a step within the current process.
+// The log hooks, exit handlers and the general config are restored after each step so that they do not accumulate across the steps.
+func runStepInProcess(rootCmd *cobra.Command) func(stageName, stepName string) error {
+	return func(stageName, stepName string) (err error) {
+		stepCmd, _, err := rootCmd.Find([]string{stepName})
+		if err != nil || stepCmd == rootCmd {
+			return fmt.Errorf("step '%v' is not available", stepName)
+		}
+
+		generalConfig := GeneralConfig
+		restoreLog := log.SaveState()
+		// a failing step terminates via log.Entry().Fatal() which would otherwise exit the process,
+		// its exit handler is run via defer when the step is aborted
+		logger := log.Entry().Logger
+		exitFunc := logger.ExitFunc
+		logger.ExitFunc = func(code int) { panic(stepExit{code: code}) }
+		log.DisableExitHandlers(true)
+		defer func() {
+			log.DisableExitHandlers(false)
+			logger.ExitFunc = exitFunc
+			restoreLog()
+			GeneralConfig = generalConfig
+			if r := recover(); r != nil {
+				exit, ok := r.(stepExit)
+				if !ok {
+					panic(r)
+				}
+				err = fmt.Errorf("step exited with code %v", exit.code)
+			}
+		}()
+
+		rootCmd.SetArgs([]string{stepName, "--stageName", stageName})
+		rootCmd.SilenceUsage = true
+		return rootCmd.Execute()
+	}
+}
+
+func addRunPipelineFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&runPipelineOptions.stageConfigFile, "stageConfig", ".resources/piper-stage-config.yml", "Pipeline definition containing the stages and the conditions of their steps (CRD-style)")
+	cmd.Flags().StringVar(&runPipelineOptions.stageName, "stage", "", "Name of the stage which should be run")
+	cmd.Flags().BoolVar(&runPipelineOptions.all, "all", false, "Run all stages of the pipeline definition")
+	cmd.Flags().BoolVar(&runPipelineOptions.dryRun, "dryRun", false, "Only evaluate the conditions and print which steps would be run")
+}
@@ -0,0 +1,203 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func runPipelineOpenFileMock(name string, tokens map[string]string) (io.ReadCloser, error) {
+	var fileContent string
+	switch name {
+	case "pipeline.yml":
+		fileContent = `
+apiVersion: project-piper.io/v1
+kind: PipelineDefinition
+spec:
+  stages:
+  - name: build
+    displayName: Build
+    steps:
+    - name: mavenBuild
+      conditions:
+      - config:
+          buildTool:
+          - maven
+    - name: npmExecuteScripts
+      conditions:
+      - config:
+          buildTool:
+          - npm
+  - name: test
+    displayName: Acceptance
+    steps:
+    - name: newmanExecute
+    - name: uiVeri5ExecuteTests
+`
+	case ".pipeline/config.yml":
+		fileContent = `
+general:
+  buildTool: maven
+`
+	default:
+		return nil, fmt.Errorf("file '%v' not found", name)
+	}
+	return ioutil.NopCloser(strings.NewReader(fileContent)), nil
+}
+
+func runPipelineFileExistsMock(filename string) (bool, error) {
+	return filename == ".pipeline/config.yml", nil
+}
+
+func TestRunPipeline(t *testing.T) {
+	defer func() {
+		runPipelineOptions = runPipelineCommandOptions{}
+		GeneralConfig.CustomConfig = ""
+		GeneralConfig.StageName = ""
+	}()
+
+	setup := func(executed *[]string, failingStep string) {
+		runPipelineOptions = runPipelineCommandOptions{
+			openFile:        runPipelineOpenFileMock,
+			fileExists:      runPipelineFileExistsMock,
+			stageConfigFile: "pipeline.yml",
+			runStep: func(stageName, stepName string) error {
+				*executed = append(*executed, stageName+"/"+stepName)
+				if stepName == failingStep {
+					return fmt.Errorf("step failed")
+				}
+				return nil
+			},
+		}
+		GeneralConfig.CustomConfig = ".pipeline/config.yml"
+		GeneralConfig.StageName = "initial"
+	}
+
+	t.Run("run all stages", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "")
+		runPipelineOptions.all = true
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Build/mavenBuild", "Acceptance/newmanExecute", "Acceptance/uiVeri5ExecuteTests"}, executed)
+		assert.Equal(t, "initial", GeneralConfig.StageName)
+	})
+
+	t.Run("run single stage by name", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "")
+		runPipelineOptions.stageName = "test"
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Acceptance/newmanExecute", "Acceptance/uiVeri5ExecuteTests"}, executed)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "")
+		runPipelineOptions.all = true
+		runPipelineOptions.dryRun = true
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.NoError(t, err)
+		assert.Empty(t, executed)
+	})
+
+	t.Run("stop on failing step", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "mavenBuild")
+		runPipelineOptions.all = true
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.EqualError(t, err, "step 'mavenBuild' of stage 'Build' failed: step failed")
+		assert.Equal(t, []string{"Build/mavenBuild"}, executed)
+	})
+
+	t.Run("steps run in-process", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "")
+		runPipelineOptions.stageName = "Acceptance"
+		hookCount := len(logrus.StandardLogger().Hooks[logrus.ErrorLevel])
+		handlerRuns := map[string]int{}
+		stepCommand := func(name string, fail bool) *cobra.Command {
+			return &cobra.Command{
+				Use: name,
+				Run: func(_ *cobra.Command, _ []string) {
+					executed = append(executed, GeneralConfig.StageName+"/"+name)
+					log.RegisterHook(&log.CollectorHook{})
+					handler := func() { handlerRuns[name]++ }
+					log.DeferExitHandler(handler)
+					defer handler()
+					GeneralConfig.Verbose = true
+					if fail {
+						log.Entry().Fatal("step failed")
+					}
+				},
+			}
+		}
+		rootCmd := &cobra.Command{Use: "piper"}
+		rootCmd.PersistentFlags().StringVar(&GeneralConfig.StageName, "stageName", GeneralConfig.StageName, "")
+		rootCmd.AddCommand(stepCommand("newmanExecute", false), stepCommand("uiVeri5ExecuteTests", true))
+		runPipelineOptions.runStep = runStepInProcess(rootCmd)
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.EqualError(t, err, "step 'uiVeri5ExecuteTests' of stage 'Acceptance' failed: step exited with code 1")
+		assert.Equal(t, []string{"Acceptance/newmanExecute", "Acceptance/uiVeri5ExecuteTests"}, executed)
+		assert.Equal(t, map[string]int{"newmanExecute": 1, "uiVeri5ExecuteTests": 1}, handlerRuns)
+		assert.Len(t, logrus.StandardLogger().Hooks[logrus.ErrorLevel], hookCount)
+		assert.False(t, GeneralConfig.Verbose)
+		assert.Equal(t, "initial", GeneralConfig.StageName)
+	})
+
+	t.Run("unknown step", func(t *testing.T) {
+		err := runStepInProcess(&cobra.Command{Use: "piper"})("Build", "unknownStep")
+		assert.EqualError(t, err, "step 'unknownStep' is not available")
+	})
+
+	t.Run("unknown stage", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "")
+		runPipelineOptions.stageName = "Release"
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.EqualError(t, err, "stage 'Release' is not defined in pipeline definition 'pipeline.yml'")
+	})
+
+	t.Run("neither stage nor all", func(t *testing.T) {
+		executed := []string{}
+		setup(&executed, "")
+
+		err := runPipeline(&mock.FilesMock{})
+		assert.EqualError(t, err, "either a stage name via --stage or --all has to be provided")
+	})
+}
+
+func TestFormatRunSummary(t *testing.T) {
+	summary := formatRunSummary([]stepRunResult{
+		{stageName: "Build", stepName: "mavenBuild", result: stepResultSuccess},
+		{stageName: "Build", stepName: "npmExecuteScripts", result: stepResultInactive},
+		{stageName: "Acceptance", stepName: "newmanExecute", result: stepResultFailure},
+		{stageName: "Acceptance", stepName: "uiVeri5ExecuteTests", result: stepResultNotRun},
+	})
+	expected := `Pipeline summary:
+  Build
+    mavenBuild                               SUCCESS (0s)
+    npmExecuteScripts                        INACTIVE
+  Acceptance
+    newmanExecute                            FAILURE (0s)
+    uiVeri5ExecuteTests                      NOT RUN`
+	assert.Equal(t, expected, summary)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type validateConfigCommandOptions struct {
+	schemaOutputFile string // if set: path to file where the JSON schema of the configuration should be written to
+	strict           bool   // if set: warnings are treated as errors
+	openFile         func(s string, t map[string]string) (io.ReadCloser, error)
+}
+
+var validateConfigOptions validateConfigCommandOptions
+
+type validateConfigUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+type validateConfigUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newValidateConfigUtils() validateConfigUtils {
+	utils := validateConfigUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+// ValidateConfigCommand is the entry command for validating the project configuration against the step metadata
+func ValidateConfigCommand() *cobra.Command {
+
+	validateConfigOptions.openFile = config.OpenPiperFile
+	var validateConfigCmd = &cobra.Command{
+		Use:   "validateConfig",
+		Short: "Validates the project 'Piper' configuration against the metadata of all steps.",
+		Long: `Validates the project 'Piper' configuration (e.g. .pipeline/config.yml) against the metadata of all steps.
+Reported are unknown sections, steps and parameters, values of a wrong type or outside of the possible values,
+parameters configured in a section they are not supported in as well as deprecated parameters and aliases.
+
+Optionally, a JSON schema of the configuration can be written which can be used for example in an IDE.`,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+		},
+		Run: func(cmd *cobra.Command, _ []string) {
+			utils := newValidateConfigUtils()
+			if err := validateConfig(utils); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				log.Entry().WithError(err).Fatal("configuration validation failed")
+			}
+		},
+	}
+
+	addValidateConfigFlags(validateConfigCmd)
+	return validateConfigCmd
+}
+
+func validateConfig(utils validateConfigUtils) error {
+	if GeneralConfig.MetaDataResolver == nil {
+		GeneralConfig.MetaDataResolver = GetAllStepMetadata
+	}
+	stepMetadata := GeneralConfig.MetaDataResolver()
+
+	if len(validateConfigOptions.schemaOutputFile) > 0 {
+		schema, err := json.MarshalIndent(config.GenerateConfigSchema(stepMetadata), "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal configuration schema")
+		}
+		if err := utils.FileWrite(validateConfigOptions.schemaOutputFile, schema, 0666); err != nil {
+			return fmt.Errorf("failed to write schema file %v: %w", validateConfigOptions.schemaOutputFile, err)
+		}
+		log.Entry().Infof("Configuration schema written to '%v'", validateConfigOptions.schemaOutputFile)
+	}
+
+	projectConfigFile := getProjectConfigFile(GeneralConfig.CustomConfig)
+	configFile, err := validateConfigOptions.openFile(projectConfigFile, GeneralConfig.GitHubAccessTokens)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			log.Entry().Infof("Project config: NONE ('%s' does not exist)", projectConfigFile)
+			return nil
+		}
+		return errors.Wrapf(err, "config: open configuration file '%v' failed", projectConfigFile)
+	}
+	defer configFile.Close()
+
+	findings, err := config.ValidateConfig(configFile, stepMetadata)
+	if err != nil {
+		return errors.Wrapf(err, "failed to validate configuration file '%v'", projectConfigFile)
+	}
+
+	errorCount, warningCount := 0, 0
+	for _, finding := range findings {
+		if finding.Severity == config.FindingError || validateConfigOptions.strict {
+			errorCount++
+			log.Entry().Errorf("%v:%v", projectConfigFile, finding)
+		} else {
+			warningCount++
+			log.Entry().Warnf("%v:%v", projectConfigFile, finding)
+		}
+	}
+
+	if errorCount > 0 {
+		return fmt.Errorf("configuration file '%v' contains %v error(s) and %v warning(s)", projectConfigFile, errorCount, warningCount)
+	}
+	log.Entry().Infof("Configuration file '%v' is valid (%v warning(s))", projectConfigFile, warningCount)
+	return nil
+}
+
+func addValidateConfigFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&validateConfigOptions.schemaOutputFile, "schemaOutputFile", "", "Defines a file path. If set, the JSON schema of the project configuration is written to this file")
+	cmd.Flags().BoolVar(&validateConfigOptions.strict, "strict", false, "Defines if warnings, e.g. about unknown parameters, should be treated as errors")
+}
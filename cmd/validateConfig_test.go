@@ -0,0 +1,76 @@
+//go:build unit
+// +build unit
+
+package cmd
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func validateConfigMetadataMock() map[string]config.StepData {
+	return map[string]config.StepData{
+		"mavenBuild": {
+			Metadata: config.StepMetadata{Name: "mavenBuild"},
+			Spec: config.StepSpec{Inputs: config.StepInputs{Parameters: []config.StepParameters{
+				{Name: "flatten", Type: "bool", Scope: []string{"STEPS"}},
+			}}},
+		},
+	}
+}
+
+func validateConfigOpenFileMock(content string) func(string, map[string]string) (io.ReadCloser, error) {
+	return func(name string, tokens map[string]string) (io.ReadCloser, error) {
+		if len(content) == 0 {
+			return nil, os.ErrNotExist
+		}
+		return ioutil.NopCloser(strings.NewReader(content)), nil
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	GeneralConfig.MetaDataResolver = validateConfigMetadataMock
+	GeneralConfig.CustomConfig = ".pipeline/config.yml"
+	defer func() {
+		GeneralConfig.MetaDataResolver = nil
+		GeneralConfig.CustomConfig = ""
+		validateConfigOptions = validateConfigCommandOptions{}
+	}()
+
+	t.Run("valid configuration", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{openFile: validateConfigOpenFileMock("steps:\n  mavenBuild:\n    flatten: true\n")}
+		assert.NoError(t, validateConfig(&mock.FilesMock{}))
+	})
+
+	t.Run("warnings only", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{openFile: validateConfigOpenFileMock("steps:\n  mavenBuild:\n    flaten: true\n")}
+		assert.NoError(t, validateConfig(&mock.FilesMock{}))
+	})
+
+	t.Run("warnings in strict mode", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{strict: true, openFile: validateConfigOpenFileMock("steps:\n  mavenBuild:\n    flaten: true\n")}
+		assert.EqualError(t, validateConfig(&mock.FilesMock{}), "configuration file '.pipeline/config.yml' contains 1 error(s) and 0 warning(s)")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{openFile: validateConfigOpenFileMock("steps:\n  mavenBuild:\n    flatten: [true]\n")}
+		assert.EqualError(t, validateConfig(&mock.FilesMock{}), "configuration file '.pipeline/config.yml' contains 1 error(s) and 0 warning(s)")
+	})
+
+	t.Run("no configuration, write schema", func(t *testing.T) {
+		validateConfigOptions = validateConfigCommandOptions{schemaOutputFile: "schema.json", openFile: validateConfigOpenFileMock("")}
+		utils := &mock.FilesMock{}
+
+		assert.NoError(t, validateConfig(utils))
+		schema, err := utils.FileRead("schema.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(schema), `"flatten": {`)
+	})
+}
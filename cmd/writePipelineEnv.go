@@ -13,7 +13,8 @@ import (
 
 // WritePipelineEnv Serializes the commonPipelineEnvironment JSON to disk
 func WritePipelineEnv() *cobra.Command {
-	return &cobra.Command{
+	var importVariables []string
+	writePipelineEnvCmd := &cobra.Command{
 		Use:   "writePipelineEnv",
 		Short: "Serializes the commonPipelineEnvironment JSON to disk",
 		PreRun: func(cmd *cobra.Command, args []string) {
@@ -23,12 +24,20 @@ func WritePipelineEnv() *cobra.Command {
 		},
 
 		Run: func(cmd *cobra.Command, args []string) {
+			if len(importVariables) > 0 {
+				if err := runImportPipelineEnv(importVariables); err != nil {
+					log.Entry().Fatalf("error when importing common Pipeline environment: %v", err)
+				}
+				return
+			}
 			err := runWritePipelineEnv()
 			if err != nil {
 				log.Entry().Fatalf("error when writing common Pipeline environment: %v", err)
 			}
 		},
 	}
+	writePipelineEnvCmd.Flags().StringSliceVar(&importVariables, "importVariables", nil, "Imports variables of the orchestrator into the commonPipelineEnvironment instead of reading JSON, entries have the format <key>=<variable name>")
+	return writePipelineEnvCmd
 }
 
 func runWritePipelineEnv() error {
@@ -69,3 +78,19 @@ func runWritePipelineEnv() error {
 	}
 	return nil
 }
+
+func runImportPipelineEnv(mappingEntries []string) error {
+	mapping, err := piperenv.ParseVariableMapping(mappingEntries)
+	if err != nil {
+		return err
+	}
+	variables, err := orchestratorVariables()
+	if err != nil {
+		return err
+	}
+	commonPipelineEnv := piperenv.CPEMap{}
+	if err := commonPipelineEnv.ImportVariables(mapping, variables); err != nil {
+		return err
+	}
+	return commonPipelineEnv.WriteToDisk(filepath.Join(GeneralConfig.EnvRootPath, "commonPipelineEnvironment"))
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/package-url/packageurl-go"
@@ -183,6 +184,44 @@ func (v Vulnerability) ToMarkdown() ([]byte, error) {
 	return vul.ToMarkdown()
 }
 
+// ToFinding returns the scanner independent representation of the vulnerability
+func (v Vulnerability) ToFinding() format.Finding {
+	severity, _ := format.ParseSeverity(v.VulnerabilityWithRemediation.Severity)
+	finding := format.Finding{
+		Scanner:      "BlackDuck",
+		ID:           v.VulnerabilityName,
+		Title:        v.Title(),
+		Description:  v.Description,
+		Severity:     severity,
+		Score:        float64(v.VulnerabilityWithRemediation.BaseScore),
+		CVE:          format.ExtractCVE(v.VulnerabilityName, v.RelatedVulnerability),
+		AuditState:   AuditState(v.RemediationStatus),
+		AuditComment: v.RemediationComment,
+		Link:         v.RelatedVulnerability,
+	}
+	if len(v.CweID) > 0 {
+		finding.CWE = []string{v.CweID}
+	}
+	if v.Component != nil {
+		finding.PackageURL = v.Component.ToPackageUrl().ToString()
+	}
+	finding.Fingerprint = format.PackageFingerprint(finding.PackageURL, v.VulnerabilityName)
+	return finding
+}
+
+// AuditState returns the unified audit state of a remediation status
+func AuditState(remediationStatus string) format.AuditState {
+	switch remediationStatus {
+	case "NEEDS_REVIEW":
+		return format.AuditStateInProcess
+	case "REMEDIATION_COMPLETE", "PATCHED", "MITIGATED", "DUPLICATE", "IGNORED":
+		return format.AuditStateNotRelevant
+	case "REMEDIATION_REQUIRED":
+		return format.AuditStateRelevant
+	}
+	return format.AuditStateNew
+}
+
 // ToTxt returns the textual representation of the contents
 func (v Vulnerability) ToTxt() string {
 	return fmt.Sprintf(`Vulnerability %v
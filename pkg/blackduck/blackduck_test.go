@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/stretchr/testify/assert"
 )
@@ -702,6 +703,59 @@ func TestTransformComponentOriginToPurlParts(t *testing.T) {
 	}
 }
 
+func TestVulnerabilityToFinding(t *testing.T) {
+	vulnerability := Vulnerability{
+		Name:    "Apache Log4j",
+		Version: "2.14.1",
+		VulnerabilityWithRemediation: VulnerabilityWithRemediation{
+			VulnerabilityName:    "BDSA-2021-3779",
+			BaseScore:            10,
+			Severity:             "CRITICAL",
+			RemediationStatus:    "MITIGATED",
+			RemediationComment:   "JNDI lookups are disabled",
+			Description:          "remote code execution",
+			CweID:                "CWE-502",
+			RelatedVulnerability: "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+		},
+		Component: &Component{
+			Name:    "Apache Log4j",
+			Version: "2.14.1",
+			Origins: []ComponentOrigin{{ExternalNamespace: "maven", ExternalID: "org.apache.logging.log4j:log4j-core:2.14.1"}},
+		},
+	}
+
+	finding := vulnerability.ToFinding()
+
+	assert.Equal(t, format.Finding{
+		Scanner:      "BlackDuck",
+		ID:           "BDSA-2021-3779",
+		Title:        "BDSA-2021-3779",
+		Description:  "remote code execution",
+		Severity:     format.SeverityCritical,
+		Score:        10,
+		CVE:          "CVE-2021-44228",
+		CWE:          []string{"CWE-502"},
+		PackageURL:   "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		AuditState:   format.AuditStateNotRelevant,
+		AuditComment: "JNDI lookups are disabled",
+		Fingerprint:  format.PackageFingerprint("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", "BDSA-2021-3779"),
+		Link:         "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+	}, finding)
+
+	t.Run("vulnerabilities with same weakness", func(t *testing.T) {
+		other := vulnerability
+		other.VulnerabilityName = "CVE-2021-45046"
+		assert.NotEqual(t, finding.Fingerprint, other.ToFinding().Fingerprint)
+	})
+}
+
+func TestAuditState(t *testing.T) {
+	assert.Equal(t, format.AuditStateNew, AuditState("NEW"))
+	assert.Equal(t, format.AuditStateInProcess, AuditState("NEEDS_REVIEW"))
+	assert.Equal(t, format.AuditStateRelevant, AuditState("REMEDIATION_REQUIRED"))
+	assert.Equal(t, format.AuditStateNotRelevant, AuditState("IGNORED"))
+}
+
 func TestComponentToPackageUrl(t *testing.T) {
 	tt := []struct {
 		description string
@@ -279,6 +279,19 @@ func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectID
 	return reportPaths, nil
 }
 
+// ToFindings returns the scanner independent representation of the results of a SARIF report created by ConvertCxxmlToSarif
+func ToFindings(sarif format.SARIF) []format.Finding {
+	return format.FindingsFromSARIF(sarif, AuditStates.AuditState)
+}
+
+// AuditStates maps the states of Checkmarx results to the unified audit state
+var AuditStates = format.AuditStateMapping{States: map[string]format.AuditState{
+	"NotExploitable":         format.AuditStateNotRelevant,
+	"Confirmed":              format.AuditStateRelevant,
+	"Urgent":                 format.AuditStateRelevant,
+	"ProposedNotExploitable": format.AuditStateInProcess,
+}}
+
 func reportShaCheckmarx(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
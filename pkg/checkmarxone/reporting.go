@@ -280,6 +280,19 @@ func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectID
 	return reportPaths, nil
 }
 
+// ToFindings returns the scanner independent representation of the results of a SARIF report created by ConvertCxJSONToSarif
+func ToFindings(sarif format.SARIF) []format.Finding {
+	return format.FindingsFromSARIF(sarif, AuditStates.AuditState)
+}
+
+// AuditStates maps the states of Checkmarx One results to the unified audit state
+var AuditStates = format.AuditStateMapping{States: map[string]format.AuditState{
+	"NOT_EXPLOITABLE":          format.AuditStateNotRelevant,
+	"CONFIRMED":                format.AuditStateRelevant,
+	"URGENT":                   format.AuditStateRelevant,
+	"PROPOSED_NOT_EXPLOITABLE": format.AuditStateInProcess,
+}}
+
 func reportShaCheckmarxOne(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
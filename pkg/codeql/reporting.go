@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"path/filepath"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/pkg/errors"
@@ -43,3 +44,9 @@ func WriteJSONReport(jsonReport CodeqlAudit, modulePath string) ([]piperutils.Pa
 
 	return reportPaths, nil
 }
+
+// ToFindings returns the scanner independent representation of the results of a SARIF report created by CodeQL,
+// the audit state is not part of the report thus all findings are considered as new
+func ToFindings(sarif format.SARIF) []format.Finding {
+	return format.FindingsFromSARIF(sarif, func(format.SarifProperties) format.AuditState { return format.AuditStateNew })
+}
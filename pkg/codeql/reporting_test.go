@@ -0,0 +1,40 @@
+//go:build unit
+// +build unit
+
+package codeql
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToFindings(t *testing.T) {
+	sarif := format.SARIF{Runs: []format.Runs{{
+		Tool: format.Tool{Driver: format.Driver{
+			Name:  "CodeQL",
+			Rules: []format.SarifRule{{ID: "js/sql-injection", Name: "SqlInjection", Properties: &format.SarifRuleProperties{SecuritySeverity: "8.8"}}},
+		}},
+		Results: []format.Results{
+			{
+				RuleID:              "js/sql-injection",
+				Locations:           []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: "src/db.js"}, Region: format.Region{StartLine: 12}}}},
+				PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: "hash:1"},
+				Properties:          &format.SarifProperties{UnifiedAuditState: "notRelevant"},
+			},
+		},
+	}}}
+
+	findings := ToFindings(sarif)
+
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, "CodeQL", findings[0].Scanner)
+		assert.Equal(t, "SqlInjection", findings[0].Title)
+		assert.Equal(t, format.SeverityHigh, findings[0].Severity)
+		assert.Equal(t, &format.FindingLocation{Path: "src/db.js", Line: 12}, findings[0].Location)
+		assert.Equal(t, "hash:1", findings[0].Fingerprint)
+		// the audit state is not part of the CodeQL report
+		assert.Equal(t, format.AuditStateNew, findings[0].AuditState)
+	}
+}
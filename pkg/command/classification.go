@@ -0,0 +1,52 @@
+package command
+
+import (
+	"regexp"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// ErrorClassificationRule categorizes the error of a step based on a line of the command output
+type ErrorClassificationRule struct {
+	// Pattern is a regular expression matching the line of the output
+	Pattern string `json:"pattern"`
+	// Category is the error category, e.g. "build" or "infrastructure"
+	Category string `json:"category"`
+	// Hint optionally describes how to solve the error
+	Hint   string `json:"hint,omitempty"`
+	regexp *regexp.Regexp
+}
+
+var errorClassificationRules []ErrorClassificationRule
+
+// SetErrorClassificationRules sets rules which are applied to the output of all commands, e.g. based on the project configuration.
+// The rules take precedence over the ErrorCategoryMapping of a command, the first matching rule is used.
+func SetErrorClassificationRules(rules []ErrorClassificationRule) error {
+	compiled := make([]ErrorClassificationRule, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule.Pattern) == 0 {
+			return errors.New("error classification rule without pattern")
+		}
+		if log.ErrorCategoryByString(rule.Category) == log.ErrorUndefined {
+			return errors.Errorf("invalid category '%v' of error classification rule '%v', expected one of build, compliance, config, custom, infrastructure, service, test", rule.Category, rule.Pattern)
+		}
+		var err error
+		if rule.regexp, err = regexp.Compile(rule.Pattern); err != nil {
+			return errors.Wrapf(err, "invalid pattern of error classification rule '%v'", rule.Pattern)
+		}
+		compiled = append(compiled, rule)
+	}
+	errorClassificationRules = compiled
+	return nil
+}
+
+// classifyByRules returns the first rule matching the line, nil if no rule matches
+func classifyByRules(line string) *ErrorClassificationRule {
+	for i := range errorClassificationRules {
+		if errorClassificationRules[i].regexp.MatchString(line) {
+			return &errorClassificationRules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+//go:build unit
+// +build unit
+
+package command
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetErrorClassificationRules(t *testing.T) {
+	defer SetErrorClassificationRules(nil)
+
+	tt := []struct {
+		name string
+		rule ErrorClassificationRule
+		err  string
+	}{
+		{name: "valid rule", rule: ErrorClassificationRule{Pattern: `^\[ERROR\] .*license`, Category: "compliance"}},
+		{name: "missing pattern", rule: ErrorClassificationRule{Category: "build"}, err: "error classification rule without pattern"},
+		{name: "invalid category", rule: ErrorClassificationRule{Pattern: "failed", Category: "unknown"}, err: "invalid category 'unknown' of error classification rule 'failed', expected one of build, compliance, config, custom, infrastructure, service, test"},
+		{name: "invalid pattern", rule: ErrorClassificationRule{Pattern: "failed(", Category: "build"}, err: "invalid pattern of error classification rule 'failed(': error parsing regexp: missing closing ): `failed(`"},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			err := SetErrorClassificationRules([]ErrorClassificationRule{test.rule})
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseConsoleErrorsWithRules(t *testing.T) {
+	defer SetErrorClassificationRules(nil)
+	defer log.ResetConsoleError()
+	defer log.SetErrorCategory(log.ErrorUndefined)
+	err := SetErrorClassificationRules([]ErrorClassificationRule{
+		{Pattern: `artifactory\.example\.com.*(timed out|502)`, Category: "infrastructure", Hint: "the artifact repository is not available, retry the build"},
+		{Pattern: `build failed`, Category: "test"},
+	})
+	assert.NoError(t, err)
+	cmd := Command{ErrorCategoryMapping: map[string][]string{"build": {"build failed"}}}
+
+	t.Run("rule takes precedence over mapping", func(t *testing.T) {
+		log.SetErrorCategory(log.ErrorUndefined)
+		log.ResetConsoleError()
+		cmd.parseConsoleErrors("the build failed")
+		assert.Equal(t, log.ErrorTest, log.GetErrorCategory())
+	})
+
+	t.Run("category, line and hint of first matching rule are recorded", func(t *testing.T) {
+		log.SetErrorCategory(log.ErrorUndefined)
+		log.ResetConsoleError()
+		cmd.parseConsoleErrors("download from https://artifactory.example.com/lib.jar timed out")
+		cmd.parseConsoleErrors("the build failed")
+
+		assert.Equal(t, log.ErrorInfrastructure, log.GetErrorCategory())
+		line, hint := log.GetConsoleError()
+		assert.Equal(t, "download from https://artifactory.example.com/lib.jar timed out", line)
+		assert.Equal(t, "the artifact repository is not available, retry the build", hint)
+	})
+
+	t.Run("rules apply to commands without mapping", func(t *testing.T) {
+		ExecCommand = helperCommand
+		defer func() { ExecCommand = exec.Command }()
+		log.SetErrorCategory(log.ErrorUndefined)
+		log.ResetConsoleError()
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("echo", "build failed")
+
+		assert.NoError(t, err)
+		assert.Equal(t, log.ErrorTest, log.GetErrorCategory())
+		line, _ := log.GetConsoleError()
+		assert.Equal(t, "build failed", line)
+	})
+
+	t.Run("console error is recorded per command", func(t *testing.T) {
+		ExecCommand = helperCommand
+		defer func() { ExecCommand = exec.Command }()
+		log.SetErrorCategory(log.ErrorUndefined)
+		log.ResetConsoleError()
+		log.SetConsoleError("download from https://artifactory.example.com/lib.jar timed out", "")
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("echo", "build failed")
+
+		assert.NoError(t, err)
+		assert.Equal(t, log.ErrorTest, log.GetErrorCategory())
+		line, _ := log.GetConsoleError()
+		assert.Equal(t, "build failed", line)
+	})
+}
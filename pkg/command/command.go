@@ -3,16 +3,23 @@ package command
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Command defines the information required for executing a call to any executable
@@ -25,6 +32,9 @@ type Command struct {
 	stderr               io.Writer
 	env                  []string
 	exitCode             int
+	timeout              time.Duration
+	outputLimit          int64
+	ctx                  context.Context
 }
 
 type runner interface {
@@ -220,13 +230,13 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		return nil, errors.Wrap(err, "starting command failed")
 	}
 
-	execution := execution{cmd: cmd, ul: log.NewURLLogger(c.StepName)}
+	execution := execution{cmd: cmd, ul: log.NewURLLogger(c.StepName), tail: &lineTail{}}
 	execution.wg.Add(2)
 
 	srcOut := stdout
 	srcErr := stderr
 
-	if c.ErrorCategoryMapping != nil {
+	if c.ErrorCategoryMapping != nil || len(errorClassificationRules) > 0 {
 		prOut, pwOut := io.Pipe()
 		trOut := io.TeeReader(stdout, pwOut)
 		srcOut = prOut
@@ -250,15 +260,18 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		}()
 	}
 
+	stdoutCapture := c.limitCapture(c.stdout, "stdout")
+	stderrCapture := c.limitCapture(c.stderr, "stderr")
+
 	go func() {
 		if c.StepName != "" {
 			var buf bytes.Buffer
 			br := bufio.NewWriter(&buf)
-			_, execution.errCopyStdout = piperutils.CopyData(io.MultiWriter(c.stdout, br), srcOut)
+			_, execution.errCopyStdout = piperutils.CopyData(io.MultiWriter(stdoutCapture, execution.tail, c.limitCapture(br, "stdout for the URL log")), srcOut)
 			br.Flush()
 			execution.ul.Parse(buf)
 		} else {
-			_, execution.errCopyStdout = piperutils.CopyData(c.stdout, srcOut)
+			_, execution.errCopyStdout = piperutils.CopyData(io.MultiWriter(stdoutCapture, execution.tail), srcOut)
 		}
 		execution.wg.Done()
 	}()
@@ -267,11 +280,11 @@ func (c *Command) startCmd(cmd *exec.Cmd) (*execution, error) {
 		if c.StepName != "" {
 			var buf bytes.Buffer
 			bw := bufio.NewWriter(&buf)
-			_, execution.errCopyStderr = piperutils.CopyData(io.MultiWriter(c.stderr, bw), srcErr)
+			_, execution.errCopyStderr = piperutils.CopyData(io.MultiWriter(stderrCapture, execution.tail, c.limitCapture(bw, "stderr for the URL log")), srcErr)
 			bw.Flush()
 			execution.ul.Parse(buf)
 		} else {
-			_, execution.errCopyStderr = piperutils.CopyData(c.stderr, srcErr)
+			_, execution.errCopyStderr = piperutils.CopyData(io.MultiWriter(stderrCapture, execution.tail), srcErr)
 		}
 		execution.wg.Done()
 	}()
@@ -316,7 +329,20 @@ func scanShortLines(data []byte, atEOF bool) (advance int, token []byte, err err
 	return 0, nil, nil
 }
 
+// parseConsoleErrors classifies a line of the console output.
+// The first line of a command matching an error classification rule is recorded together with its category and hint.
+// Otherwise the ErrorCategoryMapping applies where the last matching line determines the category.
 func (c *Command) parseConsoleErrors(logLine string) {
+	if rule := classifyByRules(logLine); rule != nil {
+		if log.SetConsoleError(logLine, rule.Hint) {
+			log.SetErrorCategory(log.ErrorCategoryByString(rule.Category))
+		}
+		return
+	}
+	if line, _ := log.GetConsoleError(); len(line) > 0 {
+		// the category of the recorded console error takes precedence
+		return
+	}
 	for category, categoryErrors := range c.ErrorCategoryMapping {
 		for _, errorPart := range categoryErrors {
 			if matchPattern(logLine, errorPart) {
@@ -340,13 +366,48 @@ func matchPattern(text, pattern string) bool {
 	return true
 }
 
-func (c *Command) runCmd(cmd *exec.Cmd) error {
+func (c *Command) runCmd(cmd *exec.Cmd) (err error) {
+	// command arguments are not part of the span since they may contain credentials
+	executable := filepath.Base(cmd.Args[0])
+	_, span := tracing.StartSpan(context.Background(), "exec "+executable,
+		trace.WithAttributes(attribute.String("process.executable.name", executable)))
+	defer func() {
+		span.SetAttributes(attribute.Int("process.exit_code", c.exitCode))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	// the console error of a previous command must not hide the error of this command
+	log.ResetConsoleError()
+
+	ctx, cancel := c.executionContext()
+	defer cancel()
+	if ctx != nil {
+		setProcessGroup(cmd)
+	}
+
 	execution, err := c.startCmd(cmd)
 	if err != nil {
 		return err
 	}
 
-	err = execution.Wait()
+	if ctx != nil {
+		finished := watchExecution(ctx, cmd, executable)
+		err = execution.Wait()
+		if reason := finished(); reason != nil {
+			c.exitCode = 1
+			setTimeoutErrorDetail(executable, reason, execution.tail)
+			if reason == context.DeadlineExceeded {
+				return errors.Errorf("command '%v' timed out after %v", executable, c.getTimeout())
+			}
+			return errors.Wrapf(reason, "command '%v' was cancelled", executable)
+		}
+	} else {
+		err = execution.Wait()
+	}
 
 	if execution.errCopyStdout != nil || execution.errCopyStderr != nil {
 		return fmt.Errorf("failed to capture stdout/stderr: '%v'/'%v'", execution.errCopyStdout, execution.errCopyStderr)
@@ -9,11 +9,19 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // based on https://golang.org/src/os/exec/exec_test.go
@@ -99,6 +107,24 @@ func TestExecutableRun(t *testing.T) {
 	})
 }
 
+func TestExecutableTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+	err := ex.RunExecutable("sh", "-c", "exit 3")
+
+	assert.Error(t, err)
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "exec sh", spans[0].Name)
+		assert.Contains(t, spans[0].Attributes, attribute.String("process.executable.name", "sh"))
+		assert.Contains(t, spans[0].Attributes, attribute.Int("process.exit_code", 3))
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+	}
+}
+
 func TestEnvironmentVariables(t *testing.T) {
 
 	ExecCommand = helperCommand
@@ -280,6 +306,16 @@ func TestHelperProcess(*testing.T) {
 		b = bytes.Repeat(b, size)
 
 		fmt.Fprint(os.Stderr, b)
+	case "sleep":
+		// prints the arguments and blocks, optionally ignoring SIGTERM
+		if len(args) > 0 && args[0] == "--ignore-sigterm" {
+			signal.Ignore(syscall.SIGTERM)
+			args = args[1:]
+		}
+		for _, s := range args {
+			fmt.Println(s)
+		}
+		time.Sleep(time.Minute)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command %q\n", cmd)
 		os.Exit(2)
@@ -13,6 +13,7 @@ type execution struct {
 	errCopyStdout error
 	errCopyStderr error
 	ul            *log.URLLogger
+	tail          *lineTail
 }
 
 func (execution *execution) Kill() error {
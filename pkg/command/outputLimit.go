@@ -0,0 +1,66 @@
+package command
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+var defaultOutputLimit int64
+
+// SetDefaultOutputLimit sets the maximum number of bytes captured per output stream for all commands without explicit limit. 0 disables the limit.
+func SetDefaultOutputLimit(limit int64) {
+	defaultOutputLimit = limit
+}
+
+// SetOutputLimit sets the maximum number of bytes captured per output stream of the executions of the command, it overrides the default limit
+func (c *Command) SetOutputLimit(limit int64) {
+	c.outputLimit = limit
+}
+
+func (c *Command) getOutputLimit() int64 {
+	if c.outputLimit > 0 {
+		return c.outputLimit
+	}
+	return defaultOutputLimit
+}
+
+// limitCapture limits the output written to a writer capturing it in memory, e.g. a buffer provided by the step.
+// The console of piper is not limited since the output is not kept in memory.
+func (c *Command) limitCapture(writer io.Writer, stream string) io.Writer {
+	limit := c.getOutputLimit()
+	if limit <= 0 || writer == os.Stdout || writer == os.Stderr {
+		return writer
+	}
+	return &limitedWriter{writer: writer, remaining: limit, limit: limit, stream: stream}
+}
+
+// limitedWriter writes up to a limit of bytes to the writer and discards the remaining output
+type limitedWriter struct {
+	mutex     sync.Mutex
+	writer    io.Writer
+	remaining int64
+	limit     int64
+	stream    string
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.remaining <= 0 {
+		return len(p), nil
+	}
+	captured := p
+	if int64(len(captured)) > w.remaining {
+		captured = captured[:w.remaining]
+		log.Entry().Warnf("captured %v exceeds the limit of %v bytes, the remaining output is not captured", w.stream, w.limit)
+	}
+	written, err := w.writer.Write(captured)
+	w.remaining -= int64(written)
+	if err != nil {
+		return written, err
+	}
+	return len(p), nil
+}
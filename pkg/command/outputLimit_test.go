@@ -0,0 +1,62 @@
+//go:build unit
+// +build unit
+
+package command
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunExecutableOutputLimit(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+
+	t.Run("output limit of command", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer), StepName: "step"}
+		ex.SetOutputLimit(8)
+
+		err := ex.RunExecutable("echo", "foo bar", "baz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo bar ", stdout.String())
+	})
+
+	t.Run("default output limit", func(t *testing.T) {
+		SetDefaultOutputLimit(3)
+		defer SetDefaultOutputLimit(0)
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("echo", "foo bar", "baz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo", stdout.String())
+	})
+
+	t.Run("no limit", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("echo", "foo bar", "baz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "foo bar baz\n", stdout.String())
+	})
+}
+
+func TestLimitedWriter(t *testing.T) {
+	buf := new(bytes.Buffer)
+	writer := &limitedWriter{writer: buf, remaining: 5, limit: 5, stream: "stdout"}
+
+	for _, p := range []string{"abc", "def", "ghi"} {
+		n, err := writer.Write([]byte(p))
+		assert.NoError(t, err)
+		assert.Equal(t, len(p), n)
+	}
+	assert.Equal(t, "abcde", buf.String())
+}
@@ -0,0 +1,42 @@
+//go:build unit && !windows
+// +build unit,!windows
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardSignals(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+	registered := make(chan chan<- os.Signal, 1)
+	notifySignals = func(c chan<- os.Signal, sig ...os.Signal) {
+		assert.Equal(t, []os.Signal{syscall.SIGINT, syscall.SIGTERM}, sig)
+		registered <- c
+	}
+	defer func() { notifySignals = signal.Notify }()
+
+	ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+	ex.SetTimeout(time.Minute)
+	go func() {
+		// piper receives SIGTERM while the command is running in its own process group
+		signals := <-registered
+		time.Sleep(200 * time.Millisecond)
+		signals <- syscall.SIGTERM
+	}()
+
+	start := time.Now()
+	err := ex.RunExecutable("sleep")
+
+	assert.EqualError(t, err, "running command 'sleep' failed: cmd.Run() failed: signal: terminated")
+	assert.Less(t, time.Since(start), 30*time.Second)
+}
@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package command
+
+import (
+	"os"
+	"os/exec"
+)
+
+// forwardedSignals is empty since commands remain in the console group of piper on Windows
+var forwardedSignals []os.Signal
+
+// setProcessGroup is not supported on Windows, only the command itself is terminated
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func signalProcessGroup(process *os.Process, sig os.Signal) error {
+	return process.Signal(sig)
+}
+
+// terminateProcessGroup kills the process since Windows does not support SIGTERM
+func terminateProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
+
+func killProcessGroup(process *os.Process) error {
+	return process.Kill()
+}
@@ -0,0 +1,178 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const (
+	// maxTailLines is the number of output lines which are kept for the error details of a timed out command
+	maxTailLines = 50
+	// maxTailLineLength cuts long lines, e.g. progress bars without line breaks
+	maxTailLineLength = 1000
+)
+
+// killGracePeriod is the time a command gets to terminate after SIGTERM before it is killed
+var killGracePeriod = 10 * time.Second
+
+var defaultTimeout time.Duration
+
+// notifySignals registers the channel for the signals forwarded to the process group of a running command
+var notifySignals = signal.Notify
+
+// SetDefaultTimeout sets the timeout for all commands without explicit timeout, e.g. based on the step configuration. 0 disables the timeout.
+func SetDefaultTimeout(timeout time.Duration) {
+	defaultTimeout = timeout
+}
+
+// SetTimeout sets the timeout for the executions of the command, it overrides the default timeout
+func (c *Command) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetContext sets a context which terminates running executions of the command when it is done
+func (c *Command) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+func (c *Command) getTimeout() time.Duration {
+	if c.timeout > 0 {
+		return c.timeout
+	}
+	return defaultTimeout
+}
+
+// executionContext returns the context limiting an execution, nil in case neither a timeout nor a context is set
+func (c *Command) executionContext() (context.Context, context.CancelFunc) {
+	ctx := c.ctx
+	timeout := c.getTimeout()
+	if ctx == nil && timeout <= 0 {
+		return nil, func() {}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// watchExecution terminates the process group of cmd once ctx is done before the execution finished.
+// The processes get the chance to terminate gracefully on SIGTERM before they are killed.
+// Since the command runs in its own process group, SIGINT and SIGTERM received by piper (e.g. on abort of the pipeline) are forwarded to it.
+// The returned function has to be called once the execution finished, it returns the reason of a termination.
+func watchExecution(ctx context.Context, cmd *exec.Cmd, executable string) func() error {
+	finished := make(chan struct{})
+	signals := make(chan os.Signal, 1)
+	if len(forwardedSignals) > 0 {
+		notifySignals(signals, forwardedSignals...)
+	}
+	var terminationReason error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer signal.Stop(signals)
+		for ctx.Err() == nil {
+			select {
+			case <-finished:
+				return
+			case sig := <-signals:
+				log.Entry().Warnf("forwarding signal '%v' to '%v'", sig, executable)
+				if err := signalProcessGroup(cmd.Process, sig); err != nil {
+					log.Entry().WithError(err).Debugf("failed to forward signal '%v'", sig)
+				}
+			case <-ctx.Done():
+			}
+		}
+		terminationReason = ctx.Err()
+		log.Entry().Warnf("terminating '%v': %v", executable, describeTermination(ctx.Err()))
+		if err := terminateProcessGroup(cmd.Process); err != nil {
+			log.Entry().WithError(err).Debug("failed to send SIGTERM")
+		}
+		select {
+		case <-finished:
+		case <-time.After(killGracePeriod):
+			log.Entry().Warnf("killing '%v' since it did not terminate within %v", executable, killGracePeriod)
+			if err := killProcessGroup(cmd.Process); err != nil {
+				log.Entry().WithError(err).Debug("failed to send SIGKILL")
+			}
+		}
+	}()
+	return func() error {
+		close(finished)
+		wg.Wait()
+		return terminationReason
+	}
+}
+
+func describeTermination(reason error) string {
+	if reason == context.DeadlineExceeded {
+		return "timeout exceeded"
+	}
+	return "execution cancelled"
+}
+
+// setTimeoutErrorDetail provides the last lines of the output as error details of the step
+func setTimeoutErrorDetail(executable string, reason error, tail *lineTail) {
+	details, _ := json.Marshal(map[string]string{
+		"message":     fmt.Sprintf("command '%v' was terminated: %v", executable, describeTermination(reason)),
+		"termination": describeTermination(reason),
+		"outputTail":  log.MaskSecrets(tail.String()),
+	})
+	log.SetFatalErrorDetail(details)
+}
+
+// lineTail keeps the last lines written to it
+type lineTail struct {
+	mutex   sync.Mutex
+	lines   []string
+	current bytes.Buffer
+}
+
+func (t *lineTail) Write(p []byte) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, b := range p {
+		if b == '\n' {
+			t.appendLine()
+			continue
+		}
+		if t.current.Len() < maxTailLineLength {
+			t.current.WriteByte(b)
+		}
+	}
+	return len(p), nil
+}
+
+func (t *lineTail) appendLine() {
+	t.lines = append(t.lines, strings.TrimSuffix(t.current.String(), "\r"))
+	if len(t.lines) > maxTailLines {
+		t.lines = t.lines[len(t.lines)-maxTailLines:]
+	}
+	t.current.Reset()
+}
+
+func (t *lineTail) String() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	lines := t.lines
+	if t.current.Len() > 0 {
+		lines = append(append([]string{}, lines...), t.current.String())
+	}
+	if len(lines) > maxTailLines {
+		lines = lines[len(lines)-maxTailLines:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,108 @@
+//go:build unit
+// +build unit
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunExecutableTimeout(t *testing.T) {
+	ExecCommand = helperCommand
+	defer func() { ExecCommand = exec.Command }()
+	defer log.SetFatalErrorDetail(nil)
+
+	t.Run("timeout terminates command", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+		ex.SetTimeout(time.Second)
+
+		start := time.Now()
+		err := ex.RunExecutable("sleep", "first line", "last line")
+
+		assert.Contains(t, err.Error(), "timed out after 1s")
+		assert.Less(t, time.Since(start), killGracePeriod)
+		assert.Equal(t, 1, ex.GetExitCode())
+		details := map[string]string{}
+		assert.NoError(t, json.Unmarshal(log.GetFatalErrorDetail(), &details))
+		assert.Equal(t, "timeout exceeded", details["termination"])
+		assert.Equal(t, "first line\nlast line", details["outputTail"])
+	})
+
+	t.Run("default timeout", func(t *testing.T) {
+		defer SetDefaultTimeout(0)
+		SetDefaultTimeout(time.Second)
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+
+		err := ex.RunExecutable("sleep")
+
+		assert.Contains(t, err.Error(), "timed out after 1s")
+	})
+
+	t.Run("command ignoring SIGTERM is killed", func(t *testing.T) {
+		defer func(gracePeriod time.Duration) { killGracePeriod = gracePeriod }(killGracePeriod)
+		killGracePeriod = 500 * time.Millisecond
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+		ex.SetTimeout(time.Second)
+
+		start := time.Now()
+		err := ex.RunExecutable("sleep", "--ignore-sigterm")
+
+		assert.Contains(t, err.Error(), "timed out after 1s")
+		assert.Less(t, time.Since(start), 30*time.Second)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ex := Command{stdout: new(bytes.Buffer), stderr: new(bytes.Buffer)}
+		ex.SetContext(ctx)
+		time.AfterFunc(500*time.Millisecond, cancel)
+
+		err := ex.RunExecutable("sleep")
+
+		assert.Contains(t, err.Error(), "was cancelled: context canceled")
+	})
+
+	t.Run("command finishing within timeout", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		ex := Command{stdout: stdout, stderr: new(bytes.Buffer)}
+		ex.SetTimeout(time.Minute)
+
+		err := ex.RunExecutable("echo", "foo")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, ex.GetExitCode())
+		assert.Equal(t, "foo\n", stdout.String())
+	})
+}
+
+func TestLineTail(t *testing.T) {
+	t.Run("keeps last lines", func(t *testing.T) {
+		tail := lineTail{}
+		for i := 1; i <= maxTailLines+10; i++ {
+			fmt.Fprintf(&tail, "line %v\r\n", i)
+		}
+		tail.Write([]byte("incomplete"))
+
+		lines := strings.Split(tail.String(), "\n")
+		assert.Len(t, lines, maxTailLines)
+		assert.Equal(t, "line 12", lines[0])
+		assert.Equal(t, "incomplete", lines[maxTailLines-1])
+	})
+
+	t.Run("cuts long lines", func(t *testing.T) {
+		tail := lineTail{}
+		tail.Write(bytes.Repeat([]byte("a"), 2*maxTailLineLength))
+		assert.Len(t, tail.String(), maxTailLineLength)
+	})
+}
@@ -14,6 +14,7 @@ import (
 
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 
 	"github.com/ghodss/yaml"
 	"github.com/google/go-cmp/cmp"
@@ -27,23 +28,33 @@ type Config struct {
 	Stages           map[string]map[string]interface{} `json:"stages"`
 	Steps            map[string]map[string]interface{} `json:"steps"`
 	Hooks            map[string]interface{}            `json:"hooks,omitempty"`
+	Overlays         []ConfigOverlay                   `json:"overlays,omitempty"`
 	defaults         PipelineDefaults
 	initialized      bool
 	accessTokens     map[string]string
 	openFile         func(s string, t map[string]string) (io.ReadCloser, error)
 	vaultCredentials VaultCredentials
+	source           string
+	aliases          map[string]map[string]string
+	// orchestratorProvider is used to evaluate overlay conditions, activeOverlays holds the evaluation result
+	orchestratorProvider orchestrator.OrchestratorSpecificConfigProviding
+	activeOverlays       []int
 }
 
 // StepConfig defines the structure for merged step configuration
 type StepConfig struct {
 	Config     map[string]interface{}
 	HookConfig map[string]interface{}
+	// Sources contains per parameter all sources which provided a value, the last one being effective
+	Sources map[string][]ParameterSource
 }
 
 // ReadConfig loads config and returns its content
 func (c *Config) ReadConfig(configuration io.ReadCloser) error {
 	defer configuration.Close()
 
+	c.source = sourceName(configuration, "project configuration")
+
 	content, err := ioutil.ReadAll(configuration)
 	if err != nil {
 		return errors.Wrapf(err, "error reading %v", configuration)
@@ -63,26 +74,30 @@ func (c *Config) ApplyAliasConfig(parameters []StepParameters, secrets []StepSec
 		c.copyStepAliasConfig(stepName, stepAliases)
 	}
 	for _, p := range parameters {
-		c.General = setParamValueFromAlias(stepName, c.General, filters.General, p.Name, p.Aliases)
-		if c.Stages[stageName] != nil {
-			c.Stages[stageName] = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, p.Name, p.Aliases)
-		}
-		if c.Steps[stepName] != nil {
-			c.Steps[stepName] = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, p.Name, p.Aliases)
-		}
+		c.applyAliases(stepName, stageName, filters, p.Name, p.Aliases)
 	}
 	for _, s := range secrets {
-		c.General = setParamValueFromAlias(stepName, c.General, filters.General, s.Name, s.Aliases)
-		if c.Stages[stageName] != nil {
-			c.Stages[stageName] = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, s.Name, s.Aliases)
-		}
-		if c.Steps[stepName] != nil {
-			c.Steps[stepName] = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, s.Name, s.Aliases)
-		}
+		c.applyAliases(stepName, stageName, filters, s.Name, s.Aliases)
 	}
 }
 
-func setParamValueFromAlias(stepName string, configMap map[string]interface{}, filter []string, name string, aliases []Alias) map[string]interface{} {
+func (c *Config) applyAliases(stepName, stageName string, filters StepFilters, name string, aliases []Alias) {
+	var alias string
+	c.General, alias = setParamValueFromAlias(stepName, c.General, filters.General, name, aliases)
+	c.recordAlias(sectionGeneral, name, alias)
+	if c.Stages[stageName] != nil {
+		c.Stages[stageName], alias = setParamValueFromAlias(stepName, c.Stages[stageName], filters.Stages, name, aliases)
+		c.recordAlias(sectionStage(stageName), name, alias)
+	}
+	if c.Steps[stepName] != nil {
+		c.Steps[stepName], alias = setParamValueFromAlias(stepName, c.Steps[stepName], filters.Steps, name, aliases)
+		c.recordAlias(sectionStep(stepName), name, alias)
+	}
+}
+
+// setParamValueFromAlias sets the parameter value from the first alias providing a value
+// and returns the name of the alias which has been used, if any
+func setParamValueFromAlias(stepName string, configMap map[string]interface{}, filter []string, name string, aliases []Alias) (map[string]interface{}, string) {
 	if configMap != nil && configMap[name] == nil && sliceContains(filter, name) {
 		for _, a := range aliases {
 			aliasVal := getDeepAliasValue(configMap, a.Name)
@@ -93,11 +108,11 @@ func setParamValueFromAlias(stepName string, configMap map[string]interface{}, f
 				}
 			}
 			if configMap[name] != nil {
-				return configMap
+				return configMap, a.Name
 			}
 		}
 	}
-	return configMap
+	return configMap, ""
 }
 
 func getDeepAliasValue(configMap map[string]interface{}, key string) interface{} {
@@ -129,6 +144,7 @@ func (c *Config) copyStepAliasConfig(stepName string, stepAliases []Alias) {
 				}
 				if c.Steps[stepName][paramName] == nil {
 					c.Steps[stepName][paramName] = paramValue
+					c.recordAlias(sectionStep(stepName), paramName, sectionStep(stepAlias.Name))
 				}
 			}
 		}
@@ -155,6 +171,9 @@ func (c *Config) InitializeConfig(configuration io.ReadCloser, defaults []io.Rea
 			if err != nil {
 				return errors.Wrapf(err, "getting default '%v' failed", f)
 			}
+			if sourceName(fc, "") == "" {
+				fc = &namedReadCloser{ReadCloser: fc, name: f}
+			}
 			defaults = append(defaults, fc)
 		}
 	}
@@ -188,33 +207,47 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 	stepConfig.mixInStepDefaults(parameters)
 
 	// merge parameters provided by Piper environment
-	stepConfig.mixIn(envParameters, filters.All)
-	stepConfig.mixIn(envParameters, ReportingParameters.getReportingFilter())
+	envSource := ParameterSource{Origin: OriginPipelineEnv}
+	stepConfig.mixInWithSource(envParameters, filters.All, envSource, nil)
+	stepConfig.mixInWithSource(envParameters, ReportingParameters.getReportingFilter(), envSource, nil)
 
 	// read defaults & merge general -> steps (-> general -> steps ...)
-	for _, def := range c.defaults.Defaults {
+	for i := range c.defaults.Defaults {
+		def := &c.defaults.Defaults[i]
 		def.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
-		stepConfig.mixIn(def.General, filters.General)
-		stepConfig.mixIn(def.Steps[stepName], filters.Steps)
-		stepConfig.mixIn(def.Stages[stageName], filters.Steps)
-		stepConfig.mixinVaultConfig(parameters, def.General, def.Steps[stepName], def.Stages[stageName])
-		reportingConfig, err := cloneConfig(&def)
+		general, step, stage := def.sections(OriginDefaults, stageName, stepName)
+		stepConfig.mixInSection(general, filters.General)
+		stepConfig.mixInSection(step, filters.Steps)
+		stepConfig.mixInSection(stage, filters.Steps)
+		stepConfig.mixinVaultSections(parameters, general, step, stage)
+		reportingConfig, err := cloneConfig(def)
 		if err != nil {
 			return StepConfig{}, err
 		}
 		reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
-		stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+		stepConfig.mixinReportingSections(reportingConfig.sections(OriginDefaults, stageName, stepName))
 
 		stepConfig.mixInHookConfig(def.Hooks)
 	}
 
 	// read config & merge - general -> steps -> stages
-	stepConfig.mixIn(c.General, filters.General)
-	stepConfig.mixIn(c.Steps[stepName], filters.Steps)
-	stepConfig.mixIn(c.Stages[stageName], filters.Stages)
+	general, step, stage := c.sections(OriginProjectConfig, stageName, stepName)
+	stepConfig.mixInSection(general, filters.General)
+	stepConfig.mixInSection(step, filters.Steps)
+	stepConfig.mixInSection(stage, filters.Stages)
+
+	// merge overlays which are active for the current pipeline run - general -> steps -> stages
+	overlaySections := [][]configSection{}
+	for _, i := range c.getActiveOverlays() {
+		overlayGeneral, overlayStep, overlayStage := c.overlaySections(i, parameters, secrets, filters, stageName, stepName, stepAliases)
+		stepConfig.mixInSection(overlayGeneral, filters.General)
+		stepConfig.mixInSection(overlayStep, filters.Steps)
+		stepConfig.mixInSection(overlayStage, filters.Stages)
+		overlaySections = append(overlaySections, []configSection{overlayGeneral, overlayStep, overlayStage})
+	}
 
 	// merge parameters provided via env vars
-	stepConfig.mixIn(envValues(filters.All), filters.All)
+	stepConfig.mixInWithSource(envValues(filters.All), filters.All, ParameterSource{Origin: OriginEnvVar}, nil)
 
 	// if parameters are provided in JSON format merge them
 	if len(paramJSON) != 0 {
@@ -224,20 +257,28 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 			log.Entry().Warnf("failed to parse parameters from environment: %v", err)
 		} else {
 			// apply aliases
+			paramAliases := map[string]string{}
+			var alias string
 			for _, p := range parameters {
-				params = setParamValueFromAlias(stepName, params, filters.Parameters, p.Name, p.Aliases)
+				params, alias = setParamValueFromAlias(stepName, params, filters.Parameters, p.Name, p.Aliases)
+				if len(alias) > 0 {
+					paramAliases[p.Name] = alias
+				}
 			}
 			for _, s := range secrets {
-				params = setParamValueFromAlias(stepName, params, filters.Parameters, s.Name, s.Aliases)
+				params, alias = setParamValueFromAlias(stepName, params, filters.Parameters, s.Name, s.Aliases)
+				if len(alias) > 0 {
+					paramAliases[s.Name] = alias
+				}
 			}
 
-			stepConfig.mixIn(params, filters.Parameters)
+			stepConfig.mixInWithSource(params, filters.Parameters, ParameterSource{Origin: OriginParametersJSON}, paramAliases)
 		}
 	}
 
 	// merge command line flags
 	if flagValues != nil {
-		stepConfig.mixIn(flagValues, filters.Parameters)
+		stepConfig.mixInWithSource(flagValues, filters.Parameters, ParameterSource{Origin: OriginFlag}, nil)
 	}
 
 	if verbose, ok := stepConfig.Config["verbose"].(bool); ok && verbose {
@@ -246,25 +287,35 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 		log.Entry().Warnf("invalid value for parameter verbose: '%v'", stepConfig.Config["verbose"])
 	}
 
-	stepConfig.mixinVaultConfig(parameters, c.General, c.Steps[stepName], c.Stages[stageName])
+	stepConfig.mixinVaultSections(parameters, general, step, stage)
+	for _, sections := range overlaySections {
+		stepConfig.mixinVaultSections(parameters, sections...)
+	}
 
 	reportingConfig, err := cloneConfig(c)
 	if err != nil {
 		return StepConfig{}, err
 	}
 	reportingConfig.ApplyAliasConfig(ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{})
-	stepConfig.mixinReportingConfig(reportingConfig.General, reportingConfig.Steps[stepName], reportingConfig.Stages[stageName])
+	stepConfig.mixinReportingSections(reportingConfig.sections(OriginProjectConfig, stageName, stepName))
+	for _, i := range c.getActiveOverlays() {
+		stepConfig.mixinReportingSections(reportingConfig.overlaySections(i, ReportingParameters.Parameters, []StepSecrets{}, ReportingParameters.getStepFilters(), stageName, stepName, []Alias{}))
+	}
 
 	// check whether vault should be skipped
 	if skip, ok := stepConfig.Config["skipVault"].(bool); !ok || !skip {
-		// fetch secrets from vault
-		vaultClient, err := getVaultClientFromConfig(stepConfig, c.vaultCredentials)
+		// fetch secrets from vault and the other configured secret providers
+		secretProviders, vaultClient, err := getSecretProviders(stepConfig, c.vaultCredentials)
 		if err != nil {
 			return StepConfig{}, err
 		}
 		if vaultClient != nil {
 			defer vaultClient.MustRevokeToken()
-			resolveAllVaultReferences(&stepConfig, vaultClient, append(parameters, ReportingParameters.Parameters...))
+		}
+		if len(secretProviders) > 0 {
+			resolveAllSecretReferences(&stepConfig, secretProviders, append(parameters, ReportingParameters.secretParameters(stepConfig.Config)...))
+		}
+		if vaultClient != nil {
 			resolveVaultTestCredentialsWrapper(&stepConfig, vaultClient)
 			resolveVaultCredentialsWrapper(&stepConfig, vaultClient)
 		}
@@ -284,6 +335,7 @@ func (c *Config) GetStepConfig(flagValues map[string]interface{}, paramJSON stri
 						subMap, ok := stepConfig.Config[dependentValue.(string)].(map[string]interface{})
 						if ok && subMap[p.Name] != nil {
 							stepConfig.Config[p.Name] = subMap[p.Name]
+							stepConfig.addSource(p.Name, ParameterSource{Origin: OriginConditionalConfig, Section: dependentValue.(string)})
 						}
 					}
 				}
@@ -390,7 +442,7 @@ func httpReadFile(name string, accessTokens map[string]string) (io.ReadCloser, e
 	if err != nil {
 		return nil, err
 	}
-	return response.Body, nil
+	return &namedReadCloser{ReadCloser: response.Body, name: name}, nil
 }
 
 func envValues(filter []string) map[string]interface{} {
@@ -404,12 +456,28 @@ func envValues(filter []string) map[string]interface{} {
 }
 
 func (s *StepConfig) mixIn(mergeData map[string]interface{}, filter []string) {
+	s.mixInWithSource(mergeData, filter, ParameterSource{}, nil)
+}
+
+// mixInWithSource merges the data and records the source for each merged parameter
+func (s *StepConfig) mixInWithSource(mergeData map[string]interface{}, filter []string, source ParameterSource, aliases map[string]string) {
 
 	if s.Config == nil {
 		s.Config = map[string]interface{}{}
 	}
 
-	s.Config = merge(s.Config, filterMap(mergeData, filter))
+	filteredData := filterMap(mergeData, filter)
+	s.Config = merge(s.Config, filteredData)
+
+	for key := range filteredData {
+		keySource := source
+		keySource.Alias = aliases[key]
+		s.addSource(key, keySource)
+	}
+}
+
+func (s *StepConfig) mixInSection(section configSection, filter []string) {
+	s.mixInWithSource(section.data, filter, section.source, section.aliases)
 }
 
 func (s *StepConfig) mixInHookConfig(mergeData map[string]interface{}) {
@@ -433,6 +501,7 @@ func (s *StepConfig) mixInStepDefaults(stepParams []StepParameters) {
 		if p.Default != nil {
 			if len(p.Conditions) == 0 {
 				s.Config[p.Name] = p.Default
+				s.addSource(p.Name, ParameterSource{Origin: OriginStepMetadata})
 			} else {
 				for _, cond := range p.Conditions {
 					for _, param := range cond.Params {
@@ -527,6 +596,7 @@ func cloneConfig(config *Config) (*Config, error) {
 	if err = json.Unmarshal(configJSON, &clone); err != nil {
 		return nil, err
 	}
+	clone.source = config.source
 
 	return clone, nil
 }
@@ -0,0 +1,365 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Severities of findings reported by ValidateConfig
+const (
+	FindingError   = "error"
+	FindingWarning = "warning"
+)
+
+// ConfigFinding describes an issue found in the project configuration
+type ConfigFinding struct {
+	Severity string `json:"severity"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+func (f ConfigFinding) String() string {
+	return fmt.Sprintf("%v:%v: %v: %v: %v", f.Line, f.Column, f.Severity, f.Path, f.Message)
+}
+
+type parameterAlias struct {
+	param      StepParameters
+	deprecated bool
+}
+
+type configValidator struct {
+	steps       map[string]StepData
+	stepAliases map[string]Alias
+	stepNames   map[string]string
+	findings    []ConfigFinding
+}
+
+// ValidateConfig checks the project configuration against the metadata of the provided steps.
+// It reports unknown keys, values of the wrong type or outside of the possible values,
+// parameters configured in a section they are not supported in as well as deprecated aliases.
+func ValidateConfig(configuration io.Reader, steps map[string]StepData) ([]ConfigFinding, error) {
+	content, err := ioutil.ReadAll(configuration)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read configuration")
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, NewParseError(fmt.Sprintf("format of configuration is invalid: %v", err))
+	}
+
+	v := configValidator{steps: steps, stepAliases: map[string]Alias{}, stepNames: map[string]string{}}
+	for name, step := range steps {
+		for _, alias := range step.Metadata.Aliases {
+			v.stepAliases[alias.Name] = alias
+			v.stepNames[alias.Name] = name
+		}
+	}
+
+	if len(document.Content) > 0 {
+		v.validateRoot(document.Content[0])
+	}
+
+	sort.SliceStable(v.findings, func(i, j int) bool {
+		return v.findings[i].Line < v.findings[j].Line
+	})
+	return v.findings, nil
+}
+
+func (v *configValidator) validateRoot(root *yaml.Node) {
+	if root.Kind != yaml.MappingNode {
+		v.add(FindingError, root, "", "configuration needs to be a map")
+		return
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "customDefaults":
+			if !isStringSequence(value) {
+				v.add(FindingError, value, key.Value, "needs to be a list of strings")
+			}
+		case "hooks":
+			if value.Kind != yaml.MappingNode {
+				v.add(FindingError, value, key.Value, "needs to be a map")
+			}
+		case "overlays":
+			v.validateOverlays(value)
+		default:
+			v.validateConfigSection(key, value, "")
+		}
+	}
+}
+
+// validateConfigSection checks the general, stages and steps sections of the configuration or of an overlay
+func (v *configValidator) validateConfigSection(key, value *yaml.Node, pathPrefix string) {
+	path := pathPrefix + key.Value
+	switch key.Value {
+	case "general":
+		v.validateSection(value, path, scopeGeneral, v.allParameters())
+	case "stages":
+		v.forEachEntry(value, path, func(stageKey, stage *yaml.Node) {
+			v.validateSection(stage, path+"/"+stageKey.Value, scopeStages, v.allParameters())
+		})
+	case "steps":
+		v.forEachEntry(value, path, func(stepKey, step *yaml.Node) {
+			v.validateStep(stepKey, step, path)
+		})
+	default:
+		v.add(FindingWarning, key, path, fmt.Sprintf("unknown section '%v'", key.Value))
+	}
+}
+
+func (v *configValidator) validateOverlays(overlays *yaml.Node) {
+	if overlays.Kind != yaml.SequenceNode {
+		v.add(FindingError, overlays, "overlays", "needs to be a list")
+		return
+	}
+	conditions := map[string]StepParameters{
+		"branch":       {Name: "branch", Type: "string"},
+		"orchestrator": {Name: "orchestrator", Type: "string"},
+		"pullRequest":  {Name: "pullRequest", Type: "bool"},
+		"buildReason":  {Name: "buildReason", Type: "string"},
+	}
+	for i, overlay := range overlays.Content {
+		path := fmt.Sprintf("overlays[%v]", i)
+		v.forEachEntry(overlay, path, func(key, value *yaml.Node) {
+			if key.Value != "when" {
+				v.validateConfigSection(key, value, path+"/")
+				return
+			}
+			v.forEachEntry(value, path+"/when", func(conditionKey, conditionValue *yaml.Node) {
+				condition, ok := conditions[conditionKey.Value]
+				if !ok {
+					v.add(FindingError, conditionKey, path+"/when/"+conditionKey.Value, fmt.Sprintf("unknown overlay condition '%v'", conditionKey.Value))
+					return
+				}
+				v.validateValue(conditionValue, path+"/when/"+conditionKey.Value, []StepParameters{condition})
+			})
+		})
+	}
+}
+
+func (v *configValidator) validateStep(stepKey, step *yaml.Node, stepsPath string) {
+	path := stepsPath + "/" + stepKey.Value
+	stepName := stepKey.Value
+	if alias, ok := v.stepAliases[stepName]; ok {
+		stepName = v.stepNames[stepKey.Value]
+		if alias.Deprecated {
+			v.add(FindingWarning, stepKey, path, fmt.Sprintf("step name '%v' is DEPRECATED, use '%v' instead", stepKey.Value, stepName))
+		}
+	}
+	metadata, ok := v.steps[stepName]
+	if !ok {
+		v.add(FindingWarning, stepKey, path, fmt.Sprintf("unknown step '%v'", stepKey.Value))
+		return
+	}
+	v.validateSection(step, path, scopeSteps, metadata.Spec.Inputs.Parameters, metadata.Spec.Inputs.Secrets)
+}
+
+// validateSection checks all entries of a general, stage or step section against the given parameters
+func (v *configValidator) validateSection(section *yaml.Node, path, scope string, params []StepParameters, secrets ...[]StepSecrets) {
+	if section.Kind != yaml.MappingNode {
+		if !isNull(section) {
+			v.add(FindingError, section, path, "needs to be a map")
+		}
+		return
+	}
+
+	known := map[string][]StepParameters{}
+	aliases := map[string][]parameterAlias{}
+	additionalKeys := map[string]bool{}
+	for _, param := range append(params, frameworkParameters()...) {
+		known[param.Name] = append(known[param.Name], param)
+		for _, alias := range param.Aliases {
+			aliases[alias.Name] = append(aliases[alias.Name], parameterAlias{param: param, deprecated: alias.Deprecated})
+		}
+		for _, ref := range param.ResourceRef {
+			if ref.Type == "vaultSecret" || ref.Type == "vaultSecretFile" {
+				additionalKeys[ref.Name] = true
+			}
+		}
+		for _, condition := range param.Conditions {
+			for _, conditionParam := range condition.Params {
+				additionalKeys[conditionParam.Value] = true
+			}
+		}
+	}
+	for _, stepSecrets := range secrets {
+		for _, secret := range stepSecrets {
+			additionalKeys[secret.Name] = true
+			for _, alias := range secret.Aliases {
+				additionalKeys[alias.Name] = true
+			}
+		}
+	}
+	if scope != scopeSteps {
+		for _, step := range v.steps {
+			for _, secret := range step.Spec.Inputs.Secrets {
+				additionalKeys[secret.Name] = true
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		key, value := section.Content[i], section.Content[i+1]
+		keyPath := path + "/" + key.Value
+
+		candidates := known[key.Value]
+		if len(candidates) == 0 {
+			for _, alias := range aliases[key.Value] {
+				candidates = append(candidates, alias.param)
+				if alias.deprecated {
+					v.add(FindingWarning, key, keyPath, fmt.Sprintf("the parameter '%v' is DEPRECATED, use '%v' instead", key.Value, alias.param.Name))
+				}
+			}
+		}
+		if len(candidates) == 0 {
+			if !additionalKeys[key.Value] && !sliceContains([]string{vaultSecretName}, key.Value) {
+				v.add(FindingWarning, key, keyPath, fmt.Sprintf("unknown parameter '%v'", key.Value))
+			}
+			continue
+		}
+
+		scoped := []StepParameters{}
+		for _, param := range candidates {
+			// parameters without scope are handled by the framework and can be used in all sections
+			if len(param.Scope) == 0 || hasScope(param, scope) {
+				scoped = append(scoped, param)
+			}
+		}
+		if len(scoped) == 0 {
+			v.add(FindingError, key, keyPath, fmt.Sprintf("the parameter '%v' is not supported in this section, supported scopes are %v", key.Value, strings.Join(candidates[0].Scope, ", ")))
+			continue
+		}
+
+		v.validateValue(value, keyPath, scoped)
+	}
+}
+
+// validateValue checks a value against the parameter definitions, it is valid if it matches one of them
+func (v *configValidator) validateValue(value *yaml.Node, path string, params []StepParameters) {
+	if isNull(value) {
+		return
+	}
+	var message string
+	for _, param := range params {
+		message = checkParameterValue(value, param)
+		if len(message) == 0 {
+			if len(param.DeprecationMessage) > 0 {
+				v.add(FindingWarning, value, path, fmt.Sprintf("the parameter '%v' is DEPRECATED: %v", param.Name, param.DeprecationMessage))
+			}
+			return
+		}
+	}
+	v.add(FindingError, value, path, message)
+}
+
+// checkParameterValue returns a message if the value is not valid for the parameter
+// type conversions done when running a step (e.g. "true" to boolean) are considered
+func checkParameterValue(value *yaml.Node, param StepParameters) string {
+	valid := true
+	switch param.Type {
+	case "string":
+		valid = value.Kind == yaml.ScalarNode && value.Tag != "!!bool" && !isYAML11Bool(value)
+	case "bool":
+		valid = value.Kind == yaml.ScalarNode && (value.Tag == "!!bool" || isYAML11Bool(value) || strings.EqualFold(value.Value, "true") || strings.EqualFold(value.Value, "false"))
+	case "int":
+		var number float64
+		valid = value.Kind == yaml.ScalarNode && (value.Tag == "!!int" || value.Tag == "!!float" && value.Decode(&number) == nil && number == float64(int(number)))
+	case "[]string":
+		valid = isStringSequence(value)
+	case "map[string]interface{}":
+		valid = value.Kind == yaml.MappingNode
+	case "[]map[string]interface{}":
+		valid = value.Kind == yaml.SequenceNode
+		for _, item := range value.Content {
+			valid = valid && item.Kind == yaml.MappingNode
+		}
+	}
+	if !valid {
+		return fmt.Sprintf("value of parameter '%v' needs to be of type %v", param.Name, param.Type)
+	}
+
+	if len(param.PossibleValues) > 0 {
+		values := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			values = value.Content
+		}
+		for _, item := range values {
+			if !isPossibleValue(item.Value, param.PossibleValues) {
+				return fmt.Sprintf("value '%v' of parameter '%v' is not one of the possible values %v", item.Value, param.Name, param.PossibleValues)
+			}
+		}
+	}
+	return ""
+}
+
+func (v *configValidator) allParameters() []StepParameters {
+	params := []StepParameters{}
+	for _, stepName := range sortedStepNames(v.steps) {
+		params = append(params, v.steps[stepName].Spec.Inputs.Parameters...)
+	}
+	return params
+}
+
+func (v *configValidator) forEachEntry(node *yaml.Node, path string, f func(key, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		if !isNull(node) {
+			v.add(FindingError, node, path, "needs to be a map")
+		}
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		f(node.Content[i], node.Content[i+1])
+	}
+}
+
+func (v *configValidator) add(severity string, node *yaml.Node, path, message string) {
+	v.findings = append(v.findings, ConfigFinding{Severity: severity, Line: node.Line, Column: node.Column, Path: path, Message: message})
+}
+
+func isPossibleValue(value string, possibleValues []interface{}) bool {
+	for _, possibleValue := range possibleValues {
+		if fmt.Sprint(possibleValue) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func isStringSequence(node *yaml.Node) bool {
+	if node.Kind != yaml.SequenceNode {
+		return false
+	}
+	for _, item := range node.Content {
+		if item.Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+	return true
+}
+
+// isYAML11Bool checks for boolean values like 'yes' or 'off' which are only booleans in YAML 1.1
+// the configuration is read using YAML 1.1 when running a step
+func isYAML11Bool(node *yaml.Node) bool {
+	if node.Kind != yaml.ScalarNode || node.Style != 0 {
+		return false
+	}
+	switch node.Value {
+	case "y", "Y", "yes", "Yes", "YES", "n", "N", "no", "No", "NO", "on", "On", "ON", "off", "Off", "OFF":
+		return true
+	}
+	return false
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
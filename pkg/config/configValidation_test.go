@@ -0,0 +1,111 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validationTestMetadata() map[string]StepData {
+	return map[string]StepData{
+		"mavenBuild": {
+			Metadata: StepMetadata{Name: "mavenBuild", Aliases: []Alias{{Name: "mavenBuildOld", Deprecated: true}}},
+			Spec: StepSpec{
+				Inputs: StepInputs{
+					Parameters: []StepParameters{
+						{Name: "pomPath", Type: "string", Scope: []string{"PARAMETERS", "STEPS"}},
+						{Name: "flatten", Type: "bool", Scope: []string{"PARAMETERS", "STEPS"}},
+						{Name: "goals", Type: "[]string", Scope: []string{"PARAMETERS"}},
+						{Name: "buildTool", Type: "string", Scope: []string{"GENERAL", "STEPS", "STAGES"}, PossibleValues: []interface{}{"maven", "npm"}},
+						{Name: "retries", Type: "int", Scope: []string{"GENERAL", "STEPS"}, Aliases: []Alias{{Name: "maxRetries", Deprecated: true}}},
+					},
+					Secrets: []StepSecrets{{Name: "mavenCredentialsId"}},
+				},
+			},
+		},
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	t.Run("valid configuration", func(t *testing.T) {
+		config := `general:
+  buildTool: maven
+  vaultBasePath: piper
+  verbose: true
+stages:
+  Build:
+    buildTool: npm
+steps:
+  mavenBuild:
+    pomPath: pom.xml
+    flatten: "true"
+    retries: 3
+    mavenCredentialsId: creds
+`
+		findings, err := ValidateConfig(strings.NewReader(config), validationTestMetadata())
+		assert.NoError(t, err)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		config := `general:
+  buildTool: gradle
+  goals: [install]
+  maxRetries: 1
+steps:
+  mavenBuild:
+    pomPath: [pom.xml]
+    flatten: maybe
+    flaten: true
+    retries: 1.5
+  mavenBuildOld:
+    flatten: yes
+  unknownStep:
+    foo: bar
+fooBar: {}
+`
+		findings, err := ValidateConfig(strings.NewReader(config), validationTestMetadata())
+		assert.NoError(t, err)
+		assert.Equal(t, []ConfigFinding{
+			{Severity: FindingError, Line: 2, Column: 14, Path: "general/buildTool", Message: "value 'gradle' of parameter 'buildTool' is not one of the possible values [maven npm]"},
+			{Severity: FindingError, Line: 3, Column: 3, Path: "general/goals", Message: "the parameter 'goals' is not supported in this section, supported scopes are PARAMETERS"},
+			{Severity: FindingWarning, Line: 4, Column: 3, Path: "general/maxRetries", Message: "the parameter 'maxRetries' is DEPRECATED, use 'retries' instead"},
+			{Severity: FindingError, Line: 7, Column: 14, Path: "steps/mavenBuild/pomPath", Message: "value of parameter 'pomPath' needs to be of type string"},
+			{Severity: FindingError, Line: 8, Column: 14, Path: "steps/mavenBuild/flatten", Message: "value of parameter 'flatten' needs to be of type bool"},
+			{Severity: FindingWarning, Line: 9, Column: 5, Path: "steps/mavenBuild/flaten", Message: "unknown parameter 'flaten'"},
+			{Severity: FindingError, Line: 10, Column: 14, Path: "steps/mavenBuild/retries", Message: "value of parameter 'retries' needs to be of type int"},
+			{Severity: FindingWarning, Line: 11, Column: 3, Path: "steps/mavenBuildOld", Message: "step name 'mavenBuildOld' is DEPRECATED, use 'mavenBuild' instead"},
+			{Severity: FindingWarning, Line: 13, Column: 3, Path: "steps/unknownStep", Message: "unknown step 'unknownStep'"},
+			{Severity: FindingWarning, Line: 15, Column: 1, Path: "fooBar", Message: "unknown section 'fooBar'"},
+		}, findings)
+	})
+
+	t.Run("overlays", func(t *testing.T) {
+		config := `overlays:
+- when:
+    branch: release/*
+    pullRequest: maybe
+    tag: v1
+  steps:
+    mavenBuild:
+      flatten: false
+      flaten: true
+`
+		findings, err := ValidateConfig(strings.NewReader(config), validationTestMetadata())
+		assert.NoError(t, err)
+		assert.Equal(t, []ConfigFinding{
+			{Severity: FindingError, Line: 4, Column: 18, Path: "overlays[0]/when/pullRequest", Message: "value of parameter 'pullRequest' needs to be of type bool"},
+			{Severity: FindingError, Line: 5, Column: 5, Path: "overlays[0]/when/tag", Message: "unknown overlay condition 'tag'"},
+			{Severity: FindingWarning, Line: 9, Column: 7, Path: "overlays[0]/steps/mavenBuild/flaten", Message: "unknown parameter 'flaten'"},
+		}, findings)
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		_, err := ValidateConfig(strings.NewReader("general: [\n"), validationTestMetadata())
+		assert.Error(t, err)
+	})
+}
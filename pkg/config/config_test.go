@@ -705,6 +705,12 @@ func TestCopyStepAliasConfig(t *testing.T) {
 					"p4": "p4_stepAlias2",
 				},
 			},
+			aliases: map[string]map[string]string{
+				"steps/step1": {
+					"p3": "steps/stepAlias1",
+					"p4": "steps/stepAlias2",
+				},
+			},
 		}
 
 		c.copyStepAliasConfig("step1", []Alias{{Name: "stepAlias1"}, {Name: "stepAlias2"}})
@@ -729,6 +735,11 @@ func TestCopyStepAliasConfig(t *testing.T) {
 					"p2": "p2_stepAlias",
 				},
 			},
+			aliases: map[string]map[string]string{
+				"steps/step1": {
+					"p2": "steps/stepAlias1",
+				},
+			},
 		}
 
 		c.copyStepAliasConfig("step1", []Alias{{Name: "stepAlias1"}})
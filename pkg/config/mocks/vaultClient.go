@@ -4,7 +4,7 @@ package mocks
 
 import mock "github.com/stretchr/testify/mock"
 
-// VaultMock is an autogenerated mock type for the vaultClient type
+// VaultMock is an autogenerated mock type for the VaultClient type
 type VaultMock struct {
 	mock.Mock
 }
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+
+	"github.com/bmatcuk/doublestar"
+)
+
+// ConfigOverlay defines configuration which is only considered in case all conditions defined in When match the current pipeline run
+type ConfigOverlay struct {
+	When    OverlayCondition                  `json:"when"`
+	General map[string]interface{}            `json:"general,omitempty"`
+	Stages  map[string]map[string]interface{} `json:"stages,omitempty"`
+	Steps   map[string]map[string]interface{} `json:"steps,omitempty"`
+}
+
+// OverlayCondition defines when a configuration overlay is active, empty values are not considered
+type OverlayCondition struct {
+	// Branch is a glob pattern, e.g. release/*
+	Branch string `json:"branch,omitempty"`
+	// Orchestrator is the type of the orchestrator, e.g. Jenkins, Azure, GitHubActions, GitLab or Tekton
+	Orchestrator string `json:"orchestrator,omitempty"`
+	PullRequest  *bool  `json:"pullRequest,omitempty"`
+	// BuildReason is the reason of the build as unified across orchestrators, e.g. Manual, Schedule, PullRequest
+	BuildReason string `json:"buildReason,omitempty"`
+}
+
+// SetOrchestratorProvider sets the provider used to evaluate the conditions of configuration overlays
+// If not set, the provider of the detected orchestrator is used.
+func (c *Config) SetOrchestratorProvider(provider orchestrator.OrchestratorSpecificConfigProviding) {
+	c.orchestratorProvider = provider
+}
+
+// getActiveOverlays returns the indices of all overlays which are active for the current pipeline run
+// the evaluation is done only once since orchestrator information may require API calls
+func (c *Config) getActiveOverlays() []int {
+	if c.activeOverlays != nil || len(c.Overlays) == 0 {
+		return c.activeOverlays
+	}
+	if c.orchestratorProvider == nil {
+		provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+		if err != nil {
+			log.Entry().WithError(err).Debug("evaluating configuration overlays without orchestrator information")
+		}
+		c.orchestratorProvider = provider
+	}
+
+	c.activeOverlays = []int{}
+	for i, overlay := range c.Overlays {
+		active, err := overlay.When.matches(c.orchestratorProvider)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("failed to evaluate condition of configuration overlay %v, overlay is ignored", i)
+			continue
+		}
+		if active {
+			log.Entry().Debugf("Configuration overlay %v is active", i)
+			c.activeOverlays = append(c.activeOverlays, i)
+		}
+	}
+	return c.activeOverlays
+}
+
+func (o *OverlayCondition) matches(provider orchestrator.OrchestratorSpecificConfigProviding) (bool, error) {
+	if len(o.Orchestrator) > 0 && !strings.EqualFold(o.Orchestrator, provider.OrchestratorType()) {
+		return false, nil
+	}
+	if len(o.Branch) > 0 {
+		match, err := doublestar.Match(o.Branch, provider.GetBranch())
+		if err != nil {
+			return false, fmt.Errorf("invalid branch pattern '%v': %w", o.Branch, err)
+		}
+		if !match {
+			return false, nil
+		}
+	}
+	if o.PullRequest != nil && *o.PullRequest != provider.IsPullRequest() {
+		return false, nil
+	}
+	if len(o.BuildReason) > 0 && !strings.EqualFold(o.BuildReason, provider.GetBuildReason()) {
+		return false, nil
+	}
+	return true, nil
+}
+
+// overlaySections returns the general, step and stage section of an overlay considering the aliases of the step
+func (c *Config) overlaySections(index int, parameters []StepParameters, secrets []StepSecrets, filters StepFilters, stageName, stepName string, stepAliases []Alias) (general, step, stage configSection) {
+	overlay := c.Overlays[index]
+	overlayConfig := Config{General: overlay.General, Stages: overlay.Stages, Steps: overlay.Steps, source: c.source}
+	overlayConfig.ApplyAliasConfig(parameters, secrets, filters, stageName, stepName, stepAliases)
+	general, step, stage = overlayConfig.sections(OriginProjectConfig, stageName, stepName)
+
+	prefix := fmt.Sprintf("overlays[%v]/", index)
+	general.source.Section = prefix + general.source.Section
+	step.source.Section = prefix + step.source.Section
+	stage.source.Section = prefix + stage.source.Section
+	return
+}
@@ -0,0 +1,128 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/stretchr/testify/assert"
+)
+
+type overlayOrchestratorMock struct {
+	orchestrator.UnknownOrchestratorConfigProvider
+	orchestratorType string
+	branch           string
+	pullRequest      bool
+	buildReason      string
+}
+
+func (o *overlayOrchestratorMock) OrchestratorType() string { return o.orchestratorType }
+func (o *overlayOrchestratorMock) GetBranch() string        { return o.branch }
+func (o *overlayOrchestratorMock) IsPullRequest() bool      { return o.pullRequest }
+func (o *overlayOrchestratorMock) GetBuildReason() string   { return o.buildReason }
+
+func TestOverlayConditionMatches(t *testing.T) {
+	truth := true
+	provider := &overlayOrchestratorMock{orchestratorType: "Azure", branch: "release/1.0", buildReason: "Schedule"}
+
+	tt := []struct {
+		name     string
+		when     OverlayCondition
+		expected bool
+	}{
+		{name: "no condition", when: OverlayCondition{}, expected: true},
+		{name: "orchestrator matches", when: OverlayCondition{Orchestrator: "azure"}, expected: true},
+		{name: "orchestrator does not match", when: OverlayCondition{Orchestrator: "Jenkins"}, expected: false},
+		{name: "branch matches", when: OverlayCondition{Branch: "release/*"}, expected: true},
+		{name: "branch does not match", when: OverlayCondition{Branch: "main"}, expected: false},
+		{name: "pull request does not match", when: OverlayCondition{PullRequest: &truth}, expected: false},
+		{name: "build reason matches", when: OverlayCondition{BuildReason: "Schedule"}, expected: true},
+		{name: "all conditions need to match", when: OverlayCondition{Branch: "release/*", BuildReason: "Manual"}, expected: false},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			active, err := test.when.matches(provider)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, active)
+		})
+	}
+
+	t.Run("invalid branch pattern", func(t *testing.T) {
+		_, err := (&OverlayCondition{Branch: "release/["}).matches(provider)
+		assert.EqualError(t, err, "invalid branch pattern 'release/[': syntax error in pattern")
+	})
+}
+
+func TestGetStepConfigWithOverlays(t *testing.T) {
+	testConfig := `general:
+  p1: p1_general
+steps:
+  step1:
+    p2: p2_step
+stages:
+  stage1:
+    p3: p3_stage
+overlays:
+- when:
+    branch: release/*
+    orchestrator: Azure
+  general:
+    p1: p1_overlay_release
+  stages:
+    stage1:
+      p3: p3_overlay_release
+- when:
+    pullRequest: true
+  steps:
+    step1:
+      p2: p2_overlay_pr
+- when:
+    branch: main
+  steps:
+    step1:
+      oldP2: p2_overlay_alias
+`
+	filters := StepFilters{
+		General: []string{"p1", "p2", "p3"},
+		Steps:   []string{"p1", "p2", "p3"},
+		Stages:  []string{"p1", "p2", "p3"},
+	}
+	metadata := StepData{Spec: StepSpec{Inputs: StepInputs{Parameters: []StepParameters{
+		{Name: "p1"}, {Name: "p2", Aliases: []Alias{{Name: "oldP2"}}}, {Name: "p3"},
+	}}}}
+
+	t.Run("release branch", func(t *testing.T) {
+		c := Config{}
+		c.SetOrchestratorProvider(&overlayOrchestratorMock{orchestratorType: "Azure", branch: "release/1.0"})
+		stepConfig, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader(testConfig)), []io.ReadCloser{}, false, filters, metadata, nil, "stage1", "step1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"p1": "p1_overlay_release", "p2": "p2_step", "p3": "p3_overlay_release"}, stepConfig.Config)
+		assert.Equal(t, ParameterSource{Origin: OriginProjectConfig, File: "project configuration", Section: "overlays[0]/stages/stage1"}, stepConfig.Sources["p3"][1])
+	})
+
+	t.Run("pull request", func(t *testing.T) {
+		c := Config{}
+		c.SetOrchestratorProvider(&overlayOrchestratorMock{orchestratorType: "GitHubActions", branch: "feature", pullRequest: true})
+		stepConfig, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader(testConfig)), []io.ReadCloser{}, false, filters, metadata, nil, "stage1", "step1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"p1": "p1_general", "p2": "p2_overlay_pr", "p3": "p3_stage"}, stepConfig.Config)
+	})
+
+	t.Run("alias in overlay", func(t *testing.T) {
+		c := Config{}
+		c.SetOrchestratorProvider(&overlayOrchestratorMock{orchestratorType: "Jenkins", branch: "main"})
+		stepConfig, err := c.GetStepConfig(nil, "", ioutil.NopCloser(strings.NewReader(testConfig)), []io.ReadCloser{}, false, filters, metadata, nil, "stage1", "step1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "p2_overlay_alias", stepConfig.Config["p2"])
+		assert.Equal(t, "oldP2", stepConfig.Sources["p2"][1].Alias)
+	})
+}
@@ -1,11 +1,14 @@
 package config
 
+// clientCertificateSecretName is the parameter holding the name of the secret which contains the client certificate for mutual TLS
+const clientCertificateSecretName = "httpClientCertificateVaultSecretName"
+
 // ReportingParams holds reporting parameters
 type ReportingParams struct {
 	Parameters []StepParameters
 }
 
-// ReportingParameters is a global variable with values of reporting parameters
+// ReportingParameters is a global variable with values of reporting parameters and further parameters available for all steps
 var ReportingParameters = ReportingParams{
 	Parameters: []StepParameters{
 		{
@@ -35,9 +38,63 @@ var ReportingParameters = ReportingParams{
 		{
 			Name: "gcsSubFolder",
 		},
+		// the client certificate is used for mutual TLS by all http clients, thus it is available for all steps like the reporting parameters.
+		// It is only resolved from the secret providers in case the name of the secret is configured, see secretParameters.
+		{
+			Name: "httpClientCertificate",
+			ResourceRef: []ResourceReference{
+				{
+					Name: clientCertificateSecretName,
+					Type: "vaultSecretFile",
+				},
+			},
+		},
+		{
+			Name: "httpClientKey",
+			ResourceRef: []ResourceReference{
+				{
+					Name: clientCertificateSecretName,
+					Type: "vaultSecretFile",
+				},
+			},
+		},
+		{
+			Name: "httpClientKeyPassword",
+			ResourceRef: []ResourceReference{
+				{
+					Name: clientCertificateSecretName,
+					Type: "vaultSecret",
+				},
+			},
+		},
 	},
 }
 
+// secretParameters returns the parameters whose secret references are resolved for a step.
+// Parameters referring to the client certificate are omitted unless its secret is configured,
+// thus steps without mutual TLS do not look up a non-existing secret.
+func (r ReportingParams) secretParameters(config map[string]interface{}) []StepParameters {
+	if secretName, ok := config[clientCertificateSecretName].(string); ok && len(secretName) > 0 {
+		return r.Parameters
+	}
+	parameters := []StepParameters{}
+	for _, param := range r.Parameters {
+		if !referencesResource(param, clientCertificateSecretName) {
+			parameters = append(parameters, param)
+		}
+	}
+	return parameters
+}
+
+func referencesResource(param StepParameters, name string) bool {
+	for _, ref := range param.ResourceRef {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetResourceParameters retrieves reporting parameters from a named pipeline resource with a defined path
 func (r ReportingParams) GetResourceParameters(path, name string) map[string]interface{} {
 	resourceParams := map[string]interface{}{}
@@ -74,8 +131,16 @@ func (r ReportingParams) getReportingFilter() []string {
 }
 
 func (s *StepConfig) mixinReportingConfig(configs ...map[string]interface{}) {
-	reportingFilter := ReportingParameters.getReportingFilter()
+	sections := []configSection{}
 	for _, config := range configs {
-		s.mixIn(config, reportingFilter)
+		sections = append(sections, configSection{data: config})
+	}
+	s.mixinReportingSections(sections...)
+}
+
+func (s *StepConfig) mixinReportingSections(sections ...configSection) {
+	reportingFilter := ReportingParameters.getReportingFilter()
+	for _, section := range sections {
+		s.mixInSection(section, reportingFilter)
 	}
 }
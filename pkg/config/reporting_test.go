@@ -199,3 +199,23 @@ func TestReportingParams_GetReportingFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestReportingParams_SecretParameters(t *testing.T) {
+	names := func(params []StepParameters) []string {
+		result := []string{}
+		for _, param := range params {
+			result = append(result, param.Name)
+		}
+		return result
+	}
+
+	t.Run("without client certificate secret", func(t *testing.T) {
+		params := ReportingParameters.secretParameters(map[string]interface{}{"httpClientCertificate": "certs/client.pem"})
+		assert.Equal(t, []string{"gcpJsonKeyFilePath", "gcsFolderPath", "gcsBucketId", "gcsSubFolder"}, names(params))
+	})
+
+	t.Run("with client certificate secret", func(t *testing.T) {
+		params := ReportingParameters.secretParameters(map[string]interface{}{"httpClientCertificateVaultSecretName": "http-client-certificate"})
+		assert.Equal(t, []string{"gcpJsonKeyFilePath", "gcsFolderPath", "gcsBucketId", "gcsSubFolder", "httpClientCertificate", "httpClientKey", "httpClientKeyPassword"}, names(params))
+	})
+}
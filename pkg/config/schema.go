@@ -0,0 +1,287 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	scopeGeneral = "GENERAL"
+	scopeStages  = "STAGES"
+	scopeSteps   = "STEPS"
+)
+
+// GenerateConfigSchema creates a JSON schema (draft-07) for the project configuration (e.g. .pipeline/config.yml)
+// based on the metadata of the provided steps.
+func GenerateConfigSchema(steps map[string]StepData) map[string]interface{} {
+	general := map[string]interface{}{}
+	stages := map[string]interface{}{}
+	stepSchemas := map[string]interface{}{}
+
+	for _, stepName := range sortedStepNames(steps) {
+		step := steps[stepName]
+		stepProperties := map[string]interface{}{}
+		for _, param := range step.Spec.Inputs.Parameters {
+			if hasScope(param, scopeGeneral) {
+				addParameterSchema(general, param)
+			}
+			if hasScope(param, scopeStages) {
+				addParameterSchema(stages, param)
+			}
+			if hasScope(param, scopeSteps) {
+				addParameterSchema(stepProperties, param)
+			}
+		}
+		for _, secret := range step.Spec.Inputs.Secrets {
+			addSecretSchema(general, secret)
+			addSecretSchema(stages, secret)
+			addSecretSchema(stepProperties, secret)
+		}
+		stepSchema := map[string]interface{}{
+			"type":        "object",
+			"description": step.Metadata.Description,
+			"properties":  stepProperties,
+		}
+		stepSchemas[stepName] = stepSchema
+		for _, alias := range step.Metadata.Aliases {
+			stepSchemas[alias.Name] = map[string]interface{}{
+				"type":        "object",
+				"description": aliasDescription(stepName, alias),
+				"properties":  stepProperties,
+			}
+		}
+	}
+
+	addFrameworkSchema(general)
+	addFrameworkSchema(stages)
+	for _, stepSchema := range stepSchemas {
+		addFrameworkSchema(stepSchema.(map[string]interface{})["properties"].(map[string]interface{}))
+	}
+
+	generalSchema := map[string]interface{}{
+		"description": "Configuration valid for all steps",
+		"type":        "object",
+		"properties":  general,
+	}
+	stagesSchema := map[string]interface{}{
+		"description": "Configuration valid for all steps of a stage",
+		"type":        "object",
+		"additionalProperties": map[string]interface{}{
+			"type":       "object",
+			"properties": stages,
+		},
+	}
+	stepsSchema := map[string]interface{}{
+		"description":          "Step specific configuration",
+		"type":                 "object",
+		"properties":           stepSchemas,
+		"additionalProperties": map[string]interface{}{"type": "object"},
+	}
+
+	return map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"title":       "Project \"Piper\" configuration",
+		"description": "Configuration of a project using project \"Piper\", typically located at .pipeline/config.yml",
+		"type":        "object",
+		"properties": map[string]interface{}{
+			"customDefaults": map[string]interface{}{
+				"description": "List of custom default configuration files or URLs, the last one having the highest precedence",
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+			"general": generalSchema,
+			"stages":  stagesSchema,
+			"steps":   stepsSchema,
+			"overlays": map[string]interface{}{
+				"description": "Configuration which is only considered in case all conditions defined in 'when' match the current pipeline run",
+				"type":        "array",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"when": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"branch":       map[string]interface{}{"type": "string", "description": "Glob pattern of the branch, e.g. release/*"},
+								"orchestrator": map[string]interface{}{"type": "string", "description": "Type of the orchestrator, e.g. Jenkins, Azure, GitHubActions, GitLab or Tekton"},
+								"pullRequest":  map[string]interface{}{"type": "boolean"},
+								"buildReason":  map[string]interface{}{"type": "string", "description": "Reason of the build, e.g. Manual, Schedule, PullRequest"},
+							},
+							"additionalProperties": false,
+						},
+						"general": generalSchema,
+						"stages":  stagesSchema,
+						"steps":   stepsSchema,
+					},
+				},
+			},
+			"hooks": map[string]interface{}{
+				"description": "Configuration of hooks, e.g. for Sentry, Splunk, OpenTelemetry or Prometheus",
+				"type":        "object",
+			},
+		},
+	}
+}
+
+// ParameterSchema returns the JSON schema of a single step parameter
+func ParameterSchema(param StepParameters) map[string]interface{} {
+	schema := map[string]interface{}{}
+	if len(param.Description) > 0 {
+		schema["description"] = param.Description
+	}
+	switch param.Type {
+	case "string":
+		schema["type"] = "string"
+	case "bool":
+		schema["type"] = "boolean"
+	case "int":
+		schema["type"] = "integer"
+	case "[]string":
+		schema["type"] = "array"
+		schema["items"] = map[string]interface{}{"type": "string"}
+	case "map[string]interface{}":
+		schema["type"] = "object"
+	case "[]map[string]interface{}":
+		schema["type"] = "array"
+		schema["items"] = map[string]interface{}{"type": "object"}
+	}
+	if len(param.PossibleValues) > 0 {
+		if param.Type == "[]string" {
+			schema["items"] = map[string]interface{}{"type": "string", "enum": param.PossibleValues}
+		} else {
+			schema["enum"] = param.PossibleValues
+		}
+	}
+	if param.Default != nil {
+		schema["default"] = param.Default
+	}
+	if len(param.DeprecationMessage) > 0 {
+		schema["deprecated"] = true
+		schema["description"] = fmt.Sprintf("%v (DEPRECATED: %v)", param.Description, param.DeprecationMessage)
+	}
+	return schema
+}
+
+// addFrameworkSchema adds parameters which are available for all steps, e.g. for Vault access and reporting
+func addFrameworkSchema(properties map[string]interface{}) {
+	for _, param := range frameworkParameters() {
+		addParameterSchema(properties, param)
+	}
+}
+
+// frameworkParameters lists the parameters which are considered by the configuration handling independent of the step
+func frameworkParameters() []StepParameters {
+	params := []StepParameters{
+		{Name: "verbose", Type: "bool", Description: "Activates debug output"},
+		{Name: "collectTelemetryData", Type: "bool", Description: "Activates the collection of telemetry data"},
+		{Name: "commandTimeout", Type: "string", Description: "Maximum duration of each command executed by the step, e.g. '30m'"},
+		{Name: "commandOutputLimit", Type: "string", Description: "Maximum size of the output captured per output stream of each command executed by the step, e.g. '100MB'"},
+		{Name: "errorClassificationRules", Type: "[]map[string]interface{}", Description: "Rules categorizing errors based on the command output, each one with a regular expression 'pattern', a 'category' and an optional 'hint'"},
+	}
+	for _, name := range vaultFilter {
+		if name != vaultSecretName {
+			params = append(params, StepParameters{Name: name})
+		}
+	}
+	return append(params, ReportingParameters.Parameters...)
+}
+
+func addParameterSchema(properties map[string]interface{}, param StepParameters) {
+	if existing, ok := properties[param.Name].(map[string]interface{}); ok {
+		// the same parameter may be defined by multiple steps with differing types
+		schema := ParameterSchema(param)
+		if existing["type"] != schema["type"] {
+			delete(existing, "type")
+			delete(existing, "items")
+			delete(existing, "enum")
+		} else {
+			mergeEnums(existing, schema)
+		}
+		return
+	}
+	properties[param.Name] = ParameterSchema(param)
+	for _, alias := range param.Aliases {
+		// aliases pointing into nested maps cannot be described as property
+		if _, ok := properties[alias.Name]; ok || strings.Contains(alias.Name, "/") {
+			continue
+		}
+		aliasSchema := ParameterSchema(param)
+		aliasSchema["description"] = aliasDescription(param.Name, alias)
+		if alias.Deprecated {
+			aliasSchema["deprecated"] = true
+		}
+		properties[alias.Name] = aliasSchema
+	}
+}
+
+// mergeEnums allows the possible values of both schemas, a schema without possible values allows any value
+func mergeEnums(existing, schema map[string]interface{}) {
+	existingHolder, holder := enumHolder(existing), enumHolder(schema)
+	existingEnum, existingOk := existingHolder["enum"].([]interface{})
+	enum, ok := holder["enum"].([]interface{})
+	if !existingOk || !ok {
+		delete(existingHolder, "enum")
+		return
+	}
+	merged := append([]interface{}{}, existingEnum...)
+	for _, value := range enum {
+		if !containsValue(merged, value) {
+			merged = append(merged, value)
+		}
+	}
+	existingHolder["enum"] = merged
+}
+
+// enumHolder returns the part of the schema containing the possible values, for lists these are defined for the items
+func enumHolder(schema map[string]interface{}) map[string]interface{} {
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		return items
+	}
+	return schema
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func addSecretSchema(properties map[string]interface{}, secret StepSecrets) {
+	if _, ok := properties[secret.Name]; ok {
+		return
+	}
+	properties[secret.Name] = ParameterSchema(StepParameters{Name: secret.Name, Type: "string", Description: secret.Description})
+	for _, alias := range secret.Aliases {
+		if _, ok := properties[alias.Name]; !ok {
+			properties[alias.Name] = ParameterSchema(StepParameters{Name: alias.Name, Type: "string", Description: aliasDescription(secret.Name, alias)})
+		}
+	}
+}
+
+func aliasDescription(name string, alias Alias) string {
+	if alias.Deprecated {
+		return fmt.Sprintf("DEPRECATED alias of '%v', use '%v' instead", name, name)
+	}
+	return fmt.Sprintf("Alias of '%v'", name)
+}
+
+func hasScope(param StepParameters, scope string) bool {
+	for _, s := range param.Scope {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedStepNames(steps map[string]StepData) []string {
+	names := make([]string, 0, len(steps))
+	for name := range steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
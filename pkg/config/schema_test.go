@@ -0,0 +1,77 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateConfigSchema(t *testing.T) {
+	schema := GenerateConfigSchema(validationTestMetadata())
+	properties := schema["properties"].(map[string]interface{})
+
+	general := properties["general"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"maven", "npm"}}, general["buildTool"])
+	assert.Equal(t, map[string]interface{}{"type": "integer", "description": "DEPRECATED alias of 'retries', use 'retries' instead", "deprecated": true}, general["maxRetries"])
+	assert.Contains(t, general, "vaultBasePath")
+	assert.NotContains(t, general, "goals")
+
+	stages := properties["stages"].(map[string]interface{})["additionalProperties"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Contains(t, stages, "buildTool")
+	assert.NotContains(t, stages, "retries")
+
+	steps := properties["steps"].(map[string]interface{})["properties"].(map[string]interface{})
+	mavenBuild := steps["mavenBuild"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "boolean"}, mavenBuild["flatten"])
+	assert.Equal(t, map[string]interface{}{"type": "string"}, mavenBuild["mavenCredentialsId"])
+	assert.Contains(t, steps, "mavenBuildOld")
+}
+
+func TestParameterSchema(t *testing.T) {
+	t.Run("list with possible values", func(t *testing.T) {
+		schema := ParameterSchema(StepParameters{Name: "p", Type: "[]string", PossibleValues: []interface{}{"a", "b"}, Default: []string{"a"}})
+		assert.Equal(t, map[string]interface{}{
+			"type":    "array",
+			"items":   map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}},
+			"default": []string{"a"},
+		}, schema)
+	})
+
+	t.Run("deprecated", func(t *testing.T) {
+		schema := ParameterSchema(StepParameters{Name: "p", Type: "map[string]interface{}", Description: "desc", DeprecationMessage: "use q"})
+		assert.Equal(t, map[string]interface{}{"type": "object", "description": "desc (DEPRECATED: use q)", "deprecated": true}, schema)
+	})
+}
+
+func TestAddParameterSchema(t *testing.T) {
+	t.Run("possible values of all definitions", func(t *testing.T) {
+		properties := map[string]interface{}{}
+		addParameterSchema(properties, StepParameters{Name: "buildTool", Type: "string", PossibleValues: []interface{}{"maven", "npm"}})
+		addParameterSchema(properties, StepParameters{Name: "buildTool", Type: "string", PossibleValues: []interface{}{"npm", "golang"}})
+		assert.Equal(t, map[string]interface{}{"type": "string", "enum": []interface{}{"maven", "npm", "golang"}}, properties["buildTool"])
+	})
+
+	t.Run("possible values of lists", func(t *testing.T) {
+		properties := map[string]interface{}{}
+		addParameterSchema(properties, StepParameters{Name: "scanners", Type: "[]string", PossibleValues: []interface{}{"a"}})
+		addParameterSchema(properties, StepParameters{Name: "scanners", Type: "[]string", PossibleValues: []interface{}{"b"}})
+		assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "enum": []interface{}{"a", "b"}}}, properties["scanners"])
+	})
+
+	t.Run("definition without possible values", func(t *testing.T) {
+		properties := map[string]interface{}{}
+		addParameterSchema(properties, StepParameters{Name: "buildTool", Type: "string", PossibleValues: []interface{}{"maven"}})
+		addParameterSchema(properties, StepParameters{Name: "buildTool", Type: "string"})
+		assert.Equal(t, map[string]interface{}{"type": "string"}, properties["buildTool"])
+	})
+
+	t.Run("differing types", func(t *testing.T) {
+		properties := map[string]interface{}{}
+		addParameterSchema(properties, StepParameters{Name: "goals", Type: "string", Description: "desc", PossibleValues: []interface{}{"install"}})
+		addParameterSchema(properties, StepParameters{Name: "goals", Type: "[]string"})
+		assert.Equal(t, map[string]interface{}{"description": "desc"}, properties["goals"])
+	})
+}
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+const (
+	secretProviders = "secretProviders"
+	secretStoreFile = "secretStoreFile"
+	secretEnvPrefix = "secretEnvPrefix"
+
+	secretProviderVault = "vault"
+	secretProviderEnv   = "env"
+	secretProviderFile  = "file"
+
+	// SecretEnvPrefixDefault is the prefix of environment variables which provide secrets, e.g. PIPER_SECRET_SONAR_TOKEN
+	SecretEnvPrefixDefault = "PIPER_SECRET_"
+	// SecretStoreKeyEnv is the environment variable holding the base64 encoded key of the encrypted secret store
+	SecretStoreKeyEnv      = "PIPER_SECRET_STORE_KEY"
+	secretStoreFileDefault = ".pipeline/secrets.yml"
+)
+
+// SecretProvider provides the secrets referenced by step parameters via resource references of type vaultSecret and vaultSecretFile
+type SecretProvider interface {
+	// Name returns the name of the provider which is also used as origin of resolved values
+	Name() string
+	// SecretPaths returns the paths at which the secret with the given name is looked up
+	SecretPaths(secretName string, config map[string]interface{}) []string
+	// GetSecret returns the fields of the secret stored at the given path, nil in case the secret does not exist
+	GetSecret(path string) (map[string]string, error)
+}
+
+// getSecretProviders creates the secret providers configured via secretProviders in the given order, by default only Vault is used
+// The returned Vault client is nil in case Vault is not used.
+func getSecretProviders(config StepConfig, creds VaultCredentials) ([]SecretProvider, VaultClient, error) {
+	providerNames := []string{secretProviderVault}
+	switch configured := config.Config[secretProviders].(type) {
+	case string:
+		providerNames = []string{configured}
+	case []interface{}:
+		providerNames = toStringSlice(configured)
+	}
+
+	var providers []SecretProvider
+	var client VaultClient
+	for _, name := range providerNames {
+		switch name {
+		case secretProviderVault:
+			var err error
+			client, err = GetVaultClientFromConfig(config, creds)
+			if err != nil {
+				return nil, nil, err
+			}
+			if client != nil {
+				providers = append(providers, &vaultSecretProvider{client: client})
+			}
+		case secretProviderEnv:
+			prefix, ok := config.Config[secretEnvPrefix].(string)
+			if !ok || len(prefix) == 0 {
+				prefix = SecretEnvPrefixDefault
+			}
+			providers = append(providers, &envSecretProvider{prefix: prefix})
+		case secretProviderFile:
+			provider, err := newFileSecretProvider(config)
+			if err != nil {
+				return nil, nil, err
+			}
+			if provider != nil {
+				providers = append(providers, provider)
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown secret provider '%v', supported are '%v', '%v' and '%v'", name, secretProviderVault, secretProviderEnv, secretProviderFile)
+		}
+	}
+	return providers, client, nil
+}
+
+// vaultSecretProvider looks up secrets in HashiCorp Vault below the VaultRootPaths
+type vaultSecretProvider struct {
+	client VaultClient
+}
+
+func (v *vaultSecretProvider) Name() string {
+	return OriginVault
+}
+
+func (v *vaultSecretProvider) SecretPaths(secretName string, config map[string]interface{}) []string {
+	return getSecretReferencePaths(secretName, config)
+}
+
+func (v *vaultSecretProvider) GetSecret(path string) (map[string]string, error) {
+	return v.client.GetKvSecret(path)
+}
+
+// envSecretProvider looks up secrets in environment variables
+// The field token of the secret sonar is for example provided via PIPER_SECRET_SONAR_TOKEN.
+type envSecretProvider struct {
+	prefix string
+}
+
+func (e *envSecretProvider) Name() string {
+	return OriginSecretEnvVar
+}
+
+func (e *envSecretProvider) SecretPaths(secretName string, _ map[string]interface{}) []string {
+	return []string{secretName}
+}
+
+func (e *envSecretProvider) GetSecret(path string) (map[string]string, error) {
+	secretPrefix := e.prefix + ConvertEnvVar(path) + "_"
+	var secret map[string]string
+	for _, env := range os.Environ() {
+		parts := strings.SplitN(env, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], secretPrefix) || len(parts[0]) == len(secretPrefix) {
+			continue
+		}
+		if secret == nil {
+			secret = map[string]string{}
+		}
+		secret[strings.TrimPrefix(parts[0], secretPrefix)] = parts[1]
+	}
+	return secret, nil
+}
+
+// encryptedValue matches values of the secret store in the format ENC[AES256_GCM,data:<base64>,iv:<base64>]
+var encryptedValue = regexp.MustCompile(`^ENC\[AES256_GCM,data:([A-Za-z0-9+/=]+),iv:([A-Za-z0-9+/=]+)\]$`)
+
+// fileSecretProvider looks up secrets in a local YAML file whose values are encrypted with AES-256-GCM.
+// Like with SOPS only the values are encrypted so that the file can be reviewed and versioned, the format is however specific to piper
+// and the values are created via 'piper encryptSecret':
+//
+//	sonar:
+//	  token: ENC[AES256_GCM,data:...,iv:...]
+type fileSecretProvider struct {
+	file    string
+	key     []byte
+	secrets map[string]map[string]string
+}
+
+func newFileSecretProvider(config StepConfig) (*fileSecretProvider, error) {
+	file, ok := config.Config[secretStoreFile].(string)
+	if !ok || len(file) == 0 {
+		file = secretStoreFileDefault
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Entry().Debugf("Skipping fetching secrets from secret store since '%v' does not exist", file)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read secret store '%v'", file)
+	}
+	key, err := SecretStoreKeyFromEnv()
+	if err != nil {
+		return nil, errors.Wrapf(err, "secret store '%v' cannot be decrypted", file)
+	}
+
+	provider := &fileSecretProvider{file: file, key: key}
+	if err := yaml.Unmarshal(content, &provider.secrets); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse secret store '%v'", file)
+	}
+	log.Entry().Infof("Fetching secrets from secret store '%v'", file)
+	return provider, nil
+}
+
+func (f *fileSecretProvider) Name() string {
+	return OriginSecretStore
+}
+
+func (f *fileSecretProvider) SecretPaths(secretName string, _ map[string]interface{}) []string {
+	return []string{secretName}
+}
+
+func (f *fileSecretProvider) GetSecret(path string) (map[string]string, error) {
+	encrypted, ok := f.secrets[path]
+	if !ok {
+		return nil, nil
+	}
+	secret := make(map[string]string, len(encrypted))
+	for field, value := range encrypted {
+		decrypted, err := DecryptSecretValue(f.key, value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decrypt field '%v' of secret '%v' in '%v'", field, path, f.file)
+		}
+		secret[field] = decrypted
+	}
+	return secret, nil
+}
+
+// SecretStoreKeyFromEnv returns the key of the secret store provided via the environment variable PIPER_SECRET_STORE_KEY
+func SecretStoreKeyFromEnv() ([]byte, error) {
+	encodedKey := os.Getenv(SecretStoreKeyEnv)
+	if len(encodedKey) == 0 {
+		return nil, fmt.Errorf("environment variable %v is not set", SecretStoreKeyEnv)
+	}
+	log.RegisterSecret(encodedKey)
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode key of secret store from %v", SecretStoreKeyEnv)
+	}
+	return key, nil
+}
+
+// GenerateSecretStoreKey returns a new base64 encoded 256 bit key for the secret store
+func GenerateSecretStoreKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", errors.Wrap(err, "failed to generate key")
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// EncryptSecretValue encrypts a value for the secret store with the given 256 bit key
+func EncryptSecretValue(key []byte, value string) (string, error) {
+	gcm, err := newSecretStoreCipher(key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", errors.Wrap(err, "failed to create initialization vector")
+	}
+	data := gcm.Seal(nil, iv, []byte(value), nil)
+	return fmt.Sprintf("ENC[AES256_GCM,data:%v,iv:%v]", base64.StdEncoding.EncodeToString(data), base64.StdEncoding.EncodeToString(iv)), nil
+}
+
+// DecryptSecretValue decrypts a value of the secret store with the given 256 bit key
+func DecryptSecretValue(key []byte, value string) (string, error) {
+	matches := encryptedValue.FindStringSubmatch(value)
+	if matches == nil {
+		return "", fmt.Errorf("value is not encrypted, expected format 'ENC[AES256_GCM,data:...,iv:...]'")
+	}
+	data, err := base64.StdEncoding.DecodeString(matches[1])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode data")
+	}
+	iv, err := base64.StdEncoding.DecodeString(matches[2])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode initialization vector")
+	}
+	gcm, err := newSecretStoreCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(iv) != gcm.NonceSize() {
+		return "", fmt.Errorf("invalid initialization vector length %v", len(iv))
+	}
+	plain, err := gcm.Open(nil, iv, data, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decrypt value")
+	}
+	return string(plain), nil
+}
+
+func newSecretStoreCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key length %v, a 256 bit key is required", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return cipher.NewGCM(block)
+}
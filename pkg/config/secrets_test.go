@@ -0,0 +1,178 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/config/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSecretProviders(t *testing.T) {
+	t.Run("Vault is used by default", func(t *testing.T) {
+		providers, client, err := getSecretProviders(StepConfig{Config: map[string]interface{}{}}, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Empty(t, providers)
+		assert.Nil(t, client)
+	})
+
+	t.Run("Providers are created in configured order", func(t *testing.T) {
+		dir := t.TempDir()
+		storeFile := filepath.Join(dir, "secrets.yml")
+		assert.NoError(t, os.WriteFile(storeFile, []byte("{}"), 0600))
+		t.Setenv(SecretStoreKeyEnv, base64.StdEncoding.EncodeToString(make([]byte, 32)))
+
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"secretProviders": []interface{}{"file", "vault", "env"},
+			"secretStoreFile": storeFile,
+			"secretEnvPrefix": "MY_SECRET_",
+		}}
+		providers, client, err := getSecretProviders(stepConfig, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Nil(t, client)
+		if assert.Len(t, providers, 2) {
+			assert.Equal(t, OriginSecretStore, providers[0].Name())
+			assert.Equal(t, OriginSecretEnvVar, providers[1].Name())
+			assert.Equal(t, "MY_SECRET_", providers[1].(*envSecretProvider).prefix)
+		}
+	})
+
+	t.Run("Missing secret store is skipped", func(t *testing.T) {
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"secretProviders": "file",
+			"secretStoreFile": filepath.Join(t.TempDir(), "secrets.yml"),
+		}}
+		providers, _, err := getSecretProviders(stepConfig, VaultCredentials{})
+		assert.NoError(t, err)
+		assert.Empty(t, providers)
+	})
+
+	t.Run("Missing key of secret store", func(t *testing.T) {
+		storeFile := filepath.Join(t.TempDir(), "secrets.yml")
+		assert.NoError(t, os.WriteFile(storeFile, []byte("{}"), 0600))
+		t.Setenv(SecretStoreKeyEnv, "")
+
+		stepConfig := StepConfig{Config: map[string]interface{}{
+			"secretProviders": "file",
+			"secretStoreFile": storeFile,
+		}}
+		_, _, err := getSecretProviders(stepConfig, VaultCredentials{})
+		assert.EqualError(t, err, fmt.Sprintf("secret store '%v' cannot be decrypted: environment variable PIPER_SECRET_STORE_KEY is not set", storeFile))
+	})
+
+	t.Run("Unknown provider", func(t *testing.T) {
+		stepConfig := StepConfig{Config: map[string]interface{}{"secretProviders": []interface{}{"keychain"}}}
+		_, _, err := getSecretProviders(stepConfig, VaultCredentials{})
+		assert.EqualError(t, err, "unknown secret provider 'keychain', supported are 'vault', 'env' and 'file'")
+	})
+}
+
+func TestEnvSecretProvider(t *testing.T) {
+	t.Setenv("PIPER_SECRET_SONAR_TOKEN", "secretToken")
+	t.Setenv("PIPER_SECRET_SONAR_USER_NAME", "user")
+	t.Setenv("PIPER_SECRET_SONARQUBE_TOKEN", "other")
+
+	provider := &envSecretProvider{prefix: SecretEnvPrefixDefault}
+	secret, err := provider.GetSecret("sonar")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"TOKEN": "secretToken", "USER_NAME": "user"}, secret)
+
+	secret, err = provider.GetSecret("unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, secret)
+}
+
+func TestFileSecretProvider(t *testing.T) {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	encrypted, err := EncryptSecretValue(key, "secretToken")
+	assert.NoError(t, err)
+	assert.Regexp(t, `^ENC\[AES256_GCM,data:.+,iv:.+\]$`, encrypted)
+
+	storeFile := filepath.Join(t.TempDir(), "secrets.yml")
+	content := fmt.Sprintf("sonar:\n  token: %v\nbroken:\n  token: plain\n", encrypted)
+	assert.NoError(t, os.WriteFile(storeFile, []byte(content), 0600))
+	t.Setenv(SecretStoreKeyEnv, base64.StdEncoding.EncodeToString(key))
+
+	provider, err := newFileSecretProvider(StepConfig{Config: map[string]interface{}{"secretStoreFile": storeFile}})
+	assert.NoError(t, err)
+
+	t.Run("Decrypt secret", func(t *testing.T) {
+		secret, err := provider.GetSecret("sonar")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"token": "secretToken"}, secret)
+	})
+
+	t.Run("Secret does not exist", func(t *testing.T) {
+		secret, err := provider.GetSecret("unknown")
+		assert.NoError(t, err)
+		assert.Nil(t, secret)
+	})
+
+	t.Run("Value is not encrypted", func(t *testing.T) {
+		_, err := provider.GetSecret("broken")
+		assert.Contains(t, fmt.Sprint(err), "value is not encrypted")
+	})
+
+	t.Run("Wrong key", func(t *testing.T) {
+		_, err := DecryptSecretValue(make([]byte, 32), encrypted)
+		assert.EqualError(t, err, "failed to decrypt value: cipher: message authentication failed")
+	})
+
+	t.Run("Invalid key length", func(t *testing.T) {
+		_, err := EncryptSecretValue([]byte("short"), "value")
+		assert.EqualError(t, err, "invalid key length 5, a 256 bit key is required")
+	})
+}
+
+func TestResolveAllSecretReferences(t *testing.T) {
+	const secretName = "testSecret"
+	const secretNameOverrideKey = "mySecretVaultSecretName"
+
+	t.Run("Secret from environment", func(t *testing.T) {
+		t.Setenv("PIPER_SECRET_OVERRIDESECRETNAME_TESTSECRET", "value1")
+		stepConfig := StepConfig{Config: map[string]interface{}{secretNameOverrideKey: "overrideSecretName"}}
+		stepParams := []StepParameters{stepParam(secretName, "vaultSecret", secretNameOverrideKey, secretName)}
+
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&envSecretProvider{prefix: SecretEnvPrefixDefault}}, stepParams)
+		assert.Equal(t, "value1", stepConfig.Config[secretName])
+		assert.Equal(t, []ParameterSource{{Origin: OriginSecretEnvVar, SecretPath: "overrideSecretName"}}, stepConfig.Sources[secretName])
+	})
+
+	t.Run("Next provider is used in case secret is not found", func(t *testing.T) {
+		t.Setenv("PIPER_SECRET_TESTSECRET_TESTSECRET", "value2")
+		vaultMock := &mocks.VaultMock{}
+		vaultMock.On("GetKvSecret", "team1/testSecret").Return(nil, nil)
+		stepConfig := StepConfig{Config: map[string]interface{}{"vaultPath": "team1"}}
+		stepParams := []StepParameters{stepParam(secretName, "vaultSecretFile", secretNameOverrideKey, secretName)}
+		defer func() {
+			RemoveVaultSecretFiles()
+			VaultSecretFileDirectory = ""
+		}()
+
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}, &envSecretProvider{prefix: SecretEnvPrefixDefault}}, stepParams)
+		if assert.IsType(t, "", stepConfig.Config[secretName]) {
+			content, err := os.ReadFile(stepConfig.Config[secretName].(string))
+			assert.NoError(t, err)
+			assert.Equal(t, "value2", string(content))
+		}
+		vaultMock.AssertExpectations(t)
+	})
+
+	t.Run("Unknown reference types are ignored", func(t *testing.T) {
+		t.Setenv("PIPER_SECRET_TESTSECRET_TESTSECRET", "value3")
+		stepConfig := StepConfig{Config: map[string]interface{}{}}
+		stepParams := []StepParameters{stepParam(secretName, "commonPipelineEnvironment", secretNameOverrideKey, secretName)}
+
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&envSecretProvider{prefix: SecretEnvPrefixDefault}}, stepParams)
+		assert.Nil(t, stepConfig.Config[secretName])
+	})
+}
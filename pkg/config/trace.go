@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const sectionGeneral = "general"
+
+// Origins of step configuration values as recorded in StepConfig.Sources
+const (
+	OriginStepMetadata      = "stepMetadata"
+	OriginPipelineEnv       = "commonPipelineEnvironment"
+	OriginDefaults          = "defaults"
+	OriginProjectConfig     = "projectConfig"
+	OriginEnvVar            = "environmentVariable"
+	OriginParametersJSON    = "parametersJSON"
+	OriginFlag              = "flag"
+	OriginVault             = "vault"
+	OriginSecretEnvVar      = "secretEnvironmentVariable"
+	OriginSecretStore       = "secretStore"
+	OriginConditionalConfig = "condition"
+)
+
+// ParameterSource describes where the value of a step configuration parameter originates from
+type ParameterSource struct {
+	Origin     string `json:"origin"`
+	File       string `json:"file,omitempty"`
+	Section    string `json:"section,omitempty"`
+	Alias      string `json:"alias,omitempty"`
+	SecretPath string `json:"secretPath,omitempty"`
+}
+
+// String returns a human readable representation of the source
+func (p ParameterSource) String() string {
+	parts := []string{p.Origin}
+	if len(p.File) > 0 {
+		parts = append(parts, fmt.Sprintf("file '%v'", p.File))
+	}
+	if len(p.Section) > 0 {
+		parts = append(parts, fmt.Sprintf("section '%v'", p.Section))
+	}
+	if len(p.Alias) > 0 {
+		parts = append(parts, fmt.Sprintf("via alias '%v'", p.Alias))
+	}
+	if len(p.SecretPath) > 0 {
+		parts = append(parts, fmt.Sprintf("path '%v'", p.SecretPath))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Explain returns a human readable trace of all sources which provided a value for the resolved step configuration.
+// The last source listed for a parameter is the one which is effective.
+// Values resolved from Vault or another secret provider are not printed.
+func (s *StepConfig) Explain() string {
+	keys := make([]string, 0, len(s.Config))
+	for key := range s.Config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		sources := s.Sources[key]
+		value := fmt.Sprintf("%v", s.Config[key])
+		if len(sources) > 0 && isSecretOrigin(sources[len(sources)-1].Origin) {
+			value = "****"
+		}
+		fmt.Fprintf(&b, "%v: %v\n", key, value)
+		if len(sources) == 0 {
+			fmt.Fprintln(&b, "  - unknown source")
+		}
+		for i, source := range sources {
+			marker := "overridden"
+			if i == len(sources)-1 {
+				marker = "effective"
+			}
+			fmt.Fprintf(&b, "  - %v (%v)\n", source, marker)
+		}
+	}
+	return b.String()
+}
+
+func isSecretOrigin(origin string) bool {
+	return origin == OriginVault || origin == OriginSecretEnvVar || origin == OriginSecretStore
+}
+
+func (s *StepConfig) addSource(key string, source ParameterSource) {
+	if len(source.Origin) == 0 {
+		return
+	}
+	if s.Sources == nil {
+		s.Sources = map[string][]ParameterSource{}
+	}
+	// the same section may be merged more than once, e.g. for vault and reporting parameters
+	if sources := s.Sources[key]; len(sources) > 0 && sources[len(sources)-1] == source {
+		return
+	}
+	s.Sources[key] = append(s.Sources[key], source)
+}
+
+// recordAlias remembers that the value of a parameter in a config section was taken from an alias
+func (c *Config) recordAlias(section, name, alias string) {
+	if len(alias) == 0 {
+		return
+	}
+	if c.aliases == nil {
+		c.aliases = map[string]map[string]string{}
+	}
+	if c.aliases[section] == nil {
+		c.aliases[section] = map[string]string{}
+	}
+	c.aliases[section][name] = alias
+}
+
+// configSection holds the content of one section of a configuration together with its source information
+type configSection struct {
+	data    map[string]interface{}
+	source  ParameterSource
+	aliases map[string]string
+}
+
+// sections returns the general, step and stage section of the configuration relevant for a step
+func (c *Config) sections(origin, stageName, stepName string) (general, step, stage configSection) {
+	section := func(data map[string]interface{}, name string) configSection {
+		return configSection{
+			data:    data,
+			source:  ParameterSource{Origin: origin, File: c.source, Section: name},
+			aliases: c.aliases[name],
+		}
+	}
+	return section(c.General, sectionGeneral), section(c.Steps[stepName], sectionStep(stepName)), section(c.Stages[stageName], sectionStage(stageName))
+}
+
+// sourceName returns the name of a configuration source, e.g. the file name, if available
+func sourceName(r io.Reader, fallback string) string {
+	if named, ok := r.(interface{ Name() string }); ok && len(named.Name()) > 0 {
+		return named.Name()
+	}
+	return fallback
+}
+
+// namedReadCloser attaches a name to configuration content which is not read from a local file
+type namedReadCloser struct {
+	io.ReadCloser
+	name string
+}
+
+func (n *namedReadCloser) Name() string {
+	return n.name
+}
+
+func sectionStep(stepName string) string {
+	return "steps/" + stepName
+}
+
+func sectionStage(stageName string) string {
+	return "stages/" + stageName
+}
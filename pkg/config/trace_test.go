@@ -0,0 +1,113 @@
+//go:build unit
+// +build unit
+
+package config
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStepConfigSources(t *testing.T) {
+	testConfig := `general:
+  p1: p1_general
+steps:
+  step1:
+    oldP2: p2_step_alias
+stages:
+  stage1:
+    p3: p3_stage
+`
+	defaults := `general:
+  p1: p1_general_default
+  p4: p4_general_default
+`
+	filters := StepFilters{
+		General:    []string{"p1", "p2", "p3", "p4", "p5"},
+		Steps:      []string{"p1", "p2", "p3", "p4", "p5"},
+		Stages:     []string{"p1", "p2", "p3", "p4", "p5"},
+		Parameters: []string{"p1", "p2", "p3", "p4", "p5"},
+	}
+	metadata := StepData{
+		Spec: StepSpec{
+			Inputs: StepInputs{
+				Parameters: []StepParameters{
+					{Name: "p1", Default: "p1_metadata"},
+					{Name: "p2", Aliases: []Alias{{Name: "oldP2"}}},
+					{Name: "p3"},
+					{Name: "p4"},
+					{Name: "p5"},
+				},
+			},
+		},
+	}
+
+	var c Config
+	stepConfig, err := c.GetStepConfig(
+		map[string]interface{}{"p5": "p5_flag"},
+		`{"p4":"p4_param"}`,
+		ioutil.NopCloser(strings.NewReader(testConfig)),
+		[]io.ReadCloser{&namedReadCloser{ReadCloser: ioutil.NopCloser(strings.NewReader(defaults)), name: "my-defaults.yml"}},
+		false,
+		filters,
+		metadata,
+		map[string]interface{}{},
+		"stage1",
+		"step1",
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []ParameterSource{
+		{Origin: OriginStepMetadata},
+		{Origin: OriginDefaults, File: "my-defaults.yml", Section: "general"},
+		{Origin: OriginProjectConfig, File: "project configuration", Section: "general"},
+	}, stepConfig.Sources["p1"])
+	assert.Equal(t, []ParameterSource{
+		{Origin: OriginProjectConfig, File: "project configuration", Section: "steps/step1", Alias: "oldP2"},
+	}, stepConfig.Sources["p2"])
+	assert.Equal(t, []ParameterSource{
+		{Origin: OriginProjectConfig, File: "project configuration", Section: "stages/stage1"},
+	}, stepConfig.Sources["p3"])
+	assert.Equal(t, []ParameterSource{
+		{Origin: OriginDefaults, File: "my-defaults.yml", Section: "general"},
+		{Origin: OriginParametersJSON},
+	}, stepConfig.Sources["p4"])
+	assert.Equal(t, []ParameterSource{{Origin: OriginFlag}}, stepConfig.Sources["p5"])
+}
+
+func TestStepConfigExplain(t *testing.T) {
+	t.Run("effective source last", func(t *testing.T) {
+		stepConfig := StepConfig{
+			Config: map[string]interface{}{"p1": "p1_flag", "p2": "p2_value"},
+			Sources: map[string][]ParameterSource{
+				"p1": {{Origin: OriginStepMetadata}, {Origin: OriginFlag}},
+			},
+		}
+		expected := `p1: p1_flag
+  - stepMetadata (overridden)
+  - flag (effective)
+p2: p2_value
+  - unknown source
+`
+		assert.Equal(t, expected, stepConfig.Explain())
+	})
+
+	t.Run("vault values are not printed", func(t *testing.T) {
+		stepConfig := StepConfig{
+			Config: map[string]interface{}{"token": "secret"},
+			Sources: map[string][]ParameterSource{
+				"token": {{Origin: OriginVault, SecretPath: "team1/token"}},
+			},
+		}
+		assert.Equal(t, "token: ****\n  - vault path 'team1/token' (effective)\n", stepConfig.Explain())
+	})
+}
+
+func TestParameterSourceString(t *testing.T) {
+	source := ParameterSource{Origin: OriginProjectConfig, File: ".pipeline/config.yml", Section: "steps/step1", Alias: "oldName"}
+	assert.Equal(t, "projectConfig file '.pipeline/config.yml' section 'steps/step1' via alias 'oldName'", source.String())
+}
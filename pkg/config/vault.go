@@ -55,6 +55,9 @@ var (
 		vaultCredentialKeys,
 		vaultCredentialEnvPrefix,
 		vaultSecretName,
+		secretProviders,
+		secretStoreFile,
+		secretEnvPrefix,
 	}
 
 	// VaultRootPaths are the lookup paths piper tries to use during the vault lookup.
@@ -76,23 +79,33 @@ type VaultCredentials struct {
 	VaultToken      string
 }
 
-// vaultClient interface for mocking
-type vaultClient interface {
+// VaultClient interface for mocking
+type VaultClient interface {
 	GetKvSecret(string) (map[string]string, error)
 	MustRevokeToken()
 }
 
 func (s *StepConfig) mixinVaultConfig(parameters []StepParameters, configs ...map[string]interface{}) {
+	sections := []configSection{}
 	for _, config := range configs {
-		s.mixIn(config, vaultFilter)
+		sections = append(sections, configSection{data: config})
+	}
+	s.mixinVaultSections(parameters, sections...)
+}
+
+func (s *StepConfig) mixinVaultSections(parameters []StepParameters, sections ...configSection) {
+	for _, section := range sections {
+		s.mixInSection(section, vaultFilter)
 		// when an empty filter is returned we skip the mixin call since an empty filter will allow everything
 		if referencesFilter := getFilterForResourceReferences(parameters); len(referencesFilter) > 0 {
-			s.mixIn(config, referencesFilter)
+			s.mixInSection(section, referencesFilter)
 		}
 	}
 }
 
-func getVaultClientFromConfig(config StepConfig, creds VaultCredentials) (vaultClient, error) {
+// GetVaultClientFromConfig creates a Vault client for the vaultServerUrl and vaultNamespace of the step configuration.
+// It returns no client in case Vault is not configured or no credentials are available.
+func GetVaultClientFromConfig(config StepConfig, creds VaultCredentials) (VaultClient, error) {
 	address, addressOk := config.Config["vaultServerUrl"].(string)
 	// if vault isn't used it's not an error
 
@@ -107,7 +120,7 @@ func getVaultClientFromConfig(config StepConfig, creds VaultCredentials) (vaultC
 		log.Entry().Debugf("Using Vault namespace %s", namespace)
 	}
 
-	var client vaultClient
+	var client VaultClient
 	var err error
 	clientConfig := &vault.Config{Config: &api.Config{Address: address}, Namespace: namespace}
 	if creds.VaultToken != "" {
@@ -125,67 +138,73 @@ func getVaultClientFromConfig(config StepConfig, creds VaultCredentials) (vaultC
 	return client, nil
 }
 
-func resolveAllVaultReferences(config *StepConfig, client vaultClient, params []StepParameters) {
+// secretReferenceTypes defines how a resolved secret is passed to the step, depending on the type of the resource reference
+var secretReferenceTypes = map[string]func(paramName, secretValue string) (interface{}, error){
+	"vaultSecret": func(_, secretValue string) (interface{}, error) {
+		return secretValue, nil
+	},
+	"vaultSecretFile": func(paramName, secretValue string) (interface{}, error) {
+		return createTemporarySecretFile(paramName, secretValue)
+	},
+}
+
+func resolveAllSecretReferences(config *StepConfig, providers []SecretProvider, params []StepParameters) {
 	for _, param := range params {
-		if ref := param.GetReference("vaultSecret"); ref != nil {
-			resolveVaultReference(ref, config, client, param)
-		}
-		if ref := param.GetReference("vaultSecretFile"); ref != nil {
-			resolveVaultReference(ref, config, client, param)
+		for i := range param.ResourceRef {
+			ref := &param.ResourceRef[i]
+			if secretValueFor, ok := secretReferenceTypes[ref.Type]; ok {
+				resolveSecretReference(ref, config, providers, param, secretValueFor)
+			}
 		}
 	}
 }
 
-func resolveVaultReference(ref *ResourceReference, config *StepConfig, client vaultClient, param StepParameters) {
+func resolveSecretReference(ref *ResourceReference, config *StepConfig, providers []SecretProvider, param StepParameters, secretValueFor func(paramName, secretValue string) (interface{}, error)) {
 	vaultDisableOverwrite, _ := config.Config["vaultDisableOverwrite"].(bool)
 	if _, ok := config.Config[param.Name].(string); vaultDisableOverwrite && ok {
-		log.Entry().Debugf("Not fetching '%s' from Vault since it has already been set", param.Name)
+		log.Entry().Debugf("Not fetching '%s' from secret providers since it has already been set", param.Name)
 		return
 	}
 
-	var secretValue *string
-	for _, vaultPath := range getSecretReferencePaths(ref, config.Config) {
-		// it should be possible to configure the root path were the secret is stored
-		vaultPath, ok := interpolation.ResolveString(vaultPath, config.Config)
-		if !ok {
-			continue
-		}
+	secretName := ref.Default
+	if providedName, ok := config.Config[ref.Name].(string); ok && providedName != "" {
+		secretName = providedName
+	}
 
-		secretValue = lookupPath(client, vaultPath, &param)
-		if secretValue != nil {
-			log.Entry().Infof("Resolved param '%s' with Vault path '%s'", param.Name, vaultPath)
-			if ref.Type == "vaultSecret" {
-				config.Config[param.Name] = *secretValue
-			} else if ref.Type == "vaultSecretFile" {
-				filePath, err := createTemporarySecretFile(param.Name, *secretValue)
-				if err != nil {
-					log.Entry().WithError(err).Warnf("Couldn't create temporary secret file for '%s'", param.Name)
-					return
-				}
-				config.Config[param.Name] = filePath
+	for _, provider := range providers {
+		for _, secretPath := range provider.SecretPaths(secretName, config.Config) {
+			secretValue := lookupPath(provider, secretPath, &param)
+			if secretValue == nil {
+				continue
 			}
-			break
+			log.Entry().Infof("Resolved param '%s' with %s path '%s'", param.Name, provider.Name(), secretPath)
+			value, err := secretValueFor(param.Name, *secretValue)
+			if err != nil {
+				log.Entry().WithError(err).Warnf("Couldn't create temporary secret file for '%s'", param.Name)
+				return
+			}
+			config.Config[param.Name] = value
+			config.addSource(param.Name, ParameterSource{Origin: provider.Name(), SecretPath: secretPath})
+			return
 		}
 	}
-	if secretValue == nil {
-		log.Entry().Warnf("Could not resolve param '%s' from Vault", param.Name)
-	}
+	log.Entry().Warnf("Could not resolve param '%s' from any secret provider", param.Name)
 }
 
-func resolveVaultTestCredentialsWrapper(config *StepConfig, client vaultClient) {
+func resolveVaultTestCredentialsWrapper(config *StepConfig, client VaultClient) {
 	log.Entry().Debugln("resolveVaultTestCredentialsWrapper")
 	resolveVaultTestCredentialsWrapperBase(config, client, vaultTestCredentialPath, vaultTestCredentialKeys, resolveVaultTestCredentials)
 }
 
-func resolveVaultCredentialsWrapper(config *StepConfig, client vaultClient) {
+func resolveVaultCredentialsWrapper(config *StepConfig, client VaultClient) {
 	log.Entry().Debugln("resolveVaultCredentialsWrapper")
 	resolveVaultTestCredentialsWrapperBase(config, client, vaultCredentialPath, vaultCredentialKeys, resolveVaultCredentials)
 }
 
 func resolveVaultTestCredentialsWrapperBase(
-	config *StepConfig, client vaultClient,
+	config *StepConfig, client VaultClient,
 	vaultCredPath, vaultCredKeys string,
-	resolveVaultCredentials func(config *StepConfig, client vaultClient),
+	resolveVaultCredentials func(config *StepConfig, client VaultClient),
 ) {
 	switch config.Config[vaultCredPath].(type) {
 	case string:
@@ -219,7 +238,7 @@ func resolveVaultTestCredentialsWrapperBase(
 }
 
 // resolve test credential keys and expose as environment variables
-func resolveVaultTestCredentials(config *StepConfig, client vaultClient) {
+func resolveVaultTestCredentials(config *StepConfig, client VaultClient) {
 	credPath, pathOk := config.Config[vaultTestCredentialPath].(string)
 	keys := getTestCredentialKeys(config)
 	if !(pathOk && keys != nil) || credPath == "" || len(keys) == 0 {
@@ -256,7 +275,7 @@ func resolveVaultTestCredentials(config *StepConfig, client vaultClient) {
 	}
 }
 
-func resolveVaultCredentials(config *StepConfig, client vaultClient) {
+func resolveVaultCredentials(config *StepConfig, client VaultClient) {
 	credPath, pathOk := config.Config[vaultCredentialPath].(string)
 	keys := getCredentialKeys(config)
 	if !(pathOk && keys != nil) || credPath == "" || len(keys) == 0 {
@@ -438,9 +457,9 @@ func createTemporarySecretFile(namePattern string, content string) (string, erro
 	return file.Name(), nil
 }
 
-func lookupPath(client vaultClient, path string, param *StepParameters) *string {
-	log.Entry().Debugf("Trying to resolve Vault parameter '%s' at '%s'", param.Name, path)
-	secret, err := client.GetKvSecret(path)
+func lookupPath(provider SecretProvider, path string, param *StepParameters) *string {
+	log.Entry().Debugf("Trying to resolve %s parameter '%s' at '%s'", provider.Name(), param.Name, path)
+	secret, err := provider.GetSecret(path)
 	if err != nil {
 		log.Entry().WithError(err).Warnf("Couldn't fetch secret at '%s'", path)
 		return nil
@@ -449,7 +468,7 @@ func lookupPath(client vaultClient, path string, param *StepParameters) *string
 		return nil
 	}
 
-	field := secret[param.Name]
+	field := secretField(secret, param.Name)
 	if field != "" {
 		log.RegisterSecret(field)
 		return &field
@@ -458,11 +477,11 @@ func lookupPath(client vaultClient, path string, param *StepParameters) *string
 	// try parameter aliases
 	for _, alias := range param.Aliases {
 		log.Entry().Debugf("Trying alias field name '%s'", alias.Name)
-		field := secret[alias.Name]
+		field := secretField(secret, alias.Name)
 		if field != "" {
 			log.RegisterSecret(field)
 			if alias.Deprecated {
-				log.Entry().WithField("package", "SAP/jenkins-library/pkg/config").Warningf("DEPRECATION NOTICE: old step config key '%s' used in Vault. Please switch to '%s'!", alias.Name, param.Name)
+				log.Entry().WithField("package", "SAP/jenkins-library/pkg/config").Warningf("DEPRECATION NOTICE: old step config key '%s' used in %s. Please switch to '%s'!", alias.Name, provider.Name(), param.Name)
 			}
 			return &field
 		}
@@ -470,14 +489,22 @@ func lookupPath(client vaultClient, path string, param *StepParameters) *string
 	return nil
 }
 
-func getSecretReferencePaths(reference *ResourceReference, config map[string]interface{}) []string {
-	retPaths := make([]string, 0, len(VaultRootPaths))
-	secretName := reference.Default
-	if providedName, ok := config[reference.Name].(string); ok && providedName != "" {
-		secretName = providedName
+// secretField returns the field of a secret, fields of secrets from environment variables are matched in their converted form
+func secretField(secret map[string]string, name string) string {
+	if field, ok := secret[name]; ok {
+		return field
 	}
+	return secret[ConvertEnvVar(name)]
+}
+
+func getSecretReferencePaths(secretName string, config map[string]interface{}) []string {
+	retPaths := make([]string, 0, len(VaultRootPaths))
 	for _, rootPath := range VaultRootPaths {
-		fullPath := path.Join(rootPath, secretName)
+		// it should be possible to configure the root path were the secret is stored
+		fullPath, ok := interpolation.ResolveString(path.Join(rootPath, secretName), config)
+		if !ok {
+			continue
+		}
 		retPaths = append(retPaths, fullPath)
 	}
 	return retPaths
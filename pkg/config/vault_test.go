@@ -31,7 +31,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		vaultData := map[string]string{secretName: "value1"}
 
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Equal(t, "value1", stepConfig.Config[secretName])
 	})
 
@@ -45,7 +45,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		vaultData := map[string]string{secretName: "value1"}
 
 		vaultMock.On("GetKvSecret", path.Join("team1", "overrideSecretName")).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Equal(t, "value1", stepConfig.Config[secretName])
 	})
 
@@ -59,7 +59,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		stepParams := []StepParameters{stepParam(secretName, "vaultSecret", secretNameOverrideKey, secretName)}
 		vaultData := map[string]string{secretName: "value1"}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 
 		assert.Equal(t, "preset value", stepConfig.Config[secretName])
 	})
@@ -73,7 +73,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		stepParams := []StepParameters{stepParam(secretName, "vaultSecret", secretNameOverrideKey, secretName)}
 		vaultData := map[string]string{secretName: "value1"}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 
 		assert.Equal(t, "value1", stepConfig.Config[secretName])
 	})
@@ -85,7 +85,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		}}
 		stepParams := []StepParameters{stepParam(secretName, "vaultSecret", secretNameOverrideKey, secretName)}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(nil, fmt.Errorf("test"))
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Len(t, stepConfig.Config, 1)
 	})
 
@@ -96,7 +96,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		}}
 		stepParams := []StepParameters{stepParam(secretName, "vaultSecret", secretNameOverrideKey, secretName)}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(nil, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Len(t, stepConfig.Config, 1)
 	})
 
@@ -111,7 +111,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		stepParams := []StepParameters{param}
 		vaultData := map[string]string{aliasName: "value1"}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Equal(t, "value1", stepConfig.Config[secretName])
 	})
 
@@ -127,7 +127,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		vaultData := map[string]string{secretName: "value1"}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(nil, nil)
 		vaultMock.On("GetKvSecret", path.Join("team2/GROUP-SECRETS", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Equal(t, "value1", stepConfig.Config[secretName])
 	})
 
@@ -135,7 +135,7 @@ func TestVaultConfigLoad(t *testing.T) {
 		vaultMock := &mocks.VaultMock{}
 		stepConfig := StepConfig{Config: map[string]interface{}{}}
 		stepParams := []StepParameters{stepParam(secretName, "vaultSecret", secretNameOverrideKey, secretName)}
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.Nil(t, stepConfig.Config[secretName])
 		vaultMock.AssertNotCalled(t, "GetKvSecret", mock.AnythingOfType("string"))
 	})
@@ -152,7 +152,7 @@ func TestVaultSecretFiles(t *testing.T) {
 		stepParams := []StepParameters{stepParam(secretName, "vaultSecretFile", secretNameOverrideKey, secretName)}
 		vaultData := map[string]string{secretName: "value1"}
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.NotNil(t, stepConfig.Config[secretName])
 		path := stepConfig.Config[secretName].(string)
 		contentByte, err := ioutil.ReadFile(path)
@@ -173,7 +173,7 @@ func TestVaultSecretFiles(t *testing.T) {
 		vaultData := map[string]string{secretName: "value1"}
 		assert.NoDirExists(t, VaultSecretFileDirectory)
 		vaultMock.On("GetKvSecret", path.Join("team1", secretName)).Return(vaultData, nil)
-		resolveAllVaultReferences(&stepConfig, vaultMock, stepParams)
+		resolveAllSecretReferences(&stepConfig, []SecretProvider{&vaultSecretProvider{client: vaultMock}}, stepParams)
 		assert.NotNil(t, stepConfig.Config[secretName])
 		path := stepConfig.Config[secretName].(string)
 		assert.DirExists(t, VaultSecretFileDirectory)
@@ -0,0 +1,45 @@
+//go:build unit
+// +build unit
+
+package format_test
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	checkmarxOne "github.com/SAP/jenkins-library/pkg/checkmarxone"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/fortify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditStateMapping(t *testing.T) {
+	tt := []struct {
+		name       string
+		mapping    format.AuditStateMapping
+		properties format.SarifProperties
+		expected   format.AuditState
+	}{
+		{name: "Checkmarx to verify", mapping: checkmarx.AuditStates, properties: format.SarifProperties{ToolState: "ToVerify"}, expected: format.AuditStateNew},
+		{name: "Checkmarx not exploitable", mapping: checkmarx.AuditStates, properties: format.SarifProperties{ToolState: "NotExploitable"}, expected: format.AuditStateNotRelevant},
+		{name: "Checkmarx confirmed", mapping: checkmarx.AuditStates, properties: format.SarifProperties{ToolState: "Confirmed"}, expected: format.AuditStateRelevant},
+		{name: "Checkmarx urgent", mapping: checkmarx.AuditStates, properties: format.SarifProperties{ToolState: "Urgent"}, expected: format.AuditStateRelevant},
+		{name: "Checkmarx proposed not exploitable", mapping: checkmarx.AuditStates, properties: format.SarifProperties{ToolState: "ProposedNotExploitable"}, expected: format.AuditStateInProcess},
+		{name: "Checkmarx One to verify", mapping: checkmarxOne.AuditStates, properties: format.SarifProperties{ToolState: "TO_VERIFY"}, expected: format.AuditStateNew},
+		{name: "Checkmarx One not exploitable", mapping: checkmarxOne.AuditStates, properties: format.SarifProperties{ToolState: "NOT_EXPLOITABLE"}, expected: format.AuditStateNotRelevant},
+		{name: "Checkmarx One urgent", mapping: checkmarxOne.AuditStates, properties: format.SarifProperties{ToolState: "URGENT"}, expected: format.AuditStateRelevant},
+		{name: "Checkmarx One proposed not exploitable", mapping: checkmarxOne.AuditStates, properties: format.SarifProperties{ToolState: "PROPOSED_NOT_EXPLOITABLE"}, expected: format.AuditStateInProcess},
+		{name: "Fortify unreviewed", mapping: fortify.AuditStates, properties: format.SarifProperties{ToolState: "Unreviewed"}, expected: format.AuditStateNew},
+		{name: "Fortify not audited", mapping: fortify.AuditStates, properties: format.SarifProperties{ToolState: "Exploitable"}, expected: format.AuditStateNew},
+		{name: "Fortify exploitable", mapping: fortify.AuditStates, properties: format.SarifProperties{ToolState: "Exploitable", Audited: true}, expected: format.AuditStateRelevant},
+		{name: "Fortify suspicious", mapping: fortify.AuditStates, properties: format.SarifProperties{ToolState: "Suspicious", Audited: true}, expected: format.AuditStateRelevant},
+		{name: "Fortify bad practice", mapping: fortify.AuditStates, properties: format.SarifProperties{ToolState: "Bad Practice", Audited: true}, expected: format.AuditStateNotRelevant},
+		{name: "Fortify not an issue", mapping: fortify.AuditStates, properties: format.SarifProperties{ToolState: "Not an Issue", Audited: true}, expected: format.AuditStateNotRelevant},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.mapping.AuditState(test.properties))
+		})
+	}
+}
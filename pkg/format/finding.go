@@ -0,0 +1,353 @@
+package format
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Finding is a security finding of a scanner independent of the scanner, i.e. a vulnerable component (SCA) or a weakness in the code (SAST)
+type Finding struct {
+	Scanner     string   `json:"scanner"`
+	ID          string   `json:"id"`
+	Title       string   `json:"title,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Severity    Severity `json:"severity"`
+	Score       float64  `json:"score,omitempty"`
+	CVE         string   `json:"cve,omitempty"`
+	CWE         []string `json:"cwe,omitempty"`
+	// PackageURL identifies the affected component of SCA findings
+	PackageURL string           `json:"purl,omitempty"`
+	Location   *FindingLocation `json:"location,omitempty"`
+	AuditState AuditState       `json:"auditState"`
+	// AuditComment is the latest comment of the audit in the scanner
+	AuditComment string `json:"auditComment,omitempty"`
+	// Fingerprint identifies the finding across scans
+	Fingerprint string `json:"fingerprint"`
+	Link        string `json:"link,omitempty"`
+}
+
+// FindingLocation is the location of a finding in the source code
+type FindingLocation struct {
+	Path   string `json:"path"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// AuditState is the unified audit state of a finding, the values correspond to SarifProperties.UnifiedAuditState
+type AuditState string
+
+const (
+	AuditStateNew         AuditState = "new"
+	AuditStateInProcess   AuditState = "inProcess"
+	AuditStateRelevant    AuditState = "relevant"
+	AuditStateNotRelevant AuditState = "notRelevant"
+)
+
+// AuditStateMapping maps the audit states of a scanner (SarifProperties.ToolState) to the unified audit state
+type AuditStateMapping struct {
+	States map[string]AuditState
+	// AuditedOnly considers the audit state only for results marked as audited
+	AuditedOnly bool
+}
+
+// AuditState returns the unified audit state of a result, unknown audit states are considered as new
+func (m AuditStateMapping) AuditState(properties SarifProperties) AuditState {
+	if m.AuditedOnly && !properties.Audited {
+		return AuditStateNew
+	}
+	if state, ok := m.States[properties.ToolState]; ok {
+		return state
+	}
+	return AuditStateNew
+}
+
+// Open checks whether the finding still needs to be addressed, i.e. it has not been assessed as not relevant
+func (f Finding) Open() bool {
+	return f.AuditState != AuditStateNotRelevant
+}
+
+// Audited checks whether the finding has been assessed as relevant or not relevant
+func (f Finding) Audited() bool {
+	return f.AuditState == AuditStateRelevant || f.AuditState == AuditStateNotRelevant
+}
+
+// Severity is the unified severity of a finding
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"none", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < SeverityNone || s > SeverityCritical {
+		return severityNames[SeverityNone]
+	}
+	return severityNames[s]
+}
+
+// MarshalText marshals the severity by its name
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText unmarshals the severity from its name or a scanner specific name
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// ParseSeverity returns the severity for the severity names of the scanners (e.g. "Information" or "BLOCKER") and the SARIF levels
+func ParseSeverity(severity string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical", "blocker":
+		return SeverityCritical, nil
+	case "high", "error", "major":
+		return SeverityHigh, nil
+	case "medium", "warning", "moderate":
+		return SeverityMedium, nil
+	case "low", "note", "minor":
+		return SeverityLow, nil
+	case "none", "", "info", "information", "informational", "trivial":
+		return SeverityNone, nil
+	}
+	return SeverityNone, fmt.Errorf("severity '%v' not supported", severity)
+}
+
+// SeverityFromScore returns the severity of a CVSS v3 score, see https://nvd.nist.gov/vuln-metrics/cvss
+func SeverityFromScore(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityNone
+}
+
+// Threshold limits the number of open findings with at least the given severity and score
+type Threshold struct {
+	Severity Severity `json:"severity"`
+	// Score is the lowest CVSS score considered, 0 considers all findings
+	Score float64 `json:"score,omitempty"`
+	// Limit is the number of findings which is tolerated
+	Limit int `json:"limit"`
+}
+
+// ThresholdViolation is a threshold exceeded by the findings
+type ThresholdViolation struct {
+	Threshold Threshold `json:"threshold"`
+	Count     int       `json:"count"`
+}
+
+func (v ThresholdViolation) String() string {
+	condition := fmt.Sprintf("severity %v or higher", v.Threshold.Severity)
+	if v.Threshold.Score > 0 {
+		condition += fmt.Sprintf(" and score %v or higher", v.Threshold.Score)
+	}
+	return fmt.Sprintf("%v open findings with %v exceed the limit of %v", v.Count, condition, v.Threshold.Limit)
+}
+
+// EvaluateThresholds returns the thresholds exceeded by the open findings
+func EvaluateThresholds(findings []Finding, thresholds []Threshold) []ThresholdViolation {
+	violations := []ThresholdViolation{}
+	for _, threshold := range thresholds {
+		count := 0
+		for _, finding := range findings {
+			if finding.Open() && finding.Severity >= threshold.Severity && finding.Score >= threshold.Score {
+				count++
+			}
+		}
+		if count > threshold.Limit {
+			violations = append(violations, ThresholdViolation{Threshold: threshold, Count: count})
+		}
+	}
+	return violations
+}
+
+// CheckThresholds returns an error listing the thresholds exceeded by the open findings
+func CheckThresholds(findings []Finding, thresholds []Threshold) error {
+	violations := EvaluateThresholds(findings, thresholds)
+	if len(violations) == 0 {
+		return nil
+	}
+	messages := []string{}
+	for _, violation := range violations {
+		messages = append(messages, violation.String())
+	}
+	return errors.New(strings.Join(messages, ", "))
+}
+
+// CountBySeverity returns the number of open findings per severity
+func CountBySeverity(findings []Finding) map[Severity]int {
+	counts := map[Severity]int{}
+	for _, finding := range findings {
+		if finding.Open() {
+			counts[finding.Severity]++
+		}
+	}
+	return counts
+}
+
+// PackageFingerprint returns the fingerprint of a vulnerability of a component, it corresponds to PartialFingerprints.PackageURLPlusCVEHash
+func PackageFingerprint(packageURL, vulnerability string) string {
+	return base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%v+%v", packageURL, vulnerability)))
+}
+
+// FindingsFromSARIF converts the results of a SARIF report, auditState maps the scanner specific audit state of a result.
+// Without audit state mapping the unified audit state of the result is used.
+func FindingsFromSARIF(sarif SARIF, auditState func(SarifProperties) AuditState) []Finding {
+	findings := []Finding{}
+	for _, run := range sarif.Runs {
+		rules := map[string]SarifRule{}
+		for _, rule := range run.Tool.Driver.Rules {
+			rules[rule.ID] = rule
+		}
+		for _, result := range run.Results {
+			rule := rules[result.RuleID]
+			finding := Finding{
+				Scanner:    run.Tool.Driver.Name,
+				ID:         result.RuleID,
+				Title:      rule.Name,
+				CWE:        ruleCWEs(rule),
+				Link:       rule.HelpURI,
+				AuditState: AuditStateNew,
+			}
+			if result.Message != nil {
+				finding.Description = result.Message.Text
+			}
+			if len(finding.Title) == 0 && rule.ShortDescription != nil {
+				finding.Title = rule.ShortDescription.Text
+			}
+			finding.CVE = ExtractCVE(result.RuleID)
+			if len(result.Locations) > 0 {
+				physicalLocation := result.Locations[0].PhysicalLocation
+				finding.Location = &FindingLocation{
+					Path:   physicalLocation.ArtifactLocation.URI,
+					Line:   physicalLocation.Region.StartLine,
+					Column: physicalLocation.Region.StartColumn,
+				}
+			}
+			if result.AnalysisTarget != nil && strings.HasPrefix(result.AnalysisTarget.URI, "pkg:") {
+				finding.PackageURL = result.AnalysisTarget.URI
+			}
+			finding.Severity, finding.Score = resultSeverity(result, rule)
+			if result.Properties != nil {
+				finding.AuditComment = result.Properties.ToolAuditMessage
+				if auditState != nil {
+					finding.AuditState = auditState(*result.Properties)
+				} else if len(result.Properties.UnifiedAuditState) > 0 {
+					finding.AuditState = AuditState(result.Properties.UnifiedAuditState)
+				}
+			}
+			finding.Fingerprint = resultFingerprint(result, finding)
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+var cvePattern = regexp.MustCompile(`CVE-\d{4}-\d+`)
+
+// ExtractCVE returns the first CVE identifier contained in the texts, e.g. in the name of a vulnerability or a link
+func ExtractCVE(texts ...string) string {
+	for _, text := range texts {
+		if cve := cvePattern.FindString(text); len(cve) > 0 {
+			return cve
+		}
+	}
+	return ""
+}
+
+var cweTagPattern = regexp.MustCompile(`(?i)^(?:external/cwe/)?cwe-(\d+)$`)
+
+func ruleCWEs(rule SarifRule) []string {
+	cwes := []string{}
+	add := func(id string) {
+		if number, err := strconv.Atoi(id); err == nil {
+			// e.g. CodeQL tags are zero padded like cwe-089
+			id = strconv.Itoa(number)
+		}
+		cwe := "CWE-" + id
+		for _, existing := range cwes {
+			if existing == cwe {
+				return
+			}
+		}
+		cwes = append(cwes, cwe)
+	}
+	for _, relationship := range rule.Relationships {
+		if strings.EqualFold(relationship.Target.ToolComponent.Name, "CWE") {
+			add(strings.TrimPrefix(strings.ToUpper(relationship.Target.Id), "CWE-"))
+		}
+	}
+	if rule.Properties != nil {
+		for _, tag := range rule.Properties.Tags {
+			if match := cweTagPattern.FindStringSubmatch(tag); match != nil {
+				add(match[1])
+			}
+		}
+	}
+	if len(cwes) == 0 {
+		return nil
+	}
+	return cwes
+}
+
+// resultSeverity prefers the severity of the scanner over the security severity of the rule and the level of the result
+func resultSeverity(result Results, rule SarifRule) (Severity, float64) {
+	var score float64
+	if rule.Properties != nil {
+		score, _ = strconv.ParseFloat(rule.Properties.SecuritySeverity, 64)
+	}
+	if result.Properties != nil && len(result.Properties.ToolSeverity) > 0 {
+		if severity, err := ParseSeverity(result.Properties.ToolSeverity); err == nil {
+			return severity, score
+		}
+	}
+	if score > 0 {
+		return SeverityFromScore(score), score
+	}
+	level := result.Level
+	if len(level) == 0 && rule.DefaultConfiguration != nil {
+		level = rule.DefaultConfiguration.Level
+	}
+	severity, _ := ParseSeverity(level)
+	return severity, score
+}
+
+func resultFingerprint(result Results, finding Finding) string {
+	fingerprints := result.PartialFingerprints
+	for _, fingerprint := range []string{fingerprints.FortifyInstanceID, fingerprints.CheckmarxSimilarityID, fingerprints.PackageURLPlusCVEHash, fingerprints.PrimaryLocationLineHash} {
+		if len(fingerprint) > 0 {
+			return fingerprint
+		}
+	}
+	if result.Properties != nil && len(result.Properties.InstanceID) > 0 {
+		return result.Properties.InstanceID
+	}
+	identity := finding.Scanner + "+" + finding.ID
+	if finding.Location != nil {
+		identity = fmt.Sprintf("%v+%v:%v", identity, finding.Location.Path, finding.Location.Line)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(identity)))
+}
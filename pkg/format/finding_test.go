@@ -0,0 +1,168 @@
+//go:build unit
+// +build unit
+
+package format
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSeverity(t *testing.T) {
+	tt := []struct {
+		severity string
+		expected Severity
+	}{
+		{severity: "CRITICAL", expected: SeverityCritical},
+		{severity: "High", expected: SeverityHigh},
+		{severity: "error", expected: SeverityHigh},
+		{severity: "Medium", expected: SeverityMedium},
+		{severity: "warning", expected: SeverityMedium},
+		{severity: "LOW", expected: SeverityLow},
+		{severity: "note", expected: SeverityLow},
+		{severity: "Information", expected: SeverityNone},
+		{severity: "", expected: SeverityNone},
+	}
+	for _, test := range tt {
+		severity, err := ParseSeverity(test.severity)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, severity, test.severity)
+	}
+
+	_, err := ParseSeverity("severe")
+	assert.EqualError(t, err, "severity 'severe' not supported")
+}
+
+func TestSeverityFromScore(t *testing.T) {
+	assert.Equal(t, SeverityNone, SeverityFromScore(0))
+	assert.Equal(t, SeverityLow, SeverityFromScore(3.9))
+	assert.Equal(t, SeverityMedium, SeverityFromScore(4))
+	assert.Equal(t, SeverityHigh, SeverityFromScore(8.9))
+	assert.Equal(t, SeverityCritical, SeverityFromScore(9.8))
+}
+
+func TestFindingJSON(t *testing.T) {
+	finding := Finding{Scanner: "Mend", ID: "CVE-2022-1234", Severity: SeverityHigh, AuditState: AuditStateNew, Fingerprint: "abc"}
+
+	content, err := json.Marshal(finding)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"scanner":"Mend","id":"CVE-2022-1234","severity":"high","auditState":"new","fingerprint":"abc"}`, string(content))
+
+	var unmarshalled Finding
+	assert.NoError(t, json.Unmarshal(content, &unmarshalled))
+	assert.Equal(t, finding, unmarshalled)
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	findings := []Finding{
+		{ID: "1", Severity: SeverityCritical, Score: 9.8, AuditState: AuditStateNew},
+		{ID: "2", Severity: SeverityHigh, Score: 7.5, AuditState: AuditStateRelevant},
+		{ID: "3", Severity: SeverityHigh, Score: 7.1, AuditState: AuditStateNotRelevant},
+		{ID: "4", Severity: SeverityMedium, Score: 5, AuditState: AuditStateInProcess},
+		{ID: "5", Severity: SeverityLow, AuditState: AuditStateNew},
+	}
+
+	t.Run("violations", func(t *testing.T) {
+		violations := EvaluateThresholds(findings, []Threshold{
+			{Severity: SeverityHigh, Limit: 0},
+			{Severity: SeverityMedium, Limit: 3},
+			{Score: 7, Limit: 1},
+		})
+		assert.Equal(t, []ThresholdViolation{
+			{Threshold: Threshold{Severity: SeverityHigh, Limit: 0}, Count: 2},
+			{Threshold: Threshold{Score: 7, Limit: 1}, Count: 2},
+		}, violations)
+	})
+
+	t.Run("check", func(t *testing.T) {
+		assert.NoError(t, CheckThresholds(findings, []Threshold{{Severity: SeverityCritical, Limit: 1}}))
+		err := CheckThresholds(findings, []Threshold{{Severity: SeverityCritical, Limit: 0}, {Severity: SeverityMedium, Score: 5, Limit: 2}})
+		assert.EqualError(t, err, "1 open findings with severity critical or higher exceed the limit of 0, 3 open findings with severity medium or higher and score 5 or higher exceed the limit of 2")
+	})
+
+	t.Run("count", func(t *testing.T) {
+		assert.Equal(t, map[Severity]int{SeverityCritical: 1, SeverityHigh: 1, SeverityMedium: 1, SeverityLow: 1}, CountBySeverity(findings))
+	})
+}
+
+func TestExtractCVE(t *testing.T) {
+	assert.Equal(t, "CVE-2021-44228", ExtractCVE("BDSA-2021-3779", "https://nvd.nist.gov/vuln/detail/CVE-2021-44228"))
+	assert.Equal(t, "", ExtractCVE("WS-2019-0001"))
+}
+
+func TestFindingsFromSARIF(t *testing.T) {
+	sarif := SARIF{Runs: []Runs{{
+		Tool: Tool{Driver: Driver{
+			Name: "CodeQL",
+			Rules: []SarifRule{
+				{
+					ID:         "js/sql-injection",
+					Name:       "SqlInjection",
+					HelpURI:    "https://codeql.github.com/js-sql-injection",
+					Properties: &SarifRuleProperties{Tags: []string{"security", "external/cwe/cwe-089"}, SecuritySeverity: "8.8"},
+				},
+				{
+					ID:                   "fortify/xss",
+					ShortDescription:     &Message{Text: "Cross-Site Scripting"},
+					DefaultConfiguration: &DefaultConfiguration{Level: "warning"},
+					Relationships:        []Relationships{{Target: Target{Id: "79", ToolComponent: ToolComponent{Name: "CWE"}}}},
+				},
+			},
+		}},
+		Results: []Results{
+			{
+				RuleID:              "js/sql-injection",
+				Message:             &Message{Text: "query built from user input"},
+				Locations:           []Location{{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: "src/db.js"}, Region: Region{StartLine: 12, StartColumn: 3}}}},
+				PartialFingerprints: PartialFingerprints{PrimaryLocationLineHash: "hash:1"},
+			},
+			{
+				RuleID:     "fortify/xss",
+				Properties: &SarifProperties{ToolSeverity: "Critical", ToolState: "Exploitable", ToolAuditMessage: "fix it", UnifiedAuditState: "relevant"},
+			},
+			{
+				RuleID:         "CVE-2022-1234",
+				Level:          "note",
+				AnalysisTarget: &ArtifactLocation{URI: "pkg:npm/lodash@4.17.20"},
+			},
+		},
+	}}}
+
+	findings := FindingsFromSARIF(sarif, nil)
+
+	if assert.Len(t, findings, 3) {
+		assert.Equal(t, Finding{
+			Scanner:     "CodeQL",
+			ID:          "js/sql-injection",
+			Title:       "SqlInjection",
+			Description: "query built from user input",
+			Severity:    SeverityHigh,
+			Score:       8.8,
+			CWE:         []string{"CWE-89"},
+			Location:    &FindingLocation{Path: "src/db.js", Line: 12, Column: 3},
+			AuditState:  AuditStateNew,
+			Fingerprint: "hash:1",
+			Link:        "https://codeql.github.com/js-sql-injection",
+		}, findings[0])
+
+		assert.Equal(t, "Cross-Site Scripting", findings[1].Title)
+		assert.Equal(t, SeverityCritical, findings[1].Severity)
+		assert.Equal(t, []string{"CWE-79"}, findings[1].CWE)
+		assert.Equal(t, AuditStateRelevant, findings[1].AuditState)
+		assert.Equal(t, "fix it", findings[1].AuditComment)
+		assert.Len(t, findings[1].Fingerprint, 64)
+
+		assert.Equal(t, SeverityLow, findings[2].Severity)
+		assert.Equal(t, "CVE-2022-1234", findings[2].CVE)
+		assert.Equal(t, "pkg:npm/lodash@4.17.20", findings[2].PackageURL)
+		assert.NotEqual(t, findings[1].Fingerprint, findings[2].Fingerprint)
+	}
+
+	t.Run("audit state mapping", func(t *testing.T) {
+		findings := FindingsFromSARIF(sarif, func(SarifProperties) AuditState { return AuditStateInProcess })
+		assert.Equal(t, AuditStateNew, findings[0].AuditState)
+		assert.Equal(t, AuditStateInProcess, findings[1].AuditState)
+	})
+}
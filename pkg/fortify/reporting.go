@@ -238,6 +238,21 @@ func WriteCustomReports(scanReport reporting.ScanReport) ([]piperutils.Path, err
 	return reportPaths, nil
 }
 
+// ToFindings returns the scanner independent representation of the results of a SARIF report created by ConvertFprToSarif
+func ToFindings(sarif format.SARIF) []format.Finding {
+	return format.FindingsFromSARIF(sarif, AuditStates.AuditState)
+}
+
+// AuditStates maps the analysis of audited Fortify issues to the unified audit state,
+// suspicious issues are relevant like in the audit of fortifyExecuteScan with considerSuspicious
+var AuditStates = format.AuditStateMapping{AuditedOnly: true, States: map[string]format.AuditState{
+	"Exploitable":       format.AuditStateRelevant,
+	"Suspicious":        format.AuditStateRelevant,
+	"Bad Practice":      format.AuditStateNotRelevant,
+	"Reliability Issue": format.AuditStateNotRelevant,
+	"Not an Issue":      format.AuditStateNotRelevant,
+}}
+
 func reportShaFortify(parts []string) string {
 	reportShaData := []byte(strings.Join(parts, ","))
 	return fmt.Sprintf("%x", sha1.Sum(reportShaData))
@@ -0,0 +1,93 @@
+package helper
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/sprig"
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/pkg/errors"
+)
+
+const cpeRegistryTemplate = `// Code generated by piper's step-generator. DO NOT EDIT.
+
+package piperenv
+
+// cpeKeys contains the keys of the common pipeline environment which are written by the steps
+var cpeKeys = map[string]CPEKey{
+{{- range $key := . }}
+	{{ $key.Name | quote }}: {Name: {{ $key.Name | quote }}, Type: {{ $key.Type | quote }}, Producers: []string{ {{- range $i, $producer := $key.Producers }}{{ if $i }}, {{ end }}{{ $producer | quote }}{{ end -}} }},
+{{- end }}
+}
+`
+
+type cpeRegistryKey struct {
+	Name      string
+	Type      string
+	Producers []string
+}
+
+// ProcessCPERegistry generates the registry of the common pipeline environment keys based on the piperEnvironment outputs of the steps
+func ProcessCPERegistry(metadataFiles []string, targetFile string, stepHelperData StepHelperData) error {
+	keys := map[string]*cpeRegistryKey{}
+	for _, metadataFilePath := range metadataFiles {
+		metadataFile, err := stepHelperData.OpenFile(metadataFilePath)
+		if err != nil {
+			return err
+		}
+		var stepData config.StepData
+		err = stepData.ReadPipelineStepData(metadataFile)
+		metadataFile.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %v", metadataFilePath)
+		}
+		if err := addCPEKeys(keys, &stepData); err != nil {
+			return err
+		}
+	}
+
+	sortedKeys := make([]cpeRegistryKey, 0, len(keys))
+	for _, key := range keys {
+		sort.Strings(key.Producers)
+		sortedKeys = append(sortedKeys, *key)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool { return sortedKeys[i].Name < sortedKeys[j].Name })
+
+	code, err := format.Source(generateCode(sortedKeys, "cpeRegistry", cpeRegistryTemplate, sprig.HermeticTxtFuncMap()))
+	if err != nil {
+		return errors.Wrap(err, "failed to format CPE registry")
+	}
+	return stepHelperData.WriteFile(targetFile, code, 0644)
+}
+
+func addCPEKeys(keys map[string]*cpeRegistryKey, stepData *config.StepData) error {
+	for _, res := range stepData.Spec.Outputs.Resources {
+		if res.Type != "piperEnvironment" || res.Name != "commonPipelineEnvironment" {
+			continue
+		}
+		for _, param := range res.Parameters {
+			// the key corresponds to the path the generated persist function writes to
+			paramSections := strings.Split(fmt.Sprintf("%v", param["name"]), "/")
+			name := paramSections[0]
+			if len(paramSections) > 1 {
+				name = paramSections[0] + "/" + strings.Join(paramSections[1:], "_")
+			}
+			paramType := resourceFieldType(fmt.Sprint(param["type"]))
+
+			key, ok := keys[name]
+			if !ok {
+				key = &cpeRegistryKey{Name: name, Type: paramType}
+				keys[name] = key
+			}
+			if key.Type != paramType {
+				return errors.Errorf("conflicting types of common pipeline environment key '%v': '%v' in step %v and '%v' in steps %v", name, paramType, stepData.Metadata.Name, key.Type, strings.Join(key.Producers, ", "))
+			}
+			if !contains(key.Producers, stepData.Metadata.Name) {
+				key.Producers = append(key.Producers, stepData.Metadata.Name)
+			}
+		}
+	}
+	return nil
+}
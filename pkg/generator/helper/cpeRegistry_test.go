@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package helper
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessCPERegistry(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		stepHelperData := StepHelperData{configOpenFileMock, writeFileMock, ""}
+
+		err := ProcessCPERegistry([]string{"testStep.yaml"}, "cpeRegistry_generated.go", stepHelperData)
+
+		if assert.NoError(t, err) {
+			expected := `// Code generated by piper's step-generator. DO NOT EDIT.
+
+package piperenv
+
+// cpeKeys contains the keys of the common pipeline environment which are written by the steps
+var cpeKeys = map[string]CPEKey{
+	"artifactVersion":   {Name: "artifactVersion", Type: "string", Producers: []string{"testStep"}},
+	"custom/customList": {Name: "custom/customList", Type: "[]string", Producers: []string{"testStep"}},
+	"git/branch":        {Name: "git/branch", Type: "string", Producers: []string{"testStep"}},
+	"git/commitId":      {Name: "git/commitId", Type: "string", Producers: []string{"testStep"}},
+	"git/headCommitId":  {Name: "git/headCommitId", Type: "string", Producers: []string{"testStep"}},
+}
+`
+			assert.Equal(t, expected, string(files["cpeRegistry_generated.go"]))
+		}
+	})
+
+	t.Run("conflicting types", func(t *testing.T) {
+		openFile := func(name string) (io.ReadCloser, error) {
+			if name == "otherStep.yaml" {
+				return ioutil.NopCloser(strings.NewReader(`metadata:
+  name: otherStep
+spec:
+  outputs:
+    resources:
+      - name: commonPipelineEnvironment
+        type: piperEnvironment
+        params:
+          - name: custom/customList
+`)), nil
+			}
+			return configOpenFileMock(name)
+		}
+		stepHelperData := StepHelperData{openFile, writeFileMock, ""}
+
+		err := ProcessCPERegistry([]string{"testStep.yaml", "otherStep.yaml"}, "cpeRegistry_generated.go", stepHelperData)
+
+		assert.EqualError(t, err, "conflicting types of common pipeline environment key 'custom/customList': 'string' in step otherStep and '[]string' in steps testStep")
+	})
+}
@@ -140,26 +140,23 @@ const influxStructTemplate = `type {{ .StepName }}{{ .Name | title}} struct {
 	{{- end }}
 }
 
-func (i *{{ .StepName }}{{ .Name | title}}) persist(path, resourceName string) {
-	measurementContent := []struct{
-		measurement string
-		valType     string
-		name        string
-		value       interface{}
-	}{
+func (i *{{ .StepName }}{{ .Name | title}}) measurements() []metrics.InfluxValue {
+	return []metrics.InfluxValue{
 		{{- range $notused, $measurement := .Measurements }}
 		{{- range $notused, $field := $measurement.Fields }}
-		{valType: config.InfluxField, measurement: "{{ $measurement.Name }}" , name: "{{ $field.Name }}", value: i.{{ $measurement.Name }}.fields.{{ $field.Name | golangName }}},
+		{ValType: config.InfluxField, Measurement: "{{ $measurement.Name }}" , Name: "{{ $field.Name }}", Value: i.{{ $measurement.Name }}.fields.{{ $field.Name | golangName }}},
 		{{- end }}
 		{{- range $notused, $tag := $measurement.Tags }}
-		{valType: config.InfluxTag, measurement: "{{ $measurement.Name }}" , name: "{{  $tag.Name }}", value: i.{{ $measurement.Name }}.tags.{{  $tag.Name | golangName }}},
+		{ValType: config.InfluxTag, Measurement: "{{ $measurement.Name }}" , Name: "{{  $tag.Name }}", Value: i.{{ $measurement.Name }}.tags.{{  $tag.Name | golangName }}},
 		{{- end }}
 		{{- end }}
 	}
+}
 
+func (i *{{ .StepName }}{{ .Name | title}}) persist(path, resourceName string) {
 	errCount := 0
-	for _, metric := range measurementContent {
-		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+	for _, metric := range i.measurements() {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.Measurement, fmt.Sprintf("%vs", metric.ValType), metric.Name), metric.Value)
 		if err != nil {
 			log.Entry().WithError(err).Error("Error persisting influx environment.")
 			errCount++
@@ -15,9 +15,11 @@ import (
 func main() {
 	var metadataPath string
 	var targetDir string
+	var cpeRegistryFile string
 
 	flag.StringVar(&metadataPath, "metadataDir", "./resources/metadata", "The directory containing the step metadata. Default points to \\'resources/metadata\\'.")
 	flag.StringVar(&targetDir, "targetDir", "./cmd", "The target directory for the generated commands.")
+	flag.StringVar(&cpeRegistryFile, "cpeRegistryFile", "./pkg/piperenv/cpeRegistry_generated.go", "The target file for the generated registry of the common pipeline environment keys.")
 	flag.Parse()
 
 	fmt.Printf("metadataDir: %v\n, targetDir: %v\n", metadataPath, targetDir)
@@ -31,6 +33,13 @@ func main() {
 	})
 	checkError(err)
 
+	fmt.Printf("Generating common pipeline environment registry %v\n", cpeRegistryFile)
+	err = helper.ProcessCPERegistry(metadataFiles, cpeRegistryFile, helper.StepHelperData{
+		OpenFile:  openMetaFile,
+		WriteFile: fileWriter,
+	})
+	checkError(err)
+
 	fmt.Printf("Running go fmt %v\n", targetDir)
 	cmd := exec.Command("go", "fmt", targetDir)
 	r, _ := cmd.StdoutPipe()
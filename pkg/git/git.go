@@ -1,13 +1,16 @@
 package git
 
 import (
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/pkg/errors"
-	"time"
 )
 
 // utilsWorkTree interface abstraction of git.Worktree to enable tests
@@ -176,6 +179,161 @@ func getCommitObject(ref string, repo *git.Repository) (*object.Commit, error) {
 	return c, nil
 }
 
+// LatestVersionTag returns the tag with the highest semantic version among the tags starting with prefix,
+// an empty string in case there is no such tag.
+func LatestVersionTag(repo *git.Repository, prefix string) (string, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot list tags")
+	}
+	latestTag := ""
+	var latestVersion *semver.Version
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		version, err := semver.NewVersion(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			// not a version tag
+			return nil
+		}
+		if latestVersion == nil || version.GreaterThan(latestVersion) {
+			latestTag, latestVersion = name, version
+		}
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Cannot list tags")
+	}
+	return latestTag, nil
+}
+
+// VersionTags returns the versions of the tags starting with prefix, i.e. the tag names without prefix.
+func VersionTags(repo *git.Repository, prefix string) ([]string, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot list tags")
+	}
+	versions := []string{}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if len(name) > len(prefix) && strings.HasPrefix(name, prefix) {
+			versions = append(versions, strings.TrimPrefix(name, prefix))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot list tags")
+	}
+	return versions, nil
+}
+
+// FileContentAt returns the content of the file at path in the commit the reference points to, e.g. a tag.
+func FileContentAt(repo *git.Repository, ref, path string) ([]byte, error) {
+	commit, err := getCommitObject(ref, repo)
+	if err != nil {
+		return nil, err
+	}
+	file, err := commit.File(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot provide '%s' of '%s'", path, ref)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot provide '%s' of '%s'", path, ref)
+	}
+	return []byte(content), nil
+}
+
+// CommitMessagesSince returns the messages of the commits reachable from HEAD but not from 'from',
+// all commits reachable from HEAD in case 'from' is empty.
+func CommitMessagesSince(repo *git.Repository, from string) ([]string, error) {
+	commits, err := commitsSince(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	messages := []string{}
+	err = commits.ForEach(func(c *object.Commit) error {
+		messages = append(messages, c.Message)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot provide commit messages")
+	}
+	return messages, nil
+}
+
+// Change is a commit together with the paths it changed
+type Change struct {
+	Message string
+	Paths   []string
+}
+
+// ChangesSince returns the commits reachable from HEAD but not from 'from' together with the paths changed compared to their first parent,
+// all commits reachable from HEAD in case 'from' is empty.
+func ChangesSince(repo *git.Repository, from string) ([]Change, error) {
+	commits, err := commitsSince(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	changes := []Change{}
+	err = commits.ForEach(func(c *object.Commit) error {
+		paths, err := changedPaths(c)
+		if err != nil {
+			return errors.Wrapf(err, "Cannot provide changed paths of commit '%s'", c.Hash)
+		}
+		changes = append(changes, Change{Message: c.Message, Paths: paths})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+func commitsSince(repo *git.Repository, from string) (object.CommitIter, error) {
+	if len(from) > 0 {
+		return LogRange(repo, from, "HEAD")
+	}
+	head, err := getCommitObject("HEAD", repo)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot provide commits")
+	}
+	return object.NewCommitPreorderIter(head, map[plumbing.Hash]bool{}, []plumbing.Hash{}), nil
+}
+
+func changedPaths(c *object.Commit) ([]string, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var parentTree *object.Tree
+	if c.NumParents() > 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, err
+	}
+	paths := []string{}
+	for _, change := range changes {
+		if len(change.From.Name) > 0 {
+			paths = append(paths, change.From.Name)
+		}
+		if len(change.To.Name) > 0 && change.To.Name != change.From.Name {
+			paths = append(paths, change.To.Name)
+		}
+	}
+	return paths, nil
+}
+
 type abstractionGit struct{}
 
 func (abstractionGit) plainClone(path string, isBare bool, o *git.CloneOptions) (*git.Repository, error) {
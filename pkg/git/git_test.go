@@ -331,6 +331,91 @@ func TestLogRange(t *testing.T) {
 	})
 }
 
+func TestVersionTagsAndCommitMessages(t *testing.T) {
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	if !assert.NoError(t, err) {
+		return
+	}
+	w, err := r.Worktree()
+	if !assert.NoError(t, err) {
+		return
+	}
+	commitFile := func(path, message string) plumbing.Hash {
+		f, _ := fs.Create(path)
+		f.Write([]byte(message))
+		f.Close()
+		w.Add(path)
+		hash, err := w.Commit(message, &git.CommitOptions{Author: &object.Signature{Name: "me"}})
+		assert.NoError(t, err)
+		return hash
+	}
+	commit := func(message string) plumbing.Hash {
+		return commitFile("file.txt", message)
+	}
+
+	t.Run("no version tag", func(t *testing.T) {
+		commit("feat: initial")
+		tag, err := LatestVersionTag(r, "v")
+		assert.NoError(t, err)
+		assert.Equal(t, "", tag)
+
+		messages, err := CommitMessagesSince(r, tag)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"feat: initial"}, messages)
+	})
+
+	t.Run("commits since latest version tag", func(t *testing.T) {
+		hash := commit("fix: first")
+		r.CreateTag("v1.9.0", hash, nil)
+		hash = commit("fix: second")
+		r.CreateTag("v1.10.0", hash, nil)
+		r.CreateTag("other-2.0.0", hash, nil)
+		r.CreateTag("vnext", hash, nil)
+		commit("feat: third")
+
+		tag, err := LatestVersionTag(r, "v")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1.10.0", tag)
+
+		messages, err := CommitMessagesSince(r, tag)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"feat: third"}, messages)
+	})
+
+	t.Run("file content of tag", func(t *testing.T) {
+		content, err := FileContentAt(r, "v1.9.0", "file.txt")
+		assert.NoError(t, err)
+		assert.Equal(t, "fix: first", string(content))
+
+		_, err = FileContentAt(r, "v1.9.0", "missing.txt")
+		assert.EqualError(t, err, "Cannot provide 'missing.txt' of 'v1.9.0': file not found")
+	})
+
+	t.Run("versions of tags", func(t *testing.T) {
+		versions, err := VersionTags(r, "v")
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1.9.0", "1.10.0", "next"}, versions)
+	})
+
+	t.Run("changed paths since latest version tag", func(t *testing.T) {
+		commitFile("ui/package.json", "fix(ui): fourth")
+
+		changes, err := ChangesSince(r, "v1.10.0")
+		assert.NoError(t, err)
+		assert.Equal(t, []Change{
+			{Message: "fix(ui): fourth", Paths: []string{"ui/package.json"}},
+			{Message: "feat: third", Paths: []string{"file.txt"}},
+		}, changes)
+
+		changes, err = ChangesSince(r, "")
+		assert.NoError(t, err)
+		if assert.Len(t, changes, 5) {
+			assert.Equal(t, Change{Message: "feat: initial", Paths: []string{"file.txt"}}, changes[4])
+		}
+	})
+}
+
 type RepositoryMock struct {
 	worktree *git.Worktree
 	test     *testing.T
@@ -0,0 +1,327 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// CassetteModeRecord records all requests and responses to the cassette file
+	CassetteModeRecord = "record"
+	// CassetteModeReplay serves all requests from the cassette file without contacting the server
+	CassetteModeReplay = "replay"
+
+	cassetteEnvVar     = "PIPER_HTTP_CASSETTE"
+	cassetteModeEnvVar = "PIPER_HTTP_CASSETTE_MODE"
+	maskedValue        = "****"
+)
+
+// sensitiveName matches names of headers, query parameters and body fields whose values are not recorded
+const sensitiveName = `(?i:[a-z_-]*(?:token|password|passwd|secret|api[_-]?key|authorization|credential|cookie|session)[a-z_-]*)`
+
+// ErrNoRecordedInteraction is returned when replaying a request which is not contained in the cassette
+var ErrNoRecordedInteraction = errors.New("no recorded interaction")
+
+var (
+	sensitiveNameRegex   = regexp.MustCompile(`^` + sensitiveName + `$`)
+	sensitiveJSONRegex   = regexp.MustCompile(`("` + sensitiveName + `"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	sensitiveFormRegex   = regexp.MustCompile(`(^|&)(` + sensitiveName + `=)[^&]*`)
+	cassettes            = map[string]*Cassette{}
+	cassettesMutex       sync.Mutex
+	replayedInteractions = map[string]map[int]bool{}
+)
+
+// Cassette contains sanitized request/response pairs recorded from a real server
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single request/response pair of a cassette
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a sanitized request, credentials in URL, headers and body are masked
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedResponse is a sanitized response, credentials in headers and body are masked
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedBody keeps text bodies readable and encodes binary bodies, e.g. downloaded archives, as base64
+type RecordedBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"bodyBase64,omitempty"`
+}
+
+// recordingTransport writes all requests and responses passing the transport to a cassette file
+type recordingTransport struct {
+	Transport http.RoundTripper
+	path      string
+	secrets   []string
+	fileUtils piperutils.FileUtils
+}
+
+// replayTransport serves requests from a cassette file
+type replayTransport struct {
+	path      string
+	secrets   []string
+	fileUtils piperutils.FileUtils
+}
+
+// cassetteSettings returns the cassette file and mode from the client options or alternatively
+// from the environment variables PIPER_HTTP_CASSETTE and PIPER_HTTP_CASSETTE_MODE
+func (c *Client) cassetteSettings() (string, string) {
+	path, mode := c.cassette, c.cassetteMode
+	if len(path) == 0 {
+		path, mode = os.Getenv(cassetteEnvVar), os.Getenv(cassetteModeEnvVar)
+	}
+	if len(mode) == 0 {
+		mode = CassetteModeReplay
+	}
+	return path, mode
+}
+
+// cassetteTransport wraps the transport for recording or replaces it for replaying in case a cassette is configured
+func (c *Client) cassetteTransport(transport http.RoundTripper) http.RoundTripper {
+	path, mode := c.cassetteSettings()
+	if len(path) == 0 {
+		return transport
+	}
+	secrets := []string{c.password, c.token}
+	switch mode {
+	case CassetteModeRecord:
+		c.logger.Debugf("Recording http requests to cassette '%v'", path)
+		return &recordingTransport{Transport: transport, path: path, secrets: secrets, fileUtils: c.getFileUtils()}
+	case CassetteModeReplay:
+		c.logger.Debugf("Replaying http requests from cassette '%v'", path)
+		return &replayTransport{path: path, secrets: secrets, fileUtils: c.getFileUtils()}
+	default:
+		c.logger.Debugf("Unknown cassette mode '%v', http requests are neither recorded nor replayed", mode)
+		return transport
+	}
+}
+
+// logCassette informs once about the cassette of the client since the transport is created for each request
+func (c *Client) logCassette() {
+	path, mode := c.cassetteSettings()
+	if len(path) == 0 {
+		return
+	}
+	switch mode {
+	case CassetteModeRecord:
+		c.logger.Infof("Recording http requests to cassette '%v'", path)
+	case CassetteModeReplay:
+		c.logger.Infof("Replaying http requests from cassette '%v'", path)
+	default:
+		c.logger.Warningf("Unknown cassette mode '%v', expected '%v' or '%v', http requests are neither recorded nor replayed", mode, CassetteModeRecord, CassetteModeReplay)
+	}
+}
+
+// RoundTrip sends the request and appends the sanitized interaction to the cassette.
+// The cassette file is rewritten after each interaction thus it is complete even if the step fails.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body for recording")
+	}
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body for recording")
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:       req.Method,
+			URL:          sanitizeCassetteURL(req.URL, t.secrets),
+			Header:       sanitizeHeader(req.Header, t.secrets),
+			RecordedBody: sanitizeBody(requestBody, t.secrets),
+		},
+		Response: RecordedResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       sanitizeHeader(resp.Header, t.secrets),
+			RecordedBody: sanitizeBody(responseBody, t.secrets),
+		},
+	}
+
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+	cassette, ok := cassettes[t.path]
+	if !ok {
+		// a cassette is recorded from scratch by each run, but shared by all clients of the run
+		cassette = &Cassette{}
+		cassettes[t.path] = cassette
+	}
+	cassette.Interactions = append(cassette.Interactions, interaction)
+	content, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal cassette")
+	}
+	if err := t.fileUtils.FileWrite(t.path, content, 0644); err != nil {
+		return nil, errors.Wrapf(err, "failed to write cassette '%v'", t.path)
+	}
+	return resp, nil
+}
+
+// RoundTrip returns the first unused recorded interaction with the same method and URL.
+// Once all matching interactions are used the last one is repeated, e.g. for polling a scan status.
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cassettesMutex.Lock()
+	defer cassettesMutex.Unlock()
+	cassette, err := t.load()
+	if err != nil {
+		return nil, err
+	}
+	used := replayedInteractions[t.path]
+	requestURL := sanitizeCassetteURL(req.URL, t.secrets)
+	match := -1
+	for i, interaction := range cassette.Interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.URL != requestURL {
+			continue
+		}
+		match = i
+		if !used[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, errors.Wrapf(ErrNoRecordedInteraction, "%v %v in cassette '%v'", req.Method, requestURL, t.path)
+	}
+	used[match] = true
+
+	recorded := cassette.Interactions[match].Response
+	body, err := recorded.content()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode recorded response body of %v %v", req.Method, requestURL)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %v", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// load reads the cassette once per process, the caller has to hold cassettesMutex
+func (t *replayTransport) load() (*Cassette, error) {
+	if _, ok := replayedInteractions[t.path]; !ok {
+		replayedInteractions[t.path] = map[int]bool{}
+	}
+	if cassette, ok := cassettes[t.path]; ok {
+		return cassette, nil
+	}
+	content, err := t.fileUtils.FileRead(t.path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read cassette '%v'", t.path)
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(content, cassette); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse cassette '%v'", t.path)
+	}
+	cassettes[t.path] = cassette
+	return cassette, nil
+}
+
+func (b RecordedBody) content() ([]byte, error) {
+	if len(b.BodyBase64) > 0 {
+		return base64.StdEncoding.DecodeString(b.BodyBase64)
+	}
+	return []byte(b.Body), nil
+}
+
+// readBody reads the body and replaces it with a reader on the read content
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	content, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(content))
+	return content, err
+}
+
+func maskCredentials(text string, secrets []string) string {
+	for _, secret := range secrets {
+		if len(secret) > 0 {
+			text = strings.ReplaceAll(text, secret, maskedValue)
+		}
+	}
+	return log.MaskSecrets(text)
+}
+
+// sanitizeCassetteURL removes user info and masks sensitive query parameters
+func sanitizeCassetteURL(u *url.URL, secrets []string) string {
+	sanitized := *u
+	sanitized.User = nil
+	query := sanitized.Query()
+	for name := range query {
+		if sensitiveNameRegex.MatchString(name) {
+			query.Set(name, maskedValue)
+		}
+	}
+	sanitized.RawQuery = query.Encode()
+	return maskCredentials(sanitized.String(), secrets)
+}
+
+// sanitizeHeader keeps sensitive headers to document their presence but masks their values
+func sanitizeHeader(header http.Header, secrets []string) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	sanitized := http.Header{}
+	for name, values := range header {
+		for _, value := range values {
+			if sensitiveNameRegex.MatchString(name) {
+				value = maskedValue
+			}
+			sanitized.Add(name, maskCredentials(value, secrets))
+		}
+	}
+	return sanitized
+}
+
+// sanitizeBody masks sensitive JSON and form fields as well as known credentials in text bodies
+func sanitizeBody(body []byte, secrets []string) RecordedBody {
+	if len(body) == 0 {
+		return RecordedBody{}
+	}
+	if !utf8.Valid(body) {
+		return RecordedBody{BodyBase64: base64.StdEncoding.EncodeToString(body)}
+	}
+	text := sensitiveJSONRegex.ReplaceAllString(string(body), `$1"`+maskedValue+`"`)
+	text = sensitiveFormRegex.ReplaceAllString(text, `$1$2`+maskedValue)
+	return RecordedBody{Body: maskCredentials(text, secrets)}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/pkcs12"
+)
+
+// ClientCertificate defines the certificate a client presents for mutual TLS
+type ClientCertificate struct {
+	// Certificate is the path to a PEM file containing the certificate chain, optionally followed by the key,
+	// or to a PKCS#12 file which can be base64 encoded, e.g. when stored as Vault secret
+	Certificate string
+	// Key is the path to a PEM file containing the private key in case it is not contained in Certificate
+	Key string
+	// Password decrypts a PKCS#12 file
+	Password string
+}
+
+// loadedCertificate is a client certificate which is read and parsed once and then reused by all requests
+type loadedCertificate struct {
+	path        string
+	certificate tls.Certificate
+}
+
+var (
+	defaultClientCertificate      *loadedCertificate
+	defaultClientCertificateMutex sync.Mutex
+)
+
+// SetDefaultClientCertificate sets the certificate which is used by all clients without an explicit ClientCertificate option,
+// thus all steps can use mutual TLS based on the general configuration
+func SetDefaultClientCertificate(certificate ClientCertificate) {
+	log.RegisterSecret(certificate.Password)
+	loaded := newLoadedCertificate(&piperutils.Files{}, certificate)
+	defaultClientCertificateMutex.Lock()
+	defer defaultClientCertificateMutex.Unlock()
+	defaultClientCertificate = loaded
+}
+
+// newLoadedCertificate reads and parses the client certificate, it returns nil without certificate or in case it cannot be loaded
+func newLoadedCertificate(fileUtils piperutils.FileUtils, clientCertificate ClientCertificate) *loadedCertificate {
+	if len(clientCertificate.Certificate) == 0 {
+		return nil
+	}
+	certificate, err := loadClientCertificate(fileUtils, clientCertificate)
+	if err != nil {
+		log.Entry().Warningf("adding client certificate for tls failed: %v, continuing without client certificate", err)
+		return nil
+	}
+	return &loadedCertificate{path: clientCertificate.Certificate, certificate: certificate}
+}
+
+func (c *Client) getClientCertificate() *loadedCertificate {
+	if c.clientCertificate != nil {
+		return c.clientCertificate
+	}
+	defaultClientCertificateMutex.Lock()
+	defer defaultClientCertificateMutex.Unlock()
+	return defaultClientCertificate
+}
+
+// configureClientCertificate adds the client certificate to the TLS configuration of the transport
+func (c *Client) configureClientCertificate(transport *TransportWrapper) error {
+	clientCertificate := c.getClientCertificate()
+	if clientCertificate == nil {
+		return nil
+	}
+	httpTransport, ok := transport.Transport.(*http.Transport)
+	if !ok || httpTransport.TLSClientConfig == nil {
+		return errors.New("transport does not support TLS configuration")
+	}
+	httpTransport.TLSClientConfig.Certificates = []tls.Certificate{clientCertificate.certificate}
+	log.Entry().Debugf("Using client certificate '%v' for mutual TLS", clientCertificate.path)
+	return nil
+}
+
+func loadClientCertificate(fileUtils piperutils.FileUtils, clientCertificate ClientCertificate) (tls.Certificate, error) {
+	content, err := fileUtils.FileRead(clientCertificate.Certificate)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to read client certificate '%v'", clientCertificate.Certificate)
+	}
+	if len(clientCertificate.Key) > 0 {
+		key, err := fileUtils.FileRead(clientCertificate.Key)
+		if err != nil {
+			return tls.Certificate{}, errors.Wrapf(err, "failed to read client key '%v'", clientCertificate.Key)
+		}
+		return parseCertificate(tls.X509KeyPair(content, key))
+	}
+	if bytes.Contains(content, []byte("-----BEGIN")) {
+		return parseCertificate(tls.X509KeyPair(content, content))
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content))); err == nil {
+		content = decoded
+	}
+	blocks, err := pkcs12.ToPEM(content, clientCertificate.Password)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrapf(err, "failed to decode PKCS#12 client certificate '%v'", clientCertificate.Certificate)
+	}
+	var pemContent []byte
+	for _, block := range blocks {
+		pemContent = append(pemContent, pem.EncodeToMemory(block)...)
+	}
+	return parseCertificate(tls.X509KeyPair(pemContent, pemContent))
+}
+
+func parseCertificate(certificate tls.Certificate, err error) (tls.Certificate, error) {
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to parse client certificate")
+	}
+	return certificate, nil
+}
@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/stretchr/testify/assert"
+)
+
+// generateClientCertificate returns a self-signed certificate and its key in PEM format
+func generateClientCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "piper-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func commonName(t *testing.T, certificate tls.Certificate) string {
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	assert.NoError(t, err)
+	return parsed.Subject.CommonName
+}
+
+func TestLoadClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateClientCertificate(t)
+	pkcs12Content, err := os.ReadFile(filepath.Join("testdata", "client.p12"))
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"cert.pem":       certPEM,
+		"key.pem":        keyPEM,
+		"combined.pem":   append(append([]byte{}, certPEM...), keyPEM...),
+		"client.p12":     pkcs12Content,
+		"client.p12.b64": []byte(base64.StdEncoding.EncodeToString(pkcs12Content) + "\n"),
+	}
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), content, 0600))
+	}
+	fileUtils := &piperutils.Files{}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	t.Run("PEM certificate and key", func(t *testing.T) {
+		certificate, err := loadClientCertificate(fileUtils, ClientCertificate{Certificate: path("cert.pem"), Key: path("key.pem")})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "piper-test-client", commonName(t, certificate))
+		}
+	})
+
+	t.Run("PEM containing certificate and key", func(t *testing.T) {
+		_, err := loadClientCertificate(fileUtils, ClientCertificate{Certificate: path("combined.pem")})
+		assert.NoError(t, err)
+	})
+
+	t.Run("PKCS#12", func(t *testing.T) {
+		certificate, err := loadClientCertificate(fileUtils, ClientCertificate{Certificate: path("client.p12"), Password: "secret"})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "piper-test-client", commonName(t, certificate))
+		}
+	})
+
+	t.Run("base64 encoded PKCS#12", func(t *testing.T) {
+		_, err := loadClientCertificate(fileUtils, ClientCertificate{Certificate: path("client.p12.b64"), Password: "secret"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("wrong PKCS#12 password", func(t *testing.T) {
+		_, err := loadClientCertificate(fileUtils, ClientCertificate{Certificate: path("client.p12"), Password: "wrong"})
+		assert.Contains(t, err.Error(), "failed to decode PKCS#12 client certificate")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := loadClientCertificate(fileUtils, ClientCertificate{Certificate: path("cert.pem"), Key: path("missing.pem")})
+		assert.Contains(t, err.Error(), "failed to read client key")
+	})
+}
+
+func TestMutualTLS(t *testing.T) {
+	certPEM, keyPEM := generateClientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certPEM)
+
+	svr := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	svr.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	svr.StartTLS()
+	defer svr.Close()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), certPEM, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "key.pem"), keyPEM, 0600))
+	clientCertificate := ClientCertificate{Certificate: filepath.Join(dir, "cert.pem"), Key: filepath.Join(dir, "key.pem")}
+
+	t.Run("client certificate option", func(t *testing.T) {
+		client := Client{}
+		client.SetOptions(ClientOptions{TransportSkipVerification: true, MaxRetries: -1, ClientCertificate: clientCertificate})
+		response, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		if assert.NoError(t, err) {
+			body := new(bytes.Buffer)
+			body.ReadFrom(response.Body)
+			assert.Equal(t, "piper-test-client", body.String())
+		}
+	})
+
+	t.Run("certificate is loaded once", func(t *testing.T) {
+		onceDir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(onceDir, "cert.pem"), certPEM, 0600))
+		assert.NoError(t, os.WriteFile(filepath.Join(onceDir, "key.pem"), keyPEM, 0600))
+		client := Client{}
+		client.SetOptions(ClientOptions{TransportSkipVerification: true, MaxRetries: -1, ClientCertificate: ClientCertificate{Certificate: filepath.Join(onceDir, "cert.pem"), Key: filepath.Join(onceDir, "key.pem")}})
+		// subsequent requests must not read the files again
+		assert.NoError(t, os.RemoveAll(onceDir))
+		for i := 0; i < 2; i++ {
+			_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+			assert.NoError(t, err)
+		}
+	})
+
+	t.Run("default client certificate", func(t *testing.T) {
+		defer SetDefaultClientCertificate(ClientCertificate{})
+		SetDefaultClientCertificate(clientCertificate)
+		client := Client{}
+		client.SetOptions(ClientOptions{TransportSkipVerification: true, MaxRetries: -1})
+		_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		assert.NoError(t, err)
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		client := Client{}
+		client.SetOptions(ClientOptions{TransportSkipVerification: true, MaxRetries: -1})
+		_, err := client.SendRequest(http.MethodGet, svr.URL, nil, nil, nil)
+		assert.Error(t, err)
+	})
+}
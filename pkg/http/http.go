@@ -21,10 +21,14 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/tracing"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/motemen/go-nuts/roundtime"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client defines an http client object
@@ -44,6 +48,11 @@ type Client struct {
 	useDefaultTransport       bool
 	trustedCerts              []string
 	fileUtils                 piperutils.FileUtils
+	retryPolicy               RetryPolicy
+	cassette                  string
+	cassetteMode              string
+	tokenSource               TokenSource
+	clientCertificate         *loadedCertificate
 }
 
 // ClientOptions defines the options to be set on the client
@@ -68,6 +77,21 @@ type ClientOptions struct {
 	DoLogResponseBodyOnDebug  bool
 	UseDefaultTransport       bool
 	TrustedCerts              []string
+	// RetryPolicy defines the backoff between retries and the circuit breaker per host.
+	// Unset values are defaulted, the circuit breaker is disabled by default.
+	RetryPolicy RetryPolicy
+	// Cassette is a file to which sanitized requests and responses are recorded or from which they are replayed,
+	// depending on CassetteMode which is either "record" or "replay" (default). Without a cassette the
+	// environment variables PIPER_HTTP_CASSETTE and PIPER_HTTP_CASSETTE_MODE are used.
+	Cassette     string
+	CassetteMode string
+	// TokenSource provides the Authorization header, e.g. using NewClientCredentials, in case the request
+	// does not contain one. It takes precedence over Token and Username/Password. Requests rejected
+	// with 401 are resent once with a new token.
+	TokenSource TokenSource
+	// ClientCertificate is presented to servers requiring mutual TLS. Without a certificate the
+	// default set via SetDefaultClientCertificate is used. It is ignored with UseDefaultTransport.
+	ClientCertificate ClientCertificate
 }
 
 // TransportWrapper is a wrapper for central round trip capabilities
@@ -78,6 +102,8 @@ type TransportWrapper struct {
 	username                 string
 	password                 string
 	token                    string
+	retryPolicy              *RetryPolicy
+	tokenSource              TokenSource
 }
 
 // UploadRequestData encapsulates the parameters for calling uploader.Upload()
@@ -260,6 +286,12 @@ func (c *Client) SetOptions(options ClientOptions) {
 	c.cookieJar = options.CookieJar
 	c.trustedCerts = options.TrustedCerts
 	c.fileUtils = &piperutils.Files{}
+	c.retryPolicy = options.RetryPolicy
+	c.cassette = options.Cassette
+	c.cassetteMode = options.CassetteMode
+	c.tokenSource = options.TokenSource
+	c.clientCertificate = newLoadedCertificate(c.fileUtils, options.ClientCertificate)
+	c.logCassette()
 }
 
 // SetFileUtils can be used to overwrite the default file utils
@@ -294,6 +326,8 @@ func (c *Client) initialize() *http.Client {
 		token:                    c.token,
 		username:                 c.username,
 		password:                 c.password,
+		retryPolicy:              &c.retryPolicy,
+		tokenSource:              c.tokenSource,
 	}
 
 	if len(c.trustedCerts) > 0 && !c.useDefaultTransport && !c.transportSkipVerification {
@@ -305,6 +339,14 @@ func (c *Client) initialize() *http.Client {
 	} else {
 		log.Entry().Debug("no trusted certs found / using default transport / insecure skip set to true / : continuing with existing tls config")
 	}
+	if !c.useDefaultTransport {
+		if err := c.configureClientCertificate(transport); err != nil {
+			log.Entry().Warningf("adding client certificate for tls failed: %v, continuing without client certificate", err)
+		}
+	}
+	if !c.useDefaultTransport {
+		transport.Transport = c.cassetteTransport(transport.Transport)
+	}
 
 	var httpClient *http.Client
 	if c.maxRetries > 0 {
@@ -319,27 +361,29 @@ func (c *Client) initialize() *http.Client {
 			retryClient.HTTPClient.Transport = transport
 		} else {
 			retryClient.HTTPClient.Transport = &TransportWrapper{
-				Transport:                retryClient.HTTPClient.Transport,
+				Transport:                c.cassetteTransport(retryClient.HTTPClient.Transport),
 				doLogRequestBodyOnDebug:  c.doLogRequestBodyOnDebug,
 				doLogResponseBodyOnDebug: c.doLogResponseBodyOnDebug,
 				token:                    c.token,
 				username:                 c.username,
-				password:                 c.password}
-		}
-		retryClient.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
-			if err != nil && (strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset")) {
-				// Assuming timeouts, resets, and similar could be retried
-				return true, nil
-			}
-			return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+				password:                 c.password,
+				retryPolicy:              &c.retryPolicy,
+				tokenSource:              c.tokenSource}
 		}
+		retryClient.RetryWaitMin = c.retryPolicy.MinWait
+		retryClient.RetryWaitMax = c.retryPolicy.MaxWait
+		retryClient.CheckRetry = c.retryPolicy.checkRetry
+		retryClient.Backoff = c.retryPolicy.backoff
 		httpClient = retryClient.StandardClient()
+		httpClient.Transport = &retryCountingTransport{Transport: httpClient.Transport}
 	} else {
 		httpClient = &http.Client{}
 		httpClient.Timeout = c.maxRequestDuration
 		httpClient.Jar = c.cookieJar
 		if !c.useDefaultTransport {
 			httpClient.Transport = transport
+		} else if path, _ := c.cassetteSettings(); len(path) > 0 {
+			httpClient.Transport = c.cassetteTransport(http.DefaultTransport)
 		}
 	}
 
@@ -357,25 +401,94 @@ type contextKey struct {
 }
 
 var contextKeyRequestStart = &contextKey{"RequestStart"}
+var contextKeyAttempts = &contextKey{"Attempts"}
 var authHeaderKey = "Authorization"
 
+// retryCountingTransport provides a counter to the TransportWrapper which is shared by all attempts of a request
+type retryCountingTransport struct {
+	Transport http.RoundTripper
+}
+
+func (t *retryCountingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := 0
+	return t.Transport.RoundTrip(req.WithContext(context.WithValue(req.Context(), contextKeyAttempts, &attempts)))
+}
+
 // RoundTrip is the core part of this module and implements http.RoundTripper.
 // Executes HTTP requests with request/response logging.
 func (t *TransportWrapper) RoundTrip(req *http.Request) (*http.Response, error) {
 	ctx := context.WithValue(req.Context(), contextKeyRequestStart, time.Now())
+	ctx, span := t.startSpan(ctx, req)
+	defer span.End()
 	req = req.WithContext(ctx)
 
+	if t.retryPolicy != nil {
+		if err := t.retryPolicy.allowRequest(req.URL.Host); err != nil {
+			endSpan(span, nil, err)
+			return nil, err
+		}
+	}
+
+	usesTokenSource, err := t.authenticateWithTokenSource(req)
+	if err != nil {
+		endSpan(span, nil, err)
+		return nil, err
+	}
 	handleAuthentication(req, t.username, t.password, t.token)
 
 	t.logRequest(req)
 
 	resp, err := t.Transport.RoundTrip(req)
+	if usesTokenSource && err == nil && resp.StatusCode == http.StatusUnauthorized {
+		resp, err = t.retryWithNewToken(req, resp)
+	}
 
 	t.logResponse(resp)
+	endSpan(span, resp, err)
+	if t.retryPolicy != nil {
+		t.retryPolicy.recordResult(req.URL.Host, resp, err)
+	}
 
 	return resp, err
 }
 
+// startSpan creates a tracing span for a single attempt of a request
+func (t *TransportWrapper) startSpan(ctx context.Context, req *http.Request) (context.Context, trace.Span) {
+	retryCount := 0
+	if attempts, ok := ctx.Value(contextKeyAttempts).(*int); ok {
+		retryCount = *attempts
+		*attempts++
+	}
+	return tracing.StartSpan(ctx, fmt.Sprintf("HTTP %v %v", req.Method, req.URL.Hostname()),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", sanitizeURL(req.URL)),
+			attribute.String("net.peer.name", req.URL.Hostname()),
+			attribute.Int("http.retry_count", retryCount),
+		))
+}
+
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+	}
+}
+
+// sanitizeURL removes user info, query and fragment from the URL since they may contain credentials
+func sanitizeURL(u *url.URL) string {
+	sanitized := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	return sanitized.String()
+}
+
 func handleAuthentication(req *http.Request, username, password, token string) {
 	// Handle authentication if not done already
 	if (len(username) > 0 || len(password) > 0) && len(req.Header.Get(authHeaderKey)) == 0 {
@@ -480,7 +593,10 @@ func (c *Client) createRequest(method, url string, body io.Reader, header *http.
 		}
 	}
 
-	handleAuthentication(request, c.username, c.password, c.token)
+	if c.tokenSource == nil {
+		// with a token source the transport sets the header, since the token may change between retries
+		handleAuthentication(request, c.username, c.password, c.token)
+	}
 
 	for _, cookie := range cookies {
 		request.AddCookie(cookie)
@@ -516,6 +632,7 @@ func (c *Client) applyDefaults() {
 	if c.logger == nil {
 		c.logger = log.Entry().WithField("package", "SAP/jenkins-library/pkg/http")
 	}
+	c.retryPolicy.applyDefaults()
 }
 
 func (c *Client) configureTLSToTrustCertificates(transport *TransportWrapper) error {
@@ -553,6 +670,8 @@ func (c *Client) configureTLSToTrustCertificates(transport *TransportWrapper) er
 		token:                    c.token,
 		username:                 c.username,
 		password:                 c.password,
+		retryPolicy:              &c.retryPolicy,
+		tokenSource:              c.tokenSource,
 	}
 
 	for _, certificate := range c.trustedCerts {
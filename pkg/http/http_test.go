@@ -27,6 +27,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"github.com/SAP/jenkins-library/pkg/log"
 )
@@ -224,12 +229,13 @@ func TestSetOptions(t *testing.T) {
 }
 
 func TestApplyDefaults(t *testing.T) {
+	defaultRetryPolicy := RetryPolicy{MinWait: 1 * time.Second, MaxWait: 30 * time.Second, MaxRateLimitWait: 5 * time.Minute, CircuitBreakerCooldown: 1 * time.Minute}
 	tt := []struct {
 		client   Client
 		expected Client
 	}{
-		{client: Client{}, expected: Client{transportTimeout: 3 * time.Minute, maxRequestDuration: 0, logger: log.Entry().WithField("package", "SAP/jenkins-library/pkg/http")}},
-		{client: Client{transportTimeout: 10, maxRequestDuration: 5}, expected: Client{transportTimeout: 10, maxRequestDuration: 5, logger: log.Entry().WithField("package", "SAP/jenkins-library/pkg/http")}},
+		{client: Client{}, expected: Client{transportTimeout: 3 * time.Minute, maxRequestDuration: 0, logger: log.Entry().WithField("package", "SAP/jenkins-library/pkg/http"), retryPolicy: defaultRetryPolicy}},
+		{client: Client{transportTimeout: 10, maxRequestDuration: 5}, expected: Client{transportTimeout: 10, maxRequestDuration: 5, logger: log.Entry().WithField("package", "SAP/jenkins-library/pkg/http"), retryPolicy: defaultRetryPolicy}},
 	}
 
 	for k, v := range tt {
@@ -493,6 +499,41 @@ func TestMaxRetries(t *testing.T) {
 	}
 }
 
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(sdktrace.NewTracerProvider())
+
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer svr.Close()
+	svrURL, _ := url.Parse(svr.URL)
+	svrURL.User = url.UserPassword("user", "secret")
+
+	client := Client{maxRetries: 1}
+	_, err := client.SendRequest(http.MethodGet, svrURL.String()+"/api/scans?token=secret", &bytes.Buffer{}, nil, nil)
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	if assert.Len(t, spans, 2) {
+		assert.Contains(t, spans[0].Attributes, attribute.String("http.url", svr.URL+"/api/scans"))
+		assert.Contains(t, spans[0].Attributes, attribute.String("http.method", http.MethodGet))
+		assert.Contains(t, spans[0].Attributes, attribute.Int("http.retry_count", 0))
+		assert.Contains(t, spans[0].Attributes, attribute.Int("http.status_code", http.StatusServiceUnavailable))
+		assert.Equal(t, codes.Error, spans[0].Status.Code)
+		assert.Contains(t, spans[1].Attributes, attribute.Int("http.retry_count", 1))
+		assert.Contains(t, spans[1].Attributes, attribute.Int("http.status_code", http.StatusOK))
+		assert.Equal(t, codes.Unset, spans[1].Status.Code)
+	}
+}
+
 func TestParseHTTPResponseBodyJSON(t *testing.T) {
 
 	type myJSONStruct struct {
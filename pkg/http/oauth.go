@@ -0,0 +1,173 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// TokenSource provides the value of the Authorization header for requests
+type TokenSource interface {
+	// Token returns a valid token including its type, e.g. "Bearer eyJhbGci..."
+	Token() (string, error)
+	// Invalidate discards a cached token, e.g. after it has been rejected by the server
+	Invalidate()
+}
+
+// ClientCredentials fetches access tokens using the OAuth2 client credentials grant
+// and caches them until shortly before they expire
+type ClientCredentials struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	// ExpiryDelta is the time before the expiry of a token when a new token is fetched. Default: 1m
+	ExpiryDelta time.Duration
+	client      Sender
+	mutex       sync.Mutex
+	token       string
+	expiresAt   time.Time
+}
+
+type accessTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// NewClientCredentials creates a token source for the OAuth2 token endpoint at tokenURL, e.g. https://my.authentication.example.com/oauth/token.
+// The client credentials are sent via basic authentication.
+func NewClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) *ClientCredentials {
+	log.RegisterSecret(clientSecret)
+	client := &Client{}
+	client.SetOptions(ClientOptions{Username: clientID, Password: clientSecret})
+	return &ClientCredentials{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		ExpiryDelta:  1 * time.Minute,
+		client:       client,
+	}
+}
+
+// Token returns the cached token or fetches a new one in case there is none or it is about to expire
+func (c *ClientCredentials) Token() (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.token) > 0 && (c.expiresAt.IsZero() || now().Add(c.ExpiryDelta).Before(c.expiresAt)) {
+		return c.token, nil
+	}
+	token, expiresAt, err := c.fetchToken()
+	if err != nil {
+		return "", err
+	}
+	c.token, c.expiresAt = token, expiresAt
+	return c.token, nil
+}
+
+// Invalidate discards the cached token, thus the next call of Token fetches a new one
+func (c *ClientCredentials) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = ""
+	c.expiresAt = time.Time{}
+}
+
+func (c *ClientCredentials) fetchToken() (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.scopes) > 0 {
+		form.Set("scope", strings.Join(c.scopes, " "))
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	header.Set("Accept", "application/json")
+
+	log.Entry().Debugf("Fetching access token for client '%v' from %v", c.clientID, c.tokenURL)
+	response, err := c.client.SendRequest(http.MethodPost, c.tokenURL, bytes.NewBufferString(form.Encode()), header, nil)
+	if err != nil {
+		return "", time.Time{}, errors.Wrapf(err, "failed to fetch access token from '%v'", c.tokenURL)
+	}
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", time.Time{}, errors.Wrap(err, "failed to read access token response")
+	}
+	var accessToken accessTokenResponse
+	if err := json.Unmarshal(content, &accessToken); err != nil {
+		return "", time.Time{}, errors.Wrap(err, "failed to parse access token response")
+	}
+	if len(accessToken.AccessToken) == 0 {
+		return "", time.Time{}, errors.Errorf("access token response of '%v' does not contain 'access_token'", c.tokenURL)
+	}
+	log.RegisterSecret(accessToken.AccessToken)
+
+	tokenType := accessToken.TokenType
+	if len(tokenType) == 0 || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	var expiresAt time.Time
+	if accessToken.ExpiresIn > 0 {
+		expiresAt = now().Add(time.Duration(accessToken.ExpiresIn) * time.Second)
+	}
+	return tokenType + " " + accessToken.AccessToken, expiresAt, nil
+}
+
+// authenticateWithTokenSource sets the Authorization header from the token source in case no header is set yet.
+// The request body is buffered to be able to resend the request with a new token.
+// req has to be a copy of the original request, e.g. created by WithContext.
+func (t *TransportWrapper) authenticateWithTokenSource(req *http.Request) (bool, error) {
+	if t.tokenSource == nil || len(req.Header.Get(authHeaderKey)) > 0 {
+		return false, nil
+	}
+	token, err := t.tokenSource.Token()
+	if err != nil {
+		return false, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		content, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return false, errors.Wrap(err, "failed to read request body")
+		}
+		req.GetBody = func() (io.ReadCloser, error) { return ioutil.NopCloser(bytes.NewReader(content)), nil }
+		req.Body, _ = req.GetBody()
+	}
+	// the header of the original request is kept untouched, thus retries of the request use the current token
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(authHeaderKey, token)
+	req.Header = header
+	log.Entry().Debug("Using OAuth2 Token Authentication ****")
+	return true, nil
+}
+
+// retryWithNewToken resends a request rejected with 401 once with a new token, since a token may be revoked before its expiry
+func (t *TransportWrapper) retryWithNewToken(req *http.Request, resp *http.Response) (*http.Response, error) {
+	t.tokenSource.Invalidate()
+	token, err := t.tokenSource.Token()
+	if err != nil {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	retry.Header.Set(authHeaderKey, token)
+	resp.Body.Close()
+	log.Entry().Debug("Request was rejected with 401, retrying with a new token")
+	return t.Transport.RoundTrip(retry)
+}
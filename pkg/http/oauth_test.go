@@ -0,0 +1,123 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tokenServer struct {
+	tokensIssued int
+	expiresIn    int
+	form         string
+	username     string
+	password     string
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.tokensIssued++
+	s.username, s.password, _ = r.BasicAuth()
+	body, _ := ioutil.ReadAll(r.Body)
+	s.form = string(body)
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"access_token":"token%v","token_type":"bearer","expires_in":%v}`, s.tokensIssued, s.expiresIn)
+}
+
+func TestClientCredentials(t *testing.T) {
+	defer func() { now = time.Now }()
+
+	t.Run("token is cached until shortly before expiry", func(t *testing.T) {
+		current := time.Now()
+		now = func() time.Time { return current }
+		server := &tokenServer{expiresIn: 3600}
+		svr := httptest.NewServer(server)
+		defer svr.Close()
+
+		source := NewClientCredentials(svr.URL+"/oauth/token", "clientId", "clientSecret", "read", "write")
+		token, err := source.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token1", token)
+		assert.Equal(t, "grant_type=client_credentials&scope=read+write", server.form)
+		assert.Equal(t, "clientId", server.username)
+		assert.Equal(t, "clientSecret", server.password)
+
+		current = current.Add(58 * time.Minute)
+		token, _ = source.Token()
+		assert.Equal(t, "Bearer token1", token)
+
+		current = current.Add(1 * time.Minute)
+		token, _ = source.Token()
+		assert.Equal(t, "Bearer token2", token)
+
+		source.Invalidate()
+		token, _ = source.Token()
+		assert.Equal(t, "Bearer token3", token)
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"error":"invalid_client"}`))
+		}))
+		defer svr.Close()
+
+		_, err := NewClientCredentials(svr.URL, "clientId", "clientSecret").Token()
+		assert.EqualError(t, err, "access token response of '"+svr.URL+"' does not contain 'access_token'")
+	})
+}
+
+func TestTokenSourceAuthentication(t *testing.T) {
+	server := &tokenServer{}
+	tokenSvr := httptest.NewServer(server)
+	defer tokenSvr.Close()
+
+	validToken := "Bearer token1"
+	var authHeaders, bodies []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeaders = append(authHeaders, r.Header.Get("Authorization"))
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") != validToken {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer svr.Close()
+
+	source := NewClientCredentials(tokenSvr.URL, "clientId", "clientSecret")
+	client := Client{}
+	client.SetOptions(ClientOptions{TokenSource: source, Token: "static", MaxRetries: -1})
+
+	t.Run("token of the source is used", func(t *testing.T) {
+		_, err := client.SendRequest(http.MethodPost, svr.URL, bytes.NewBufferString("payload"), nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Bearer token1"}, authHeaders)
+	})
+
+	t.Run("revoked token is refreshed", func(t *testing.T) {
+		authHeaders, bodies = nil, nil
+		validToken = "Bearer token2"
+		_, err := client.SendRequest(http.MethodPost, svr.URL, bytes.NewBufferString("payload"), nil, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Bearer token1", "Bearer token2"}, authHeaders)
+		assert.Equal(t, []string{"payload", "payload"}, bodies)
+		assert.Equal(t, 2, server.tokensIssued)
+	})
+
+	t.Run("explicit header is kept", func(t *testing.T) {
+		authHeaders = nil
+		header := http.Header{}
+		header.Set("Authorization", "Basic abc")
+		_, err := client.SendRequest(http.MethodGet, svr.URL, nil, header, nil)
+		assert.Error(t, err)
+		assert.Equal(t, []string{"Basic abc"}, authHeaders)
+		assert.Equal(t, 2, server.tokensIssued)
+	})
+}
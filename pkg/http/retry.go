@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+)
+
+// ErrCircuitOpen is returned for requests to a host which failed too often in a row
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// RetryPolicy defines how failed requests are retried and when requests to a failing host are rejected
+type RetryPolicy struct {
+	// MinWait is the wait time before the first retry, it is doubled for each consecutive failure of the host. Default: 1s
+	MinWait time.Duration
+	// MaxWait is the maximum wait time between two retries. Default: 30s
+	MaxWait time.Duration
+	// MaxRateLimitWait is the maximum wait time requested via Retry-After or X-RateLimit-Reset
+	// which is respected, longer wait times are cut. Default: 5m
+	MaxRateLimitWait time.Duration
+	// CircuitBreakerThreshold is the number of consecutive server errors or connection failures of a host
+	// after which all requests to the host fail fast with ErrCircuitOpen. Default: 0, i.e. disabled
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is the time the circuit stays open until a single request to the host is tried again. Default: 1m
+	CircuitBreakerCooldown time.Duration
+}
+
+// hostState tracks the consecutive failures of a host across all clients
+type hostState struct {
+	failures  int
+	openUntil time.Time
+}
+
+var (
+	hostStates      = map[string]*hostState{}
+	hostStatesMutex sync.Mutex
+	now             = time.Now
+)
+
+func (p *RetryPolicy) applyDefaults() {
+	if p.MinWait == 0 {
+		p.MinWait = 1 * time.Second
+	}
+	if p.MaxWait == 0 {
+		p.MaxWait = 30 * time.Second
+	}
+	if p.MaxRateLimitWait == 0 {
+		p.MaxRateLimitWait = 5 * time.Minute
+	}
+	if p.CircuitBreakerCooldown == 0 {
+		p.CircuitBreakerCooldown = 1 * time.Minute
+	}
+}
+
+func getHostState(host string) *hostState {
+	state, ok := hostStates[host]
+	if !ok {
+		state = &hostState{}
+		hostStates[host] = state
+	}
+	return state
+}
+
+// allowRequest returns ErrCircuitOpen in case the circuit of the host is open.
+// After the cooldown the circuit is half open, i.e. the next request is let through while the others still fail fast.
+func (p *RetryPolicy) allowRequest(host string) error {
+	if p.CircuitBreakerThreshold <= 0 {
+		return nil
+	}
+	hostStatesMutex.Lock()
+	defer hostStatesMutex.Unlock()
+	state := getHostState(host)
+	if state.failures < p.CircuitBreakerThreshold {
+		return nil
+	}
+	if now().Before(state.openUntil) {
+		return errors.Wrapf(ErrCircuitOpen, "%v failed %v times in a row, retry after %v", host, state.failures, state.openUntil.Format(time.RFC3339))
+	}
+	// half open: let this request pass and reject others until its result is known
+	state.openUntil = now().Add(p.CircuitBreakerCooldown)
+	return nil
+}
+
+// recordResult updates the failure streak of the host, server errors and connection failures count as failure
+func (p *RetryPolicy) recordResult(host string, resp *http.Response, err error) {
+	hostStatesMutex.Lock()
+	defer hostStatesMutex.Unlock()
+	state := getHostState(host)
+	if err != nil || (resp != nil && resp.StatusCode >= 500) {
+		state.failures++
+		if p.CircuitBreakerThreshold > 0 && state.failures >= p.CircuitBreakerThreshold {
+			log.Entry().Warnf("%v failed %v times in a row, requests are rejected for %v", host, state.failures, p.CircuitBreakerCooldown)
+			state.openUntil = now().Add(p.CircuitBreakerCooldown)
+		}
+		return
+	}
+	state.failures = 0
+	state.openUntil = time.Time{}
+}
+
+func (p *RetryPolicy) hostFailures(host string) int {
+	hostStatesMutex.Lock()
+	defer hostStatesMutex.Unlock()
+	return getHostState(host).failures
+}
+
+// checkRetry extends the default retry policy of retryablehttp by rate limits, rejected requests of an open circuit
+// and requests missing in a replayed cassette
+func (p *RetryPolicy) checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil && (errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrNoRecordedInteraction)) {
+		return false, err
+	}
+	if err != nil && (strings.Contains(err.Error(), "timeout") || strings.Contains(err.Error(), "timed out") || strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "connection reset")) {
+		// Assuming timeouts, resets, and similar could be retried
+		return true, nil
+	}
+	if resp != nil && isRateLimited(resp) {
+		return true, nil
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
+
+// backoff respects the wait time requested by the server and otherwise increases the wait time
+// with the number of consecutive failures of the host, thus all clients back off from a failing host.
+func (p *RetryPolicy) backoff(_, _ time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := requestedWait(resp); ok {
+			if wait > p.MaxRateLimitWait {
+				log.Entry().Debugf("server requested to wait %v, waiting %v", wait, p.MaxRateLimitWait)
+				wait = p.MaxRateLimitWait
+			}
+			return wait
+		}
+	}
+	exponent := attemptNum
+	if resp != nil && resp.Request != nil {
+		// the streak already contains the failure of the current attempt
+		if streak := p.hostFailures(resp.Request.URL.Host) - 1; streak > exponent {
+			exponent = streak
+		}
+	}
+	wait := float64(p.MinWait) * math.Pow(2, float64(exponent))
+	if wait > float64(p.MaxWait) {
+		return p.MaxWait
+	}
+	return time.Duration(wait)
+}
+
+// isRateLimited detects rate limits which are reported with status 403, e.g. by GitHub, besides 429
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("X-RateLimit-Remaining") == "0" || len(resp.Header.Get("Retry-After")) > 0)
+}
+
+// requestedWait returns the wait time requested via Retry-After, either in seconds or as HTTP date,
+// or via X-RateLimit-Reset as epoch seconds in case no requests are remaining
+func requestedWait(resp *http.Response) (time.Duration, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); len(retryAfter) > 0 {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return nonNegative(date.Sub(now())), true
+		}
+	}
+	if isRateLimited(resp) {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return nonNegative(time.Unix(reset, 0).Sub(now())), true
+		}
+	}
+	return 0, false
+}
+
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,136 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestedWait(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Date(2023, time.March, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	tt := []struct {
+		name     string
+		status   int
+		header   map[string]string
+		expected time.Duration
+		ok       bool
+	}{
+		{name: "Retry-After seconds", status: http.StatusServiceUnavailable, header: map[string]string{"Retry-After": "120"}, expected: 2 * time.Minute, ok: true},
+		{name: "Retry-After date", status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "Wed, 01 Mar 2023 10:00:30 GMT"}, expected: 30 * time.Second, ok: true},
+		{name: "GitHub rate limit", status: http.StatusForbidden, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(current.Add(time.Minute).Unix(), 10)}, expected: time.Minute, ok: true},
+		{name: "rate limit reset in the past", status: http.StatusTooManyRequests, header: map[string]string{"X-RateLimit-Reset": strconv.FormatInt(current.Add(-time.Minute).Unix(), 10)}, expected: 0, ok: true},
+		{name: "forbidden without rate limit", status: http.StatusForbidden, header: map[string]string{"X-RateLimit-Remaining": "10", "X-RateLimit-Reset": "1677664860"}, ok: false},
+		{name: "no header", status: http.StatusInternalServerError, ok: false},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: test.status, Header: http.Header{}}
+			for k, v := range test.header {
+				resp.Header.Set(k, v)
+			}
+			wait, ok := requestedWait(resp)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, wait)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	defer func() { hostStates = map[string]*hostState{} }()
+	policy := RetryPolicy{MaxWait: 10 * time.Second}
+	policy.applyDefaults()
+
+	t.Run("exponential by attempt", func(t *testing.T) {
+		assert.Equal(t, 1*time.Second, policy.backoff(0, 0, 0, nil))
+		assert.Equal(t, 4*time.Second, policy.backoff(0, 0, 2, nil))
+		assert.Equal(t, 10*time.Second, policy.backoff(0, 0, 5, nil))
+	})
+
+	t.Run("exponential by failures of host", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "https://flaky.example.com/api", nil)
+		for i := 0; i < 3; i++ {
+			policy.recordResult("flaky.example.com", &http.Response{StatusCode: http.StatusBadGateway}, nil)
+		}
+		assert.Equal(t, 4*time.Second, policy.backoff(0, 0, 0, &http.Response{StatusCode: http.StatusBadGateway, Request: request}))
+	})
+
+	t.Run("requested wait is capped", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"3600"}}}
+		assert.Equal(t, 5*time.Minute, policy.backoff(0, 0, 0, resp))
+	})
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	defer func() {
+		hostStates = map[string]*hostState{}
+		now = time.Now
+	}()
+
+	healthy := false
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer svr.Close()
+
+	newClient := func() *Client {
+		client := &Client{}
+		client.SetOptions(ClientOptions{MaxRetries: 5, RetryPolicy: RetryPolicy{MinWait: time.Millisecond, MaxWait: time.Millisecond, CircuitBreakerThreshold: 2}})
+		return client
+	}
+
+	_, err := newClient().SendRequest(http.MethodGet, svr.URL, &bytes.Buffer{}, nil, nil)
+	assert.True(t, errors.Is(err, ErrCircuitOpen), "expected open circuit, got %v", err)
+	assert.Equal(t, 2, count)
+
+	// other clients fail fast as well
+	_, err = newClient().SendRequest(http.MethodGet, svr.URL, &bytes.Buffer{}, nil, nil)
+	assert.True(t, errors.Is(err, ErrCircuitOpen), "expected open circuit, got %v", err)
+	assert.Equal(t, 2, count)
+
+	// after the cooldown a request is let through and closes the circuit
+	healthy = true
+	now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = newClient().SendRequest(http.MethodGet, svr.URL, &bytes.Buffer{}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	_, err = newClient().SendRequest(http.MethodGet, svr.URL, &bytes.Buffer{}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	count := 0
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer svr.Close()
+
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: 2})
+	_, err := client.SendRequest(http.MethodGet, svr.URL, &bytes.Buffer{}, nil, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,355 @@
+package http
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+const (
+	downloadCacheEnvVar = "PIPER_DOWNLOAD_CACHE"
+	partialFileSuffix   = ".part"
+	// validatorFileSuffix is appended to the partial file for the file storing the validator of the partial content
+	validatorFileSuffix = ".validator"
+	// maxResumeAttempts is the number of times an interrupted download is resumed
+	maxResumeAttempts = 5
+)
+
+// VerifiedDownloader downloads files with resume, checksum verification and caching
+type VerifiedDownloader interface {
+	DownloadVerified(url, filename string, options DownloadOptions) error
+}
+
+// DownloadOptions defines how a file is downloaded by DownloadVerified
+type DownloadOptions struct {
+	Header  http.Header
+	Cookies []*http.Cookie
+	// Digest is the expected checksum of the file, either with the algorithm as prefix, e.g. "sha256:<hex>" or "sha512:<hex>",
+	// or as plain hex string in which case the algorithm is derived from the length
+	Digest string
+	// DigestURL is the URL of a sidecar file containing the checksum in the format of sha256sum or sha512sum, e.g. <url>.sha256.
+	// It is used in case no Digest is provided.
+	DigestURL string
+	// CacheDir is the directory of a cache for files with known digest. Without a directory the environment variable
+	// PIPER_DOWNLOAD_CACHE is used, if it is not set either, no cache is used.
+	CacheDir string
+}
+
+type digest struct {
+	algorithm string
+	value     string
+}
+
+// DownloadVerified downloads the file via GET request to filename. Interrupted downloads are resumed via Range requests
+// in case the server provided a validator (ETag or Last-Modified) for the partial file.
+// In case a digest is known, the file is verified and stored in the cache, thus later downloads of the same URL and digest are served from the cache.
+func (c *Client) DownloadVerified(url, filename string, options DownloadOptions) error {
+	fileUtils := c.getFileUtils()
+	expected, err := c.expectedDigest(url, options)
+	if err != nil {
+		return err
+	}
+
+	cacheDir := options.CacheDir
+	if len(cacheDir) == 0 {
+		cacheDir = os.Getenv(downloadCacheEnvVar)
+	}
+	var cacheFile string
+	if len(cacheDir) > 0 && expected != nil {
+		cacheFile = filepath.Join(cacheDir, cacheKey(url, expected))
+		if exists, _ := fileUtils.FileExists(cacheFile); exists {
+			if err := verifyDigest(fileUtils, cacheFile, expected); err == nil {
+				log.Entry().Infof("Using cached download of %v", url)
+				return copyFile(fileUtils, cacheFile, filename)
+			}
+			log.Entry().Warnf("Ignoring corrupted cache entry '%v' of %v", cacheFile, url)
+		}
+	}
+
+	partialFile := filename + partialFileSuffix
+	if err := c.downloadResumable(url, partialFile, options); err != nil {
+		return err
+	}
+	fileUtils.FileRemove(partialFile + validatorFileSuffix)
+	if expected != nil {
+		if err := verifyDigest(fileUtils, partialFile, expected); err != nil {
+			// the partial file must not be resumed by the next attempt
+			fileUtils.FileRemove(partialFile)
+			return errors.Wrapf(err, "verification of %v failed", url)
+		}
+	}
+	if err := fileUtils.FileRename(partialFile, filename); err != nil {
+		return errors.Wrapf(err, "unable to move download to %v", filename)
+	}
+
+	if len(cacheFile) > 0 {
+		if err := copyFile(fileUtils, filename, cacheFile); err != nil {
+			log.Entry().WithError(err).Warnf("Failed to add %v to the download cache", url)
+		}
+	}
+	return nil
+}
+
+// downloadResumable appends to an existing partial file and resumes an interrupted download.
+// The validator of the partial file is sent via If-Range, thus the server sends the whole file in case it changed in the meantime.
+func (c *Client) downloadResumable(url, partialFile string, options DownloadOptions) error {
+	fileUtils := c.getFileUtils()
+	validatorFile := partialFile + validatorFileSuffix
+	if parent := filepath.Dir(partialFile); len(parent) > 0 {
+		if err := fileUtils.MkdirAll(parent, 0775); err != nil {
+			return err
+		}
+	}
+	var lastErr error
+	for attempt := 0; attempt <= maxResumeAttempts; attempt++ {
+		offset := fileSize(fileUtils, partialFile)
+		header := options.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		if offset > 0 {
+			validator, _ := fileUtils.FileRead(validatorFile)
+			if len(validator) > 0 {
+				header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+				header.Set("If-Range", string(validator))
+				log.Entry().Infof("Resuming download of %v at byte %v", url, offset)
+			} else {
+				// without validator it is unknown whether the partial file belongs to the current file
+				log.Entry().Infof("Restarting download of %v since the partial file cannot be validated", url)
+				offset = 0
+			}
+		}
+
+		response, err := c.SendRequest(http.MethodGet, url, nil, header, options.Cookies)
+		if err != nil {
+			if response != nil && response.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+				// the partial file is not a prefix of the current file, e.g. since the file changed
+				response.Body.Close()
+				fileUtils.FileRemove(partialFile)
+				fileUtils.FileRemove(validatorFile)
+				lastErr = err
+				continue
+			}
+			return errors.Wrapf(err, "HTTP GET request to %v failed with error", url)
+		}
+
+		if response.StatusCode == http.StatusPartialContent && offset > 0 {
+			lastErr = appendBody(fileUtils, partialFile, response.Body)
+		} else {
+			// the server sends the whole file, e.g. since it does not support ranges or the file changed
+			if err := fileUtils.FileWrite(validatorFile, []byte(responseValidator(response)), 0644); err != nil {
+				response.Body.Close()
+				return errors.Wrapf(err, "unable to create file %v", validatorFile)
+			}
+			lastErr = writeBody(fileUtils, partialFile, response.Body)
+		}
+		if lastErr == nil {
+			return nil
+		}
+		log.Entry().WithError(lastErr).Warnf("Download of %v was interrupted", url)
+	}
+	return errors.Wrapf(lastErr, "download of %v failed after %v attempts", url, maxResumeAttempts+1)
+}
+
+// responseValidator returns the validator of the response usable for If-Range, i.e. a strong ETag or the Last-Modified date
+func responseValidator(response *http.Response) string {
+	if etag := response.Header.Get("ETag"); len(etag) > 0 && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return response.Header.Get("Last-Modified")
+}
+
+// fileSize returns the size of a file, 0 in case it does not exist
+func fileSize(fileUtils piperutils.FileUtils, filename string) int64 {
+	file, err := fileUtils.Open(filename)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+	size, _ := io.Copy(io.Discard, file)
+	return size
+}
+
+func writeBody(fileUtils piperutils.FileUtils, filename string, body io.ReadCloser) error {
+	defer body.Close()
+	file, err := fileUtils.Create(filename)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create file %v", filename)
+	}
+	defer file.Close()
+	if _, err := piperutils.CopyData(file, body); err != nil {
+		return errors.Wrapf(err, "unable to copy content from url to file %v", filename)
+	}
+	return nil
+}
+
+// appendBody appends the body to the file via a temporary file.
+// The received part of the body is kept in case the download is interrupted.
+func appendBody(fileUtils piperutils.FileUtils, filename string, body io.ReadCloser) error {
+	defer body.Close()
+	tempFile := filename + ".tmp"
+	file, err := fileUtils.Create(tempFile)
+	if err != nil {
+		return errors.Wrapf(err, "unable to create file %v", tempFile)
+	}
+	if err := copyContent(fileUtils, filename, file); err != nil {
+		file.Close()
+		fileUtils.FileRemove(tempFile)
+		return err
+	}
+	_, copyErr := piperutils.CopyData(file, body)
+	file.Close()
+	fileUtils.FileRemove(filename)
+	if err := fileUtils.FileRename(tempFile, filename); err != nil {
+		return errors.Wrapf(err, "unable to move %v to %v", tempFile, filename)
+	}
+	if copyErr != nil {
+		return errors.Wrapf(copyErr, "unable to copy content from url to file %v", filename)
+	}
+	return nil
+}
+
+func copyContent(fileUtils piperutils.FileUtils, filename string, target io.Writer) error {
+	file, err := fileUtils.Open(filename)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open file %v", filename)
+	}
+	defer file.Close()
+	if _, err := io.Copy(target, file); err != nil {
+		return errors.Wrapf(err, "unable to read %v", filename)
+	}
+	return nil
+}
+
+// expectedDigest returns the digest from the options or from the sidecar file, nil in case none is provided
+func (c *Client) expectedDigest(url string, options DownloadOptions) (*digest, error) {
+	if len(options.Digest) > 0 {
+		return parseDigest(options.Digest)
+	}
+	if len(options.DigestURL) == 0 {
+		return nil, nil
+	}
+	response, err := c.SendRequest(http.MethodGet, options.DigestURL, nil, options.Header, options.Cookies)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download checksum from %v", options.DigestURL)
+	}
+	defer response.Body.Close()
+	content, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read checksum from %v", options.DigestURL)
+	}
+	value, err := checksumForFile(string(content), path.Base(url))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid checksum file %v", options.DigestURL)
+	}
+	for _, algorithm := range []string{"sha256", "sha512"} {
+		if strings.HasSuffix(options.DigestURL, "."+algorithm) {
+			return parseDigest(algorithm + ":" + value)
+		}
+	}
+	return parseDigest(value)
+}
+
+// checksumForFile returns the checksum of the file from a checksum file with lines in the format "<checksum>  <file name>".
+// In case the checksum file contains a single checksum, the file name is optional.
+func checksumForFile(content, fileName string) (string, error) {
+	var checksums [][]string
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			checksums = append(checksums, fields)
+		}
+	}
+	for _, fields := range checksums {
+		// sha256sum marks binary mode with a '*' in front of the file name
+		if len(fields) > 1 && strings.TrimPrefix(fields[1], "*") == fileName {
+			return fields[0], nil
+		}
+	}
+	if len(checksums) == 1 {
+		return checksums[0][0], nil
+	}
+	return "", errors.Errorf("no checksum found for %v", fileName)
+}
+
+func parseDigest(value string) (*digest, error) {
+	algorithm := ""
+	if parts := strings.SplitN(value, ":", 2); len(parts) == 2 {
+		algorithm, value = strings.ToLower(parts[0]), parts[1]
+	}
+	value = strings.ToLower(strings.TrimSpace(value))
+	if _, err := hex.DecodeString(value); err != nil {
+		return nil, errors.Errorf("invalid digest '%v', expected hex encoded checksum", value)
+	}
+	if len(algorithm) == 0 {
+		switch len(value) {
+		case sha256.Size * 2:
+			algorithm = "sha256"
+		case sha512.Size * 2:
+			algorithm = "sha512"
+		}
+	}
+	if newHash(algorithm) == nil {
+		return nil, errors.Errorf("unsupported digest '%v', expected SHA-256 or SHA-512", value)
+	}
+	return &digest{algorithm: algorithm, value: value}, nil
+}
+
+func newHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "sha256":
+		return sha256.New()
+	case "sha512":
+		return sha512.New()
+	}
+	return nil
+}
+
+func verifyDigest(fileUtils piperutils.FileUtils, filename string, expected *digest) error {
+	file, err := fileUtils.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hasher := newHash(expected.algorithm)
+	if _, err := io.Copy(hasher, file); err != nil {
+		return errors.Wrapf(err, "unable to read %v", filename)
+	}
+	if actual := hex.EncodeToString(hasher.Sum(nil)); actual != expected.value {
+		return errors.Errorf("%v checksum mismatch: expected %v, got %v", expected.algorithm, expected.value, actual)
+	}
+	return nil
+}
+
+// cacheKey addresses a cache entry by URL and digest, thus a changed digest for the same URL is never served from the cache
+func cacheKey(url string, expected *digest) string {
+	key := sha256.Sum256([]byte(url + "\n" + expected.algorithm + ":" + expected.value))
+	encoded := hex.EncodeToString(key[:])
+	return filepath.Join(encoded[:2], encoded)
+}
+
+func copyFile(fileUtils piperutils.FileUtils, source, target string) error {
+	if parent := filepath.Dir(target); len(parent) > 0 {
+		if err := fileUtils.MkdirAll(parent, 0775); err != nil {
+			return err
+		}
+	}
+	if _, err := fileUtils.Copy(source, target); err != nil {
+		return errors.Wrapf(err, "unable to copy %v to %v", source, target)
+	}
+	return nil
+}
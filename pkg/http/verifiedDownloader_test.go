@@ -0,0 +1,196 @@
+//go:build unit
+// +build unit
+
+package http
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var downloadContent = bytes.Repeat([]byte("piper"), 10000)
+
+func contentDigest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// newDownloadServer serves downloadContent with support for ranges, the first request is interrupted after half of the content
+func newDownloadServer(interrupt bool) (*httptest.Server, *[]string) {
+	ranges := []string{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tool.tar.gz.sha256":
+			w.Write([]byte(contentDigest([]byte("other")) + "  other.tar.gz\n" + contentDigest(downloadContent) + " *tool.tar.gz\n"))
+			return
+		}
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		if interrupt && len(ranges) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+			w.Write(downloadContent[:len(downloadContent)/2])
+			return
+		}
+		http.ServeContent(w, r, "tool.tar.gz", time.Time{}, bytes.NewReader(downloadContent))
+	})), &ranges
+}
+
+func TestDownloadVerified(t *testing.T) {
+	client := Client{}
+	client.SetOptions(ClientOptions{MaxRetries: -1})
+
+	t.Run("interrupted download is resumed", func(t *testing.T) {
+		svr, ranges := newDownloadServer(true)
+		defer svr.Close()
+		target := filepath.Join(t.TempDir(), "tools", "tool.tar.gz")
+
+		err := client.DownloadVerified(svr.URL+"/tool.tar.gz", target, DownloadOptions{Digest: "sha256:" + contentDigest(downloadContent)})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{"", "bytes=" + strconv.Itoa(len(downloadContent)/2) + "-"}, *ranges)
+			content, _ := os.ReadFile(target)
+			assert.Equal(t, downloadContent, content)
+			assert.NoFileExists(t, target+".part")
+		}
+	})
+
+	t.Run("changed file is downloaded again", func(t *testing.T) {
+		requests := 0
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.Header().Set("ETag", `"v1"`)
+				w.Header().Set("Content-Length", strconv.Itoa(len(downloadContent)))
+				w.Write([]byte("stale"))
+				return
+			}
+			assert.Equal(t, `"v1"`, r.Header.Get("If-Range"))
+			w.Header().Set("ETag", `"v2"`)
+			http.ServeContent(w, r, "tool.tar.gz", time.Time{}, bytes.NewReader(downloadContent))
+		}))
+		defer svr.Close()
+		target := filepath.Join(t.TempDir(), "tool.tar.gz")
+
+		err := client.DownloadVerified(svr.URL+"/tool.tar.gz", target, DownloadOptions{Digest: contentDigest(downloadContent)})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, 2, requests)
+			content, _ := os.ReadFile(target)
+			assert.Equal(t, downloadContent, content)
+			assert.NoFileExists(t, target+".part.validator")
+		}
+	})
+
+	t.Run("partial file without validator is not resumed", func(t *testing.T) {
+		svr, ranges := newDownloadServer(false)
+		defer svr.Close()
+		target := filepath.Join(t.TempDir(), "tool.tar.gz")
+		os.WriteFile(target+".part", downloadContent[:100], 0644)
+
+		err := client.DownloadVerified(svr.URL+"/tool.tar.gz", target, DownloadOptions{Digest: contentDigest(downloadContent)})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{""}, *ranges)
+			content, _ := os.ReadFile(target)
+			assert.Equal(t, downloadContent, content)
+		}
+	})
+
+	t.Run("server without range support", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(downloadContent)
+		}))
+		defer svr.Close()
+		target := filepath.Join(t.TempDir(), "tool.tar.gz")
+		os.WriteFile(target+".part", []byte("stale"), 0644)
+
+		err := client.DownloadVerified(svr.URL+"/tool.tar.gz", target, DownloadOptions{})
+
+		if assert.NoError(t, err) {
+			content, _ := os.ReadFile(target)
+			assert.Equal(t, downloadContent, content)
+		}
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		svr, _ := newDownloadServer(false)
+		defer svr.Close()
+		target := filepath.Join(t.TempDir(), "tool.tar.gz")
+
+		err := client.DownloadVerified(svr.URL+"/tool.tar.gz", target, DownloadOptions{Digest: contentDigest([]byte("other"))})
+
+		assert.Contains(t, err.Error(), "sha256 checksum mismatch")
+		assert.NoFileExists(t, target)
+		assert.NoFileExists(t, target+".part")
+	})
+
+	t.Run("checksum from sidecar file and cache", func(t *testing.T) {
+		svr, ranges := newDownloadServer(false)
+		cacheDir := t.TempDir()
+		options := DownloadOptions{DigestURL: svr.URL + "/tool.tar.gz.sha256", CacheDir: cacheDir}
+		target := filepath.Join(t.TempDir(), "tool.tar.gz")
+
+		err := client.DownloadVerified(svr.URL+"/tool.tar.gz", target, options)
+		assert.NoError(t, err)
+		assert.Len(t, *ranges, 1)
+
+		// the file is served from the cache without contacting the server
+		options.DigestURL = ""
+		options.Digest = contentDigest(downloadContent)
+		svr.Close()
+		cachedTarget := filepath.Join(t.TempDir(), "tool.tar.gz")
+		err = client.DownloadVerified(svr.URL+"/tool.tar.gz", cachedTarget, options)
+		if assert.NoError(t, err) {
+			content, _ := os.ReadFile(cachedTarget)
+			assert.Equal(t, downloadContent, content)
+		}
+	})
+}
+
+func TestParseDigest(t *testing.T) {
+	sha512Digest := hex.EncodeToString(bytes.Repeat([]byte{0xab}, 64))
+	tt := []struct {
+		value     string
+		algorithm string
+		err       string
+	}{
+		{value: "SHA256:" + contentDigest(downloadContent), algorithm: "sha256"},
+		{value: contentDigest(downloadContent), algorithm: "sha256"},
+		{value: sha512Digest, algorithm: "sha512"},
+		{value: "md5:d41d8cd98f00b204e9800998ecf8427e", err: "unsupported digest 'd41d8cd98f00b204e9800998ecf8427e', expected SHA-256 or SHA-512"},
+		{value: "sha256:xyz", err: "invalid digest 'xyz', expected hex encoded checksum"},
+	}
+	for _, test := range tt {
+		t.Run(test.value, func(t *testing.T) {
+			parsed, err := parseDigest(test.value)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, test.algorithm, parsed.algorithm)
+			}
+		})
+	}
+}
+
+func TestChecksumForFile(t *testing.T) {
+	checksum, err := checksumForFile("abc  tool-linux.tar.gz\ndef  tool.tar.gz\n", "tool.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "def", checksum)
+
+	checksum, err = checksumForFile("abc\n", "tool.tar.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", checksum)
+
+	_, err = checksumForFile("abc  tool-linux.tar.gz\ndef  tool-darwin.tar.gz\n", "tool.tar.gz")
+	assert.EqualError(t, err, "no checksum found for tool.tar.gz")
+}
@@ -1,5 +1,7 @@
 package log
 
+import "sync"
+
 // ErrorCategory defines the category of a pipeline error
 type ErrorCategory int
 
@@ -18,6 +20,12 @@ const (
 var errorCategory ErrorCategory = ErrorUndefined
 var fatalError []byte
 
+var (
+	consoleErrorLine  string
+	consoleErrorHint  string
+	consoleErrorMutex sync.Mutex
+)
+
 func (e ErrorCategory) String() string {
 	return [...]string{
 		"undefined",
@@ -74,3 +82,31 @@ func SetFatalErrorDetail(error []byte) {
 func GetFatalErrorDetail() []byte {
 	return fatalError
 }
+
+// SetConsoleError records the console line which identified the error category together with a hint how to solve the error.
+// Only the first line is kept since subsequent errors are often caused by the first one, the return value indicates whether the line has been recorded.
+func SetConsoleError(line, hint string) bool {
+	consoleErrorMutex.Lock()
+	defer consoleErrorMutex.Unlock()
+	if len(consoleErrorLine) > 0 {
+		return false
+	}
+	consoleErrorLine = line
+	consoleErrorHint = hint
+	return true
+}
+
+// GetConsoleError retrieves the console line and the hint recorded via SetConsoleError
+func GetConsoleError() (string, string) {
+	consoleErrorMutex.Lock()
+	defer consoleErrorMutex.Unlock()
+	return consoleErrorLine, consoleErrorHint
+}
+
+// ResetConsoleError removes the recorded console line and hint
+func ResetConsoleError() {
+	consoleErrorMutex.Lock()
+	defer consoleErrorMutex.Unlock()
+	consoleErrorLine = ""
+	consoleErrorHint = ""
+}
@@ -91,3 +91,18 @@ func TestGetFatalErrorDetail(t *testing.T) {
 		})
 	}
 }
+
+func TestSetConsoleError(t *testing.T) {
+	defer ResetConsoleError()
+	assert.True(t, SetConsoleError("[ERROR] first error", "check the first error"))
+	assert.False(t, SetConsoleError("[ERROR] subsequent error", ""))
+
+	line, hint := GetConsoleError()
+	assert.Equal(t, "[ERROR] first error", line)
+	assert.Equal(t, "check the first error", hint)
+
+	ResetConsoleError()
+	line, hint = GetConsoleError()
+	assert.Empty(t, line)
+	assert.Empty(t, hint)
+}
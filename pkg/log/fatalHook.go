@@ -28,6 +28,22 @@ func (f *FatalHook) Fire(entry *logrus.Entry) error {
 	if details == nil {
 		details = logrus.Fields{}
 	}
+	// keep details which were recorded before the fatal error, e.g. the output of a terminated command
+	previousDetails := map[string]interface{}{}
+	if err := json.Unmarshal(GetFatalErrorDetail(), &previousDetails); err == nil && previousDetails["result"] == nil {
+		for key, value := range previousDetails {
+			if _, exists := details[key]; !exists {
+				details[key] = value
+			}
+		}
+	}
+
+	if line, hint := GetConsoleError(); len(line) > 0 && details["consoleError"] == nil {
+		details["consoleError"] = MaskSecrets(line)
+		if len(hint) > 0 {
+			details["remediationHint"] = hint
+		}
+	}
 
 	details["message"] = entry.Message
 	details["error"] = fmt.Sprint(details["error"])
@@ -65,6 +65,32 @@ func TestFatalHookFire(t *testing.T) {
 		assert.Contains(t, string(fileContent), `"message":"the error message"`)
 	})
 
+	t.Run("details recorded before", func(t *testing.T) {
+		defer SetFatalErrorDetail(nil)
+		SetFatalErrorDetail([]byte(`{"message":"command 'mvn' was terminated","outputTail":"last line"}`))
+		hook := FatalHook{Path: t.TempDir()}
+		entry := logrus.Entry{Message: "the error message"}
+
+		err := hook.Fire(&entry)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(GetFatalErrorDetail()), `"outputTail":"last line"`)
+		assert.Contains(t, string(GetFatalErrorDetail()), `"message":"the error message"`)
+	})
+
+	t.Run("console error", func(t *testing.T) {
+		defer ResetConsoleError()
+		SetConsoleError("npm ERR! code E401", "renew the npm token")
+		hook := FatalHook{Path: t.TempDir()}
+		entry := logrus.Entry{Message: "the error message"}
+
+		err := hook.Fire(&entry)
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(GetFatalErrorDetail()), `"consoleError":"npm ERR! code E401"`)
+		assert.Contains(t, string(GetFatalErrorDetail()), `"remediationHint":"renew the npm token"`)
+	})
+
 	t.Run("file exists", func(t *testing.T) {
 		hook := FatalHook{}
 		entry := logrus.Entry{
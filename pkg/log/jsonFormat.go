@@ -0,0 +1,55 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// formatJSON formats a log entry as a single line JSON object including the correlation information of the pipeline run
+// Secrets are masked in all string values.
+func formatJSON(entry *logrus.Entry) ([]byte, error) {
+	data := make(map[string]interface{}, len(entry.Data)+8)
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case error:
+			// errors would otherwise be marshalled as empty objects
+			data[key] = maskSecrets(v.Error())
+		case string:
+			data[key] = maskSecrets(v)
+		case fmt.Stringer:
+			data[key] = maskSecrets(v.String())
+		default:
+			data[key] = value
+		}
+	}
+
+	level, _ := entry.Level.MarshalText()
+	message := maskSecrets(entry.Message)
+	data["time"] = entry.Time.Format(time.RFC3339Nano)
+	data["level"] = string(level)
+	data["message"] = message
+	data["stageName"] = stageName
+	data["correlationId"] = correlationID
+	data["errorCategory"] = GetErrorCategory().String()
+	if _, ok := data["stepName"]; !ok {
+		data["stepName"] = ""
+	}
+
+	stepName, _ := data["stepName"].(string)
+	urls := []string{}
+	for _, url := range parseURLs([]byte(message), returnURLStrictClassifier(stepName)) {
+		urls = append(urls, string(url))
+	}
+	if len(urls) > 0 {
+		data["urls"] = urls
+	}
+
+	serialized, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal log entry to JSON: %w", err)
+	}
+	return append(serialized, '\n'), nil
+}
@@ -0,0 +1,59 @@
+//go:build unit
+// +build unit
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatJSON(t *testing.T) {
+	outWriter := Entry().Logger.Out
+	formatter := Entry().Logger.Formatter
+	var buffer bytes.Buffer
+	Entry().Logger.SetOutput(&buffer)
+	SetFormatter(logFormatJSON)
+	defer func() {
+		Entry().Logger.SetOutput(outWriter)
+		Entry().Logger.SetFormatter(formatter)
+		SetStageName("")
+		SetCorrelationID("")
+		SetErrorCategory(ErrorUndefined)
+	}()
+
+	SetStageName("Build")
+	SetCorrelationID("https://ci.example.com/job/42")
+	SetErrorCategory(ErrorBuild)
+	RegisterSecret("jsonSecret")
+
+	Entry().WithField("stepName", "mavenBuild").WithError(errors.New("failed with jsonSecret")).
+		Error("see https://ci.example.com/report.html for jsonSecret details")
+	Entry().WithField("stepName", "mavenBuild").Info("second line")
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.NotContains(t, buffer.String(), "jsonSecret")
+
+	var logEntry map[string]interface{}
+	if assert.NoError(t, json.Unmarshal([]byte(lines[0]), &logEntry)) {
+		assert.Equal(t, "error", logEntry["level"])
+		assert.Equal(t, "see https://ci.example.com/report.html for **** details", logEntry["message"])
+		assert.Equal(t, "failed with ****", logEntry["error"])
+		assert.Equal(t, "mavenBuild", logEntry["stepName"])
+		assert.Equal(t, "Build", logEntry["stageName"])
+		assert.Equal(t, "https://ci.example.com/job/42", logEntry["correlationId"])
+		assert.Equal(t, "build", logEntry["errorCategory"])
+		assert.Equal(t, []interface{}{"https://ci.example.com/report.html"}, logEntry["urls"])
+		assert.NotEmpty(t, logEntry["time"])
+	}
+	if assert.NoError(t, json.Unmarshal([]byte(lines[1]), &logEntry)) {
+		assert.Equal(t, "info", logEntry["level"])
+		assert.Equal(t, "second line", logEntry["message"])
+	}
+}
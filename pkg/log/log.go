@@ -19,10 +19,15 @@ const (
 	logFormatPlain         = "plain"
 	logFormatDefault       = "default"
 	logFormatWithTimestamp = "timestamp"
+	logFormatJSON          = "json"
 )
 
 // Format the log message
 func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, err error) {
+	if formatter.logFormat == logFormatJSON {
+		return formatJSON(entry)
+	}
+
 	message := ""
 
 	stepName := entry.Data["stepName"]
@@ -56,11 +61,20 @@ func (formatter *PiperLogFormatter) Format(entry *logrus.Entry) (bytes []byte, e
 		message = string(formattedMessage)
 	}
 
+	return []byte(maskSecrets(message)), nil
+}
+
+// maskSecrets replaces all registered secrets in the given text
+func maskSecrets(text string) string {
 	for _, secret := range secrets {
-		message = strings.Replace(message, secret, "****", -1)
+		text = strings.Replace(text, secret, "****", -1)
 	}
+	return text
+}
 
-	return []byte(message), nil
+// MaskSecrets replaces all registered secrets in the given text, e.g. before it is persisted
+func MaskSecrets(text string) string {
+	return maskSecrets(text)
 }
 
 // LibraryRepository that is passed into with -ldflags
@@ -68,6 +82,8 @@ var LibraryRepository string
 var LibraryName string
 var logger *logrus.Entry
 var secrets []string
+var stageName string
+var correlationID string
 
 // Entry returns the logger entry or creates one if none is present.
 func Entry() *logrus.Entry {
@@ -102,9 +118,43 @@ func SetStepName(stepName string) {
 	logger = Entry().WithField("stepName", stepName)
 }
 
+// SetStageName sets the stage name which is part of structured log output.
+func SetStageName(name string) {
+	stageName = name
+}
+
+// SetCorrelationID sets the correlation ID which is part of structured log output.
+func SetCorrelationID(id string) {
+	correlationID = id
+}
+
+var exitHandlers []func()
+var exitHandlersRegistered bool
+var exitHandlersDisabled bool
+
 // DeferExitHandler registers a logrus exit handler to allow cleanup activities.
 func DeferExitHandler(handler func()) {
-	logrus.DeferExitHandler(handler)
+	if !exitHandlersRegistered {
+		// logrus does not allow to remove exit handlers, thus a single handler runs the handlers registered here
+		logrus.DeferExitHandler(runExitHandlers)
+		exitHandlersRegistered = true
+	}
+	exitHandlers = append([]func(){handler}, exitHandlers...)
+}
+
+// DisableExitHandlers defines whether the exit handlers are skipped when the logger exits.
+// Steps executed in-process run their handlers via defer, thus running them on exit as well would run them twice.
+func DisableExitHandlers(disabled bool) {
+	exitHandlersDisabled = disabled
+}
+
+func runExitHandlers() {
+	if exitHandlersDisabled {
+		return
+	}
+	for _, handler := range exitHandlers {
+		handler()
+	}
 }
 
 // RegisterHook registers a logrus hook
@@ -112,6 +162,24 @@ func RegisterHook(hook logrus.Hook) {
 	logrus.AddHook(hook)
 }
 
+// SaveState saves the hooks, exit handlers, step and stage name of the logger and returns a function restoring them.
+// This allows to run several steps within the same process without accumulating their hooks and exit handlers.
+func SaveState() (restore func()) {
+	hooks := logrus.LevelHooks{}
+	for level, levelHooks := range logrus.StandardLogger().Hooks {
+		hooks[level] = append([]logrus.Hook{}, levelHooks...)
+	}
+	handlers := exitHandlers
+	entry := Entry()
+	stage := stageName
+	return func() {
+		logrus.StandardLogger().ReplaceHooks(hooks)
+		exitHandlers = handlers
+		logger = entry
+		stageName = stage
+	}
+}
+
 // RegisterSecret registers a value which should be masked in every log message
 func RegisterSecret(secret string) {
 	if len(secret) > 0 {
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -68,3 +69,24 @@ func TestWriteLargeBuffer(t *testing.T) {
 		assert.True(t, size != written)
 	})
 }
+
+func TestSaveState(t *testing.T) {
+	restore := SaveState()
+	hookCount := len(logrus.StandardLogger().Hooks[logrus.ErrorLevel])
+	handlerCount := len(exitHandlers)
+	entry := Entry()
+
+	func() {
+		defer SaveState()()
+		SetStepName("step")
+		RegisterHook(&CollectorHook{})
+		DeferExitHandler(func() {})
+		assert.Len(t, logrus.StandardLogger().Hooks[logrus.ErrorLevel], hookCount+1)
+		assert.Len(t, exitHandlers, handlerCount+1)
+	}()
+
+	assert.Len(t, logrus.StandardLogger().Hooks[logrus.ErrorLevel], hookCount)
+	assert.Len(t, exitHandlers, handlerCount)
+	assert.Same(t, entry, Entry())
+	restore()
+}
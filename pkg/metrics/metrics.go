@@ -0,0 +1,183 @@
+package metrics
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// MetricType defines how a metric is exposed
+type MetricType string
+
+const (
+	// Gauge is a value which can go up and down, e.g. the duration of a step.
+	// Since each step run only reports its own values, all metrics are gauges.
+	Gauge MetricType = "gauge"
+)
+
+// Metric is a single value exposed to a Sink
+type Metric struct {
+	Name   string
+	Help   string
+	Type   MetricType
+	Labels map[string]string
+	Value  float64
+}
+
+// Sink receives the metrics of a step, e.g. a Prometheus Pushgateway
+type Sink interface {
+	Send(stepName, stageName string, metrics []Metric) error
+}
+
+// InfluxValue is a field or tag of an Influx measurement written by a step
+type InfluxValue struct {
+	Measurement string
+	// ValType is either config.InfluxField or config.InfluxTag
+	ValType string
+	Name    string
+	Value   interface{}
+}
+
+// StepData contains the information about a step run which is exposed as metrics
+type StepData struct {
+	StepName      string
+	StageName     string
+	Duration      time.Duration
+	ErrorCode     string
+	ErrorCategory string
+	Measurements  []InfluxValue
+}
+
+var invalidNameCharacters = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Send exposes the metrics of a step run to all sinks, sinks which are nil are ignored.
+// Failures are only logged since they must not let the step fail.
+func Send(data StepData, sinks ...Sink) {
+	var metrics []Metric
+	for _, sink := range sinks {
+		if sink == nil {
+			continue
+		}
+		if metrics == nil {
+			metrics = data.Metrics()
+		}
+		if err := sink.Send(data.StepName, data.StageName, metrics); err != nil {
+			log.Entry().WithError(err).Warn("failed to send step metrics")
+		}
+	}
+}
+
+// Metrics returns the metrics of a step run.
+// All metrics carry the labels step and stage, measurements additionally carry the tags of their Influx measurement.
+func (s StepData) Metrics() []Metric {
+	labels := map[string]string{"step": s.StepName, "stage": s.StageName}
+	success := 1.0
+	errorCategory := "none"
+	if s.ErrorCode != "0" {
+		success = 0
+		errorCategory = s.ErrorCategory
+	}
+
+	metrics := []Metric{
+		{
+			Name:   "piper_step_duration_seconds",
+			Help:   "Duration of the step run",
+			Type:   Gauge,
+			Labels: labels,
+			Value:  s.Duration.Seconds(),
+		},
+		{
+			Name:   "piper_step_success",
+			Help:   "Result of the last step run, 1 for success and 0 for failure",
+			Type:   Gauge,
+			Labels: withLabels(labels, map[string]string{"error_category": errorCategory}),
+			Value:  success,
+		},
+	}
+	return append(metrics, s.measurementMetrics(labels)...)
+}
+
+func (s StepData) measurementMetrics(labels map[string]string) []Metric {
+	tags := map[string]map[string]string{}
+	for _, value := range s.Measurements {
+		if value.ValType != config.InfluxTag {
+			continue
+		}
+		if tags[value.Measurement] == nil {
+			tags[value.Measurement] = map[string]string{}
+		}
+		tags[value.Measurement][sanitizeName(value.Name)] = fmt.Sprint(value.Value)
+	}
+
+	var metrics []Metric
+	for _, value := range s.Measurements {
+		if value.ValType != config.InfluxField {
+			continue
+		}
+		number, ok := toFloat(value.Value)
+		if !ok {
+			// string fields cannot be represented as metric
+			continue
+		}
+		metrics = append(metrics, Metric{
+			Name:   sanitizeName(fmt.Sprintf("piper_%v_%v", value.Measurement, value.Name)),
+			Help:   fmt.Sprintf("Field %v of Influx measurement %v", value.Name, value.Measurement),
+			Type:   Gauge,
+			Labels: withLabels(labels, tags[value.Measurement]),
+			Value:  number,
+		})
+	}
+	return metrics
+}
+
+func withLabels(labels, additional map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for k, v := range additional {
+		merged[k] = v
+	}
+	return merged
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+func sanitizeName(name string) string {
+	return invalidNameCharacters.ReplaceAllString(name, "_")
+}
+
+// sortedLabelNames provides a stable order of the labels of a metric
+func sortedLabelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
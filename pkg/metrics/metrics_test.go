@@ -0,0 +1,129 @@
+//go:build unit
+// +build unit
+
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var testStepData = StepData{
+	StepName:      "checkmarxExecuteScan",
+	StageName:     "Security",
+	Duration:      90 * time.Second,
+	ErrorCode:     "1",
+	ErrorCategory: "service",
+	Measurements: []InfluxValue{
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "high_issues", Value: 3},
+		{ValType: config.InfluxField, Measurement: "checkmarx_data", Name: "owner", Value: "admin"},
+		{ValType: config.InfluxField, Measurement: "step_data", Name: "checkmarx", Value: true},
+		{ValType: config.InfluxTag, Measurement: "checkmarx_data", Name: "team", Value: "security"},
+	},
+}
+
+func TestStepMetrics(t *testing.T) {
+	t.Run("failed step", func(t *testing.T) {
+		metrics := testStepData.Metrics()
+
+		assert.Equal(t, []Metric{
+			{Name: "piper_step_duration_seconds", Help: "Duration of the step run", Type: Gauge, Labels: map[string]string{"step": "checkmarxExecuteScan", "stage": "Security"}, Value: 90},
+			{Name: "piper_step_success", Help: "Result of the last step run, 1 for success and 0 for failure", Type: Gauge, Labels: map[string]string{"step": "checkmarxExecuteScan", "stage": "Security", "error_category": "service"}, Value: 0},
+			{Name: "piper_checkmarx_data_high_issues", Help: "Field high_issues of Influx measurement checkmarx_data", Type: Gauge, Labels: map[string]string{"step": "checkmarxExecuteScan", "stage": "Security", "team": "security"}, Value: 3},
+			{Name: "piper_step_data_checkmarx", Help: "Field checkmarx of Influx measurement step_data", Type: Gauge, Labels: map[string]string{"step": "checkmarxExecuteScan", "stage": "Security"}, Value: 1},
+		}, metrics)
+	})
+
+	t.Run("successful step", func(t *testing.T) {
+		metrics := StepData{StepName: "mavenBuild", ErrorCode: "0", ErrorCategory: "undefined"}.Metrics()
+
+		assert.Len(t, metrics, 2)
+		assert.Equal(t, map[string]string{"step": "mavenBuild", "stage": "", "error_category": "none"}, metrics[1].Labels)
+		assert.Equal(t, float64(1), metrics[1].Value)
+	})
+}
+
+func TestPushgatewaySink(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		content, _ := io.ReadAll(r.Body)
+		body = string(content)
+	}))
+	defer server.Close()
+
+	Send(testStepData, NewPushgatewaySink(server.URL+"/", ""), NewOTLPSink("", ""))
+
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/metrics/job/piper/stage/Security/step/checkmarxExecuteScan", path)
+	assert.Equal(t, `# HELP piper_step_duration_seconds Duration of the step run
+# TYPE piper_step_duration_seconds gauge
+piper_step_duration_seconds{stage="Security",step="checkmarxExecuteScan"} 90
+# HELP piper_step_success Result of the last step run, 1 for success and 0 for failure
+# TYPE piper_step_success gauge
+piper_step_success{error_category="service",stage="Security",step="checkmarxExecuteScan"} 0
+# HELP piper_checkmarx_data_high_issues Field high_issues of Influx measurement checkmarx_data
+# TYPE piper_checkmarx_data_high_issues gauge
+piper_checkmarx_data_high_issues{stage="Security",step="checkmarxExecuteScan",team="security"} 3
+# HELP piper_step_data_checkmarx Field checkmarx of Influx measurement step_data
+# TYPE piper_step_data_checkmarx gauge
+piper_step_data_checkmarx{stage="Security",step="checkmarxExecuteScan"} 1
+`, body)
+}
+
+func TestGroupingKey(t *testing.T) {
+	assert.Equal(t, "stage/Security", groupingKey("stage", "Security"))
+	assert.Equal(t, "stage@base64/=", groupingKey("stage", ""))
+	assert.Equal(t, "stage@base64/QnVpbGQvVGVzdA", groupingKey("stage", "Build/Test"))
+}
+
+func TestFormatTextExposition(t *testing.T) {
+	text := formatTextExposition([]Metric{{Name: "m", Help: "h", Type: Gauge, Labels: map[string]string{"l": "a \"quoted\"\\value\n"}, Value: 0.5}})
+	assert.Equal(t, "# HELP m h\n# TYPE m gauge\nm{l=\"a \\\"quoted\\\"\\\\value\\n\"} 0.5\n", text)
+}
+
+func TestOTLPSink(t *testing.T) {
+	var header http.Header
+	var request otlpMetricsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+	}))
+	defer server.Close()
+
+	sink := NewOTLPSink(server.URL+"/v1/metrics", "Bearer token").(*OTLPSink)
+	sink.now = func() time.Time { return time.Unix(1677665730, 0) }
+	err := sink.Send("mavenBuild", "Build", []Metric{
+		{Name: "piper_step_duration_seconds", Help: "Duration", Type: Gauge, Labels: map[string]string{"step": "mavenBuild"}, Value: 12.5},
+		{Name: "piper_step_success", Help: "Success", Type: Gauge, Labels: map[string]string{"step": "mavenBuild"}, Value: 1},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	if assert.Len(t, request.ResourceMetrics, 1) && assert.Len(t, request.ResourceMetrics[0].ScopeMetrics, 1) {
+		metrics := request.ResourceMetrics[0].ScopeMetrics[0].Metrics
+		dataPoint := otlpDataPoint{Attributes: []otlpKeyValue{stringAttribute("step", "mavenBuild")}, TimeUnixNano: "1677665730000000000", AsDouble: 12.5}
+		assert.Equal(t, otlpMetric{Name: "piper_step_duration_seconds", Description: "Duration", Gauge: &otlpGauge{DataPoints: []otlpDataPoint{dataPoint}}}, metrics[0])
+		dataPoint.AsDouble = 1
+		assert.Equal(t, otlpMetric{Name: "piper_step_success", Description: "Success", Gauge: &otlpGauge{DataPoints: []otlpDataPoint{dataPoint}}}, metrics[1])
+	}
+}
+
+func TestSendFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := NewPushgatewaySink(server.URL, "piper").Send("mavenBuild", "Build", nil)
+
+	assert.Contains(t, err.Error(), "failed to push metrics to '"+server.URL+"/metrics/job/piper/stage/Build/step/mavenBuild'")
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// OTLPSink sends the metrics of a step to an OpenTelemetry receiver using OTLP/HTTP with JSON encoding
+type OTLPSink struct {
+	client   piperhttp.Sender
+	endpoint string
+	now      func() time.Time
+}
+
+type otlpKeyValue struct {
+	Key   string            `json:"key"`
+	Value map[string]string `json:"value"`
+}
+
+type otlpDataPoint struct {
+	Attributes   []otlpKeyValue `json:"attributes"`
+	TimeUnixNano string         `json:"timeUnixNano"`
+	AsDouble     float64        `json:"asDouble"`
+}
+
+type otlpGauge struct {
+	DataPoints []otlpDataPoint `json:"dataPoints"`
+}
+
+type otlpMetric struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Gauge       *otlpGauge `json:"gauge,omitempty"`
+}
+
+type otlpScopeMetrics struct {
+	Scope   map[string]string `json:"scope"`
+	Metrics []otlpMetric      `json:"metrics"`
+}
+
+type otlpResourceMetrics struct {
+	Resource     map[string][]otlpKeyValue `json:"resource"`
+	ScopeMetrics []otlpScopeMetrics        `json:"scopeMetrics"`
+}
+
+type otlpMetricsRequest struct {
+	ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+}
+
+// NewOTLPSink creates a sink for the OTLP/HTTP metrics endpoint, e.g. https://otel-collector.example.com:4318/v1/metrics.
+// It returns nil in case no endpoint is provided.
+func NewOTLPSink(endpoint, token string) Sink {
+	if len(endpoint) == 0 {
+		return nil
+	}
+	if len(token) > 0 {
+		log.RegisterSecret(token)
+	}
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRequestDuration: 10 * time.Second, MaxRetries: 1, Token: token})
+	return &OTLPSink{client: client, endpoint: endpoint, now: time.Now}
+}
+
+// Send sends the metrics, step and stage are contained in the attributes of the metrics
+func (o *OTLPSink) Send(stepName, stageName string, metrics []Metric) error {
+	payload, err := json.Marshal(o.request(metrics))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal metrics")
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	response, err := o.client.SendRequest(http.MethodPost, o.endpoint, bytes.NewBuffer(payload), header, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send metrics to '%v'", o.endpoint)
+	}
+	response.Body.Close()
+	return nil
+}
+
+func (o *OTLPSink) request(metrics []Metric) otlpMetricsRequest {
+	timestamp := strconv.FormatInt(o.now().UnixNano(), 10)
+	scopeMetrics := otlpScopeMetrics{Scope: map[string]string{"name": "github.com/SAP/jenkins-library"}}
+	for _, metric := range metrics {
+		dataPoint := otlpDataPoint{TimeUnixNano: timestamp, AsDouble: metric.Value, Attributes: []otlpKeyValue{}}
+		for _, name := range sortedLabelNames(metric.Labels) {
+			dataPoint.Attributes = append(dataPoint.Attributes, stringAttribute(name, metric.Labels[name]))
+		}
+		scopeMetrics.Metrics = append(scopeMetrics.Metrics, otlpMetric{Name: metric.Name, Description: metric.Help, Gauge: &otlpGauge{DataPoints: []otlpDataPoint{dataPoint}}})
+	}
+	return otlpMetricsRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     map[string][]otlpKeyValue{"attributes": {stringAttribute("service.name", "piper")}},
+		ScopeMetrics: []otlpScopeMetrics{scopeMetrics},
+	}}}
+}
+
+func stringAttribute(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: map[string]string{"stringValue": value}}
+}
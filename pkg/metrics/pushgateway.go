@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/pkg/errors"
+)
+
+// PushgatewaySink pushes the metrics of a step to a Prometheus Pushgateway
+type PushgatewaySink struct {
+	client piperhttp.Sender
+	url    string
+	job    string
+}
+
+// NewPushgatewaySink creates a sink for the Pushgateway at pushgatewayURL, e.g. https://pushgateway.example.com:9091.
+// It returns nil in case no URL is provided.
+func NewPushgatewaySink(pushgatewayURL, job string) Sink {
+	if len(pushgatewayURL) == 0 {
+		return nil
+	}
+	if len(job) == 0 {
+		job = "piper"
+	}
+	client := &piperhttp.Client{}
+	client.SetOptions(piperhttp.ClientOptions{MaxRequestDuration: 10 * time.Second, MaxRetries: 1})
+	return &PushgatewaySink{client: client, url: strings.TrimSuffix(pushgatewayURL, "/"), job: job}
+}
+
+// Send replaces the metrics of the step in the group job/<job>/stage/<stageName>/step/<stepName>,
+// thus the metrics of other steps of the same job and of the same step in other stages are kept.
+func (p *PushgatewaySink) Send(stepName, stageName string, metrics []Metric) error {
+	groupURL := fmt.Sprintf("%v/metrics/%v/%v/%v", p.url, groupingKey("job", p.job), groupingKey("stage", stageName), groupingKey("step", stepName))
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain; version=0.0.4")
+	response, err := p.client.SendRequest(http.MethodPut, groupURL, bytes.NewBufferString(formatTextExposition(metrics)), header, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push metrics to '%v'", groupURL)
+	}
+	response.Body.Close()
+	return nil
+}
+
+// groupingKey returns a label of the grouping key as path segments.
+// Empty values and values containing a slash have to be base64 encoded, e.g. stage names like "Build/Test".
+func groupingKey(name, value string) string {
+	if len(value) == 0 {
+		return name + "@base64/="
+	}
+	if strings.Contains(value, "/") {
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return name + "/" + url.PathEscape(value)
+}
+
+// formatTextExposition formats the metrics in the Prometheus text exposition format
+func formatTextExposition(metrics []Metric) string {
+	var builder strings.Builder
+	described := map[string]bool{}
+	for _, metric := range metrics {
+		if !described[metric.Name] {
+			fmt.Fprintf(&builder, "# HELP %v %v\n", metric.Name, metric.Help)
+			fmt.Fprintf(&builder, "# TYPE %v %v\n", metric.Name, metric.Type)
+			described[metric.Name] = true
+		}
+		labels := []string{}
+		for _, name := range sortedLabelNames(metric.Labels) {
+			labels = append(labels, fmt.Sprintf(`%v="%v"`, name, escapeLabelValue(metric.Labels[name])))
+		}
+		fmt.Fprintf(&builder, "%v{%v} %v\n", metric.Name, strings.Join(labels, ","), metric.Value)
+	}
+	return builder.String()
+}
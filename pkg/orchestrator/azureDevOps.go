@@ -1,6 +1,7 @@
 package orchestrator
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strconv"
@@ -254,6 +255,20 @@ func (a *AzureDevOpsConfigProvider) IsPullRequest() bool {
 	return getEnv("BUILD_REASON", "n/a") == "PullRequest"
 }
 
+// SetVariable sets an output variable via logging command, other jobs and stages can access it via dependencies
+func (a *AzureDevOpsConfigProvider) SetVariable(name, value string) error {
+	// https://learn.microsoft.com/en-us/azure/devops/pipelines/scripts/logging-commands#setvariable-initialize-or-modify-the-value-of-a-variable
+	escapedName := strings.NewReplacer("%", "%AZP25", ";", "%3B", "]", "%5D").Replace(name)
+	escapedValue := strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A").Replace(value)
+	_, err := fmt.Fprintf(variableOutput, "##vso[task.setvariable variable=%v;isOutput=true]%v\n", escapedName, escapedValue)
+	return err
+}
+
+// GetVariable returns the value of a pipeline variable, Azure DevOps passes variables as environment variables in upper case with '.' replaced by '_'
+func (a *AzureDevOpsConfigProvider) GetVariable(name string) (string, bool) {
+	return os.LookupEnv(strings.ToUpper(strings.NewReplacer(".", "_", " ", "_").Replace(name)))
+}
+
 func isAzure() bool {
 	envVars := []string{"AZURE_HTTP_USER_AGENT"}
 	return areIndicatingEnvVarsSet(envVars)
@@ -0,0 +1,200 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/ghodss/yaml"
+)
+
+// GenericMappingEnv is the environment variable pointing to the mapping file of the generic orchestrator provider
+const GenericMappingEnv = "PIPER_ORCHESTRATOR_MAPPING"
+
+// EnvMapping defines from which environment variables the information about the current pipeline run is read.
+// Values reference environment variables as ${VAR} and may combine several variables with constant text,
+// e.g. https://ci.example.com/${PROJECT}/builds/${BUILD_NUMBER}. A value is "n/a" in case one of the referenced variables is empty.
+type EnvMapping struct {
+	OrchestratorType    string `json:"orchestratorType,omitempty"`
+	OrchestratorVersion string `json:"orchestratorVersion,omitempty"`
+	StageName           string `json:"stageName,omitempty"`
+	Branch              string `json:"branch,omitempty"`
+	Reference           string `json:"reference,omitempty"`
+	BuildURL            string `json:"buildURL,omitempty"`
+	BuildID             string `json:"buildID,omitempty"`
+	JobURL              string `json:"jobURL,omitempty"`
+	JobName             string `json:"jobName,omitempty"`
+	Commit              string `json:"commit,omitempty"`
+	RepoURL             string `json:"repoURL,omitempty"`
+	PullRequestKey      string `json:"pullRequestKey,omitempty"`
+	PullRequestBranch   string `json:"pullRequestBranch,omitempty"`
+	PullRequestBase     string `json:"pullRequestBase,omitempty"`
+	BuildReason         string `json:"buildReason,omitempty"`
+	BuildStatus         string `json:"buildStatus,omitempty"`
+	// BuildStatusValues maps the values of BuildStatus to the values aligned with Jenkins, e.g. SUCCESS, FAILURE, ABORTED
+	BuildStatusValues map[string]string `json:"buildStatusValues,omitempty"`
+	// PipelineStartTime needs to be in RFC3339 format
+	PipelineStartTime string `json:"pipelineStartTime,omitempty"`
+}
+
+// GenericConfigProvider provides the information about the current pipeline run based on an EnvMapping
+type GenericConfigProvider struct {
+	mapping EnvMapping
+}
+
+func newGenericConfigProvider(mappingFile string) (*GenericConfigProvider, error) {
+	content, err := os.ReadFile(mappingFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read orchestrator mapping file '%v': %w", mappingFile, err)
+	}
+	provider := GenericConfigProvider{}
+	if err := yaml.Unmarshal(content, &provider.mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse orchestrator mapping file '%v': %w", mappingFile, err)
+	}
+	return &provider, nil
+}
+
+// resolve returns the value of a mapping entry or "n/a" in case it cannot be resolved
+func (g *GenericConfigProvider) resolve(template string) string {
+	if len(template) == 0 {
+		return "n/a"
+	}
+	complete := true
+	value := os.Expand(template, func(key string) string {
+		value := os.Getenv(key)
+		if len(value) == 0 {
+			complete = false
+		}
+		return value
+	})
+	if !complete {
+		log.Entry().Debugf("Could not resolve '%v', using fallback value n/a", template)
+		return "n/a"
+	}
+	return value
+}
+
+// InitOrchestratorProvider does nothing since no API is used
+func (g *GenericConfigProvider) InitOrchestratorProvider(settings *OrchestratorSettings) {
+	log.Entry().Debugf("Successfully initialized %v config provider", g.OrchestratorType())
+}
+
+// OrchestratorType returns the orchestrator name defined by the mapping, Generic by default
+func (g *GenericConfigProvider) OrchestratorType() string {
+	if len(g.mapping.OrchestratorType) == 0 {
+		return "Generic"
+	}
+	return g.mapping.OrchestratorType
+}
+
+func (g *GenericConfigProvider) OrchestratorVersion() string {
+	return g.resolve(g.mapping.OrchestratorVersion)
+}
+
+func (g *GenericConfigProvider) GetStageName() string {
+	return g.resolve(g.mapping.StageName)
+}
+
+// GetBranch returns the source branch name, e.g. main
+func (g *GenericConfigProvider) GetBranch() string {
+	return strings.TrimPrefix(g.resolve(g.mapping.Branch), "refs/heads/")
+}
+
+// GetReference returns the git reference, derived from the branch if not mapped
+func (g *GenericConfigProvider) GetReference() string {
+	if len(g.mapping.Reference) > 0 {
+		return g.resolve(g.mapping.Reference)
+	}
+	if branch := g.GetBranch(); branch != "n/a" {
+		return "refs/heads/" + branch
+	}
+	return "n/a"
+}
+
+func (g *GenericConfigProvider) GetBuildURL() string {
+	return g.resolve(g.mapping.BuildURL)
+}
+
+func (g *GenericConfigProvider) GetBuildID() string {
+	return g.resolve(g.mapping.BuildID)
+}
+
+func (g *GenericConfigProvider) GetJobURL() string {
+	return g.resolve(g.mapping.JobURL)
+}
+
+func (g *GenericConfigProvider) GetJobName() string {
+	return g.resolve(g.mapping.JobName)
+}
+
+func (g *GenericConfigProvider) GetCommit() string {
+	return g.resolve(g.mapping.Commit)
+}
+
+func (g *GenericConfigProvider) GetRepoURL() string {
+	return g.resolve(g.mapping.RepoURL)
+}
+
+func (g *GenericConfigProvider) GetPullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: g.resolve(g.mapping.PullRequestBranch),
+		Base:   g.resolve(g.mapping.PullRequestBase),
+		Key:    g.resolve(g.mapping.PullRequestKey),
+	}
+}
+
+// IsPullRequest indicates whether the current build is a PR, i.e. whether the pull request key is available
+func (g *GenericConfigProvider) IsPullRequest() bool {
+	return g.resolve(g.mapping.PullRequestKey) != "n/a"
+}
+
+// GetLog is not supported since the logs are only available via orchestrator specific APIs
+func (g *GenericConfigProvider) GetLog() ([]byte, error) {
+	log.Entry().Infof("GetLog() for %v not supported.", g.OrchestratorType())
+	return []byte{}, nil
+}
+
+// GetChangeSet is not supported since the change set is only available via orchestrator specific APIs
+func (g *GenericConfigProvider) GetChangeSet() []ChangeSet {
+	log.Entry().Infof("GetChangeSet() for %v not supported.", g.OrchestratorType())
+	return []ChangeSet{}
+}
+
+// GetPipelineStartTime returns the pipeline start time in UTC
+func (g *GenericConfigProvider) GetPipelineStartTime() time.Time {
+	startTime := g.resolve(g.mapping.PipelineStartTime)
+	parsed, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		log.Entry().Debugf("could not parse timestamp '%v', %v", startTime, err)
+		return time.Time{}.UTC()
+	}
+	return parsed.UTC()
+}
+
+// GetBuildStatus returns status of the build. Return variables are aligned with Jenkins build statuses.
+func (g *GenericConfigProvider) GetBuildStatus() string {
+	status := g.resolve(g.mapping.BuildStatus)
+	if mapped, ok := g.mapping.BuildStatusValues[status]; ok {
+		return mapped
+	}
+	switch status {
+	case "SUCCESS", "ABORTED", "NOT_BUILT":
+		return status
+	default:
+		return "FAILURE"
+	}
+}
+
+// GetBuildReason returns the build reason, for pull requests PullRequest in case no build reason is mapped
+func (g *GenericConfigProvider) GetBuildReason() string {
+	if len(g.mapping.BuildReason) == 0 && g.IsPullRequest() {
+		return "PullRequest"
+	}
+	return g.resolve(g.mapping.BuildReason)
+}
+
+func isGeneric() bool {
+	return len(os.Getenv(GenericMappingEnv)) > 0
+}
@@ -0,0 +1,106 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bitbucketMapping = `
+orchestratorType: BitbucketPipelines
+branch: ${BITBUCKET_BRANCH}
+commit: ${BITBUCKET_COMMIT}
+repoURL: ${BITBUCKET_GIT_HTTP_ORIGIN}
+buildID: ${BITBUCKET_BUILD_NUMBER}
+buildURL: ${BITBUCKET_GIT_HTTP_ORIGIN}/pipelines/results/${BITBUCKET_BUILD_NUMBER}
+jobName: ${BITBUCKET_REPO_FULL_NAME}
+stageName: ${BITBUCKET_STEP_NAME}
+pullRequestKey: ${BITBUCKET_PR_ID}
+pullRequestBranch: ${BITBUCKET_BRANCH}
+pullRequestBase: ${BITBUCKET_PR_DESTINATION_BRANCH}
+buildStatus: ${BITBUCKET_EXIT_CODE}
+buildStatusValues:
+  "0": SUCCESS
+pipelineStartTime: ${PIPELINE_START}
+`
+
+func TestGenericConfigProvider(t *testing.T) {
+	mappingFile := filepath.Join(t.TempDir(), "mapping.yml")
+	assert.NoError(t, os.WriteFile(mappingFile, []byte(bitbucketMapping), 0644))
+
+	t.Run("Branch build", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_ORCHESTRATOR_MAPPING", mappingFile)
+		os.Setenv("BITBUCKET_BRANCH", "feat/test-bitbucket")
+		os.Setenv("BITBUCKET_COMMIT", "abcdef42713")
+		os.Setenv("BITBUCKET_GIT_HTTP_ORIGIN", "https://bitbucket.org/foo/bar")
+		os.Setenv("BITBUCKET_BUILD_NUMBER", "42")
+		os.Setenv("BITBUCKET_REPO_FULL_NAME", "foo/bar")
+		os.Setenv("BITBUCKET_EXIT_CODE", "0")
+		os.Setenv("PIPELINE_START", "2023-03-01T10:15:30+01:00")
+
+		p, err := NewOrchestratorSpecificConfigProvider()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Generic", DetectOrchestrator().String())
+		assert.Equal(t, "BitbucketPipelines", p.OrchestratorType())
+		assert.Equal(t, "n/a", p.OrchestratorVersion())
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-bitbucket", p.GetBranch())
+		assert.Equal(t, "refs/heads/feat/test-bitbucket", p.GetReference())
+		assert.Equal(t, "abcdef42713", p.GetCommit())
+		assert.Equal(t, "42", p.GetBuildID())
+		assert.Equal(t, "https://bitbucket.org/foo/bar/pipelines/results/42", p.GetBuildURL())
+		assert.Equal(t, "n/a", p.GetJobURL())
+		assert.Equal(t, "foo/bar", p.GetJobName())
+		assert.Equal(t, "https://bitbucket.org/foo/bar", p.GetRepoURL())
+		assert.Equal(t, "n/a", p.GetStageName())
+		assert.Equal(t, "n/a", p.GetBuildReason())
+		assert.Equal(t, "SUCCESS", p.GetBuildStatus())
+		assert.Equal(t, time.Date(2023, time.March, 1, 9, 15, 30, 0, time.UTC), p.GetPipelineStartTime())
+	})
+
+	t.Run("Pull request", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("BITBUCKET_BRANCH", "feat/test-bitbucket")
+		os.Setenv("BITBUCKET_PR_ID", "7")
+		os.Setenv("BITBUCKET_PR_DESTINATION_BRANCH", "main")
+		os.Setenv("BITBUCKET_EXIT_CODE", "1")
+
+		p, err := newGenericConfigProvider(mappingFile)
+		assert.NoError(t, err)
+		c := p.GetPullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "PullRequest", p.GetBuildReason())
+		assert.Equal(t, "FAILURE", p.GetBuildStatus())
+		assert.Equal(t, "feat/test-bitbucket", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "7", c.Key)
+	})
+
+	t.Run("Mapping file not found", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PIPER_ORCHESTRATOR_MAPPING", filepath.Join(t.TempDir(), "missing.yml"))
+
+		p, err := NewOrchestratorSpecificConfigProvider()
+
+		assert.Contains(t, err.Error(), "failed to read orchestrator mapping file")
+		assert.Equal(t, "Unknown", p.OrchestratorType())
+	})
+
+	t.Run("Default type", func(t *testing.T) {
+		p := GenericConfigProvider{}
+		assert.Equal(t, "Generic", p.OrchestratorType())
+		assert.Equal(t, "n/a", p.GetReference())
+	})
+}
@@ -3,9 +3,12 @@ package orchestrator
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -186,6 +189,34 @@ func (g *GitHubActionsConfigProvider) IsPullRequest() bool {
 	return truthy("GITHUB_HEAD_REF")
 }
 
+// SetVariable sets an output of the current step by appending it to the file referenced by GITHUB_OUTPUT
+func (g *GitHubActionsConfigProvider) SetVariable(name, value string) error {
+	outputFile := os.Getenv("GITHUB_OUTPUT")
+	if len(outputFile) == 0 {
+		return fmt.Errorf("failed to set output '%v': GITHUB_OUTPUT is not set", name)
+	}
+	// a random delimiter allows multi-line values, https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#multiline-strings
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return fmt.Errorf("failed to set output '%v': %w", name, err)
+	}
+	delimiter := "ghadelimiter_" + hex.EncodeToString(random)
+	file, err := os.OpenFile(outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open GITHUB_OUTPUT file '%v': %w", outputFile, err)
+	}
+	defer file.Close()
+	if _, err := fmt.Fprintf(file, "%v<<%v\n%v\n%v\n", name, delimiter, value, delimiter); err != nil {
+		return fmt.Errorf("failed to set output '%v': %w", name, err)
+	}
+	return nil
+}
+
+// GetVariable returns the value of a variable which is passed to the step as environment variable, e.g. the output of a previous job
+func (g *GitHubActionsConfigProvider) GetVariable(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
 func isGitHubActions() bool {
 	envVars := []string{"GITHUB_ACTION", "GITHUB_ACTIONS"}
 	return areIndicatingEnvVarsSet(envVars)
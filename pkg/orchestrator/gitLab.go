@@ -0,0 +1,291 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+type GitLabConfigProvider struct {
+	client piperHttp.Client
+	header http.Header
+}
+
+type gitLabJob struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+type gitLabCommit struct {
+	ID            string `json:"id"`
+	CommittedDate string `json:"committed_date"`
+}
+
+type gitLabCompare struct {
+	Commits []gitLabCommit `json:"commits"`
+}
+
+// InitOrchestratorProvider initializes http client for GitLabConfigProvider
+// Without an access token the job token of the current job is used which is not permitted to access all APIs.
+func (g *GitLabConfigProvider) InitOrchestratorProvider(settings *OrchestratorSettings) {
+	g.client = piperHttp.Client{}
+	options := piperHttp.ClientOptions{
+		MaxRetries:       3,
+		TransportTimeout: time.Second * 10,
+	}
+	g.header = http.Header{}
+	if len(settings.GitLabToken) > 0 {
+		options.Token = "Bearer " + settings.GitLabToken
+	} else {
+		g.header.Set("JOB-TOKEN", getEnv("CI_JOB_TOKEN", ""))
+	}
+	g.client.SetOptions(options)
+	log.Entry().Debug("Successfully initialized GitLab config provider")
+}
+
+// getProjectAPIURL returns the API URL of the current project e.g. https://gitlab.com/api/v4/projects/42
+func (g *GitLabConfigProvider) getProjectAPIURL() string {
+	return fmt.Sprintf("%s/projects/%s", getEnv("CI_API_V4_URL", "n/a"), getEnv("CI_PROJECT_ID", "n/a"))
+}
+
+// OrchestratorVersion returns the version of the GitLab instance
+func (g *GitLabConfigProvider) OrchestratorVersion() string {
+	return getEnv("CI_SERVER_VERSION", "n/a")
+}
+
+// OrchestratorType returns the orchestrator name e.g. Azure/GitHubActions/Jenkins/GitLab
+func (g *GitLabConfigProvider) OrchestratorType() string {
+	return "GitLab"
+}
+
+// GetBuildStatus returns status of the build. Return variables are aligned with Jenkins build statuses.
+func (g *GitLabConfigProvider) GetBuildStatus() string {
+	// CI_JOB_STATUS is only available in after_script, values: success, failed, canceled
+	switch getEnv("CI_JOB_STATUS", "failed") {
+	case "success":
+		return "SUCCESS"
+	case "canceled":
+		return "ABORTED"
+	default:
+		return "FAILURE"
+	}
+}
+
+// GetLog returns the logs of all finished jobs of the current pipeline
+func (g *GitLabConfigProvider) GetLog() ([]byte, error) {
+	jobs, err := g.getPipelineJobs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+
+	var logs []byte
+	currentJob := getEnv("CI_JOB_ID", "")
+	for _, job := range jobs {
+		// the log of the current job and of jobs which did not run yet is not complete
+		if strconv.Itoa(job.ID) == currentJob || job.Status == "created" || job.Status == "pending" || job.Status == "running" || job.Status == "skipped" || job.Status == "manual" {
+			continue
+		}
+		response, err := g.client.GetRequest(fmt.Sprintf("%s/jobs/%d/trace", g.getProjectAPIURL(), job.ID), g.header, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get log of job %d: %w", job.ID, err)
+		}
+		content, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log of job %d: %w", job.ID, err)
+		}
+		logs = append(logs, content...)
+	}
+	return logs, nil
+}
+
+// getPipelineJobs returns the jobs of the current pipeline, the pages of the response are followed via the X-Next-Page header
+func (g *GitLabConfigProvider) getPipelineJobs() ([]gitLabJob, error) {
+	var jobs []gitLabJob
+	for page := "1"; len(page) > 0; {
+		response, err := g.client.GetRequest(fmt.Sprintf("%s/pipelines/%s/jobs?per_page=100&page=%s", g.getProjectAPIURL(), getEnv("CI_PIPELINE_ID", "n/a"), page), g.header, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jobs of pipeline: %w", err)
+		}
+		page = response.Header.Get("X-Next-Page")
+		var pageJobs []gitLabJob
+		if err := piperHttp.ParseHTTPResponseBodyJSON(response, &pageJobs); err != nil {
+			return nil, fmt.Errorf("failed to parse jobs of pipeline: %w", err)
+		}
+		jobs = append(jobs, pageJobs...)
+	}
+	return jobs, nil
+}
+
+// GetChangeSet returns the commits of the merge request or the commits pushed with the current pipeline
+func (g *GitLabConfigProvider) GetChangeSet() []ChangeSet {
+	var commits []gitLabCommit
+	prNumber := 0
+	if g.IsPullRequest() {
+		prNumber, _ = strconv.Atoi(getEnv("CI_MERGE_REQUEST_IID", ""))
+		response, err := g.client.GetRequest(fmt.Sprintf("%s/merge_requests/%d/commits", g.getProjectAPIURL(), prNumber), g.header, nil)
+		if err == nil {
+			err = piperHttp.ParseHTTPResponseBodyJSON(response, &commits)
+		}
+		if err != nil {
+			log.Entry().WithError(err).Warn("could not get commits of merge request")
+			return []ChangeSet{}
+		}
+	} else {
+		before := getEnv("CI_COMMIT_BEFORE_SHA", "")
+		if len(strings.Trim(before, "0")) == 0 {
+			// first push of a branch, CI_COMMIT_BEFORE_SHA is 0000000000000000000000000000000000000000
+			var commit gitLabCommit
+			response, err := g.client.GetRequest(fmt.Sprintf("%s/repository/commits/%s", g.getProjectAPIURL(), g.GetCommit()), g.header, nil)
+			if err == nil {
+				err = piperHttp.ParseHTTPResponseBodyJSON(response, &commit)
+			}
+			if err != nil {
+				log.Entry().WithError(err).Warn("could not get commit of pipeline")
+				return []ChangeSet{}
+			}
+			commits = []gitLabCommit{commit}
+		} else {
+			var compare gitLabCompare
+			response, err := g.client.GetRequest(fmt.Sprintf("%s/repository/compare?from=%s&to=%s", g.getProjectAPIURL(), before, g.GetCommit()), g.header, nil)
+			if err == nil {
+				err = piperHttp.ParseHTTPResponseBodyJSON(response, &compare)
+			}
+			if err != nil {
+				log.Entry().WithError(err).Warn("could not get commits of pipeline")
+				return []ChangeSet{}
+			}
+			commits = compare.Commits
+		}
+	}
+
+	changeSetList := []ChangeSet{}
+	for _, commit := range commits {
+		timestamp := commit.CommittedDate
+		// align with the timestamp in milliseconds provided by Jenkins
+		if parsed, err := time.Parse(time.RFC3339, commit.CommittedDate); err == nil {
+			timestamp = strconv.FormatInt(parsed.UnixMilli(), 10)
+		}
+		changeSetList = append(changeSetList, ChangeSet{CommitId: commit.ID, Timestamp: timestamp, PrNumber: prNumber})
+	}
+	return changeSetList
+}
+
+// GetPipelineStartTime returns the pipeline start time in UTC
+func (g *GitLabConfigProvider) GetPipelineStartTime() time.Time {
+	created := getEnv("CI_PIPELINE_CREATED_AT", "")
+	parsed, err := time.Parse(time.RFC3339, created)
+	if err != nil {
+		log.Entry().Errorf("could not parse timestamp '%v', %v", created, err)
+		return time.Time{}.UTC()
+	}
+	return parsed.UTC()
+}
+
+// GetBuildID returns the ID of the pipeline e.g. 1234
+func (g *GitLabConfigProvider) GetBuildID() string {
+	return getEnv("CI_PIPELINE_ID", "n/a")
+}
+
+// GetStageName returns the name of the stage of the current job, e.g. build
+func (g *GitLabConfigProvider) GetStageName() string {
+	return getEnv("CI_JOB_STAGE", "n/a")
+}
+
+// GetBuildReason returns the build reason unified with AzureDevOps build reasons
+func (g *GitLabConfigProvider) GetBuildReason() string {
+	// https://docs.gitlab.com/ee/ci/jobs/job_control.html#common-if-clauses-for-rules
+	switch getEnv("CI_PIPELINE_SOURCE", "n/a") {
+	case "push":
+		return "IndividualCI"
+	case "merge_request_event", "external_pull_request_event":
+		return "PullRequest"
+	case "schedule":
+		return "Schedule"
+	case "web", "api", "chat":
+		return "Manual"
+	case "trigger", "pipeline", "parent_pipeline":
+		return "ResourceTrigger"
+	default:
+		return "Unknown"
+	}
+}
+
+// GetBranch returns the source branch name, e.g. main
+func (g *GitLabConfigProvider) GetBranch() string {
+	if branch, ok := lookupEnvNotEmpty("CI_COMMIT_BRANCH"); ok {
+		return branch
+	}
+	if branch, ok := lookupEnvNotEmpty("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"); ok {
+		return branch
+	}
+	return getEnv("CI_COMMIT_REF_NAME", "n/a")
+}
+
+// GetReference returns the git reference, e.g. refs/heads/main
+func (g *GitLabConfigProvider) GetReference() string {
+	if tag, ok := lookupEnvNotEmpty("CI_COMMIT_TAG"); ok {
+		return "refs/tags/" + tag
+	}
+	if g.IsPullRequest() {
+		return "refs/merge-requests/" + getEnv("CI_MERGE_REQUEST_IID", "n/a") + "/head"
+	}
+	return "refs/heads/" + g.GetBranch()
+}
+
+// GetBuildURL returns the URL of the pipeline e.g. https://gitlab.com/foo/bar/-/pipelines/1234
+func (g *GitLabConfigProvider) GetBuildURL() string {
+	return getEnv("CI_PIPELINE_URL", "n/a")
+}
+
+// GetJobURL returns the URL of the current job e.g. https://gitlab.com/foo/bar/-/jobs/5678
+func (g *GitLabConfigProvider) GetJobURL() string {
+	return getEnv("CI_JOB_URL", "n/a")
+}
+
+// GetJobName returns the path of the project e.g. foo/bar
+func (g *GitLabConfigProvider) GetJobName() string {
+	return getEnv("CI_PROJECT_PATH", "n/a")
+}
+
+// GetCommit returns commit SHA of current build
+func (g *GitLabConfigProvider) GetCommit() string {
+	return getEnv("CI_COMMIT_SHA", "n/a")
+}
+
+// GetRepoURL returns current repo URL e.g. https://gitlab.com/foo/bar
+func (g *GitLabConfigProvider) GetRepoURL() string {
+	return getEnv("CI_PROJECT_URL", "n/a")
+}
+
+// GetPullRequestConfig returns the configuration of the merge request
+func (g *GitLabConfigProvider) GetPullRequestConfig() PullRequestConfig {
+	return PullRequestConfig{
+		Branch: getEnv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "n/a"),
+		Base:   getEnv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME", "n/a"),
+		Key:    getEnv("CI_MERGE_REQUEST_IID", "n/a"),
+	}
+}
+
+// IsPullRequest indicates whether the current pipeline runs for a merge request
+func (g *GitLabConfigProvider) IsPullRequest() bool {
+	return truthy("CI_MERGE_REQUEST_IID")
+}
+
+func isGitLab() bool {
+	envVars := []string{"GITLAB_CI"}
+	return areIndicatingEnvVarsSet(envVars)
+}
+
+func lookupEnvNotEmpty(key string) (string, bool) {
+	value := getEnv(key, "")
+	return value, len(value) > 0
+}
@@ -0,0 +1,242 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func gitLabTestProvider() *GitLabConfigProvider {
+	g := GitLabConfigProvider{header: http.Header{}}
+	g.client.SetOptions(piperHttp.ClientOptions{
+		MaxRequestDuration:  5 * time.Second,
+		Token:               "Bearer TOKEN",
+		UseDefaultTransport: true, // need to use default transport for http mock
+		MaxRetries:          -1,
+	})
+	return &g
+}
+
+func TestGitLab(t *testing.T) {
+	t.Run("BranchBuild", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("GITLAB_CI", "true")
+		os.Setenv("CI_COMMIT_BRANCH", "feat/test-gitlab")
+		os.Setenv("CI_COMMIT_REF_NAME", "feat/test-gitlab")
+		os.Setenv("CI_COMMIT_SHA", "abcdef42713")
+		os.Setenv("CI_PIPELINE_ID", "1234")
+		os.Setenv("CI_PIPELINE_URL", "https://gitlab.com/foo/bar/-/pipelines/1234")
+		os.Setenv("CI_JOB_URL", "https://gitlab.com/foo/bar/-/jobs/5678")
+		os.Setenv("CI_PROJECT_URL", "https://gitlab.com/foo/bar")
+		os.Setenv("CI_PROJECT_PATH", "foo/bar")
+		os.Setenv("CI_JOB_STAGE", "build")
+		os.Setenv("CI_PIPELINE_SOURCE", "push")
+		os.Setenv("CI_PIPELINE_CREATED_AT", "2023-03-01T10:15:30Z")
+		os.Setenv("CI_SERVER_VERSION", "15.9.0")
+
+		p, err := NewOrchestratorSpecificConfigProvider()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "GitLab", DetectOrchestrator().String())
+		assert.Equal(t, "GitLab", p.OrchestratorType())
+		assert.Equal(t, "15.9.0", p.OrchestratorVersion())
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-gitlab", p.GetBranch())
+		assert.Equal(t, "refs/heads/feat/test-gitlab", p.GetReference())
+		assert.Equal(t, "abcdef42713", p.GetCommit())
+		assert.Equal(t, "1234", p.GetBuildID())
+		assert.Equal(t, "https://gitlab.com/foo/bar/-/pipelines/1234", p.GetBuildURL())
+		assert.Equal(t, "https://gitlab.com/foo/bar/-/jobs/5678", p.GetJobURL())
+		assert.Equal(t, "foo/bar", p.GetJobName())
+		assert.Equal(t, "https://gitlab.com/foo/bar", p.GetRepoURL())
+		assert.Equal(t, "build", p.GetStageName())
+		assert.Equal(t, "IndividualCI", p.GetBuildReason())
+		assert.Equal(t, time.Date(2023, time.March, 1, 10, 15, 30, 0, time.UTC), p.GetPipelineStartTime())
+	})
+
+	t.Run("Merge request", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("GITLAB_CI", "true")
+		os.Setenv("CI_COMMIT_REF_NAME", "feat/test-gitlab")
+		os.Setenv("CI_MERGE_REQUEST_IID", "42")
+		os.Setenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME", "feat/test-gitlab")
+		os.Setenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME", "main")
+		os.Setenv("CI_PIPELINE_SOURCE", "merge_request_event")
+
+		p := GitLabConfigProvider{}
+		c := p.GetPullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "feat/test-gitlab", p.GetBranch())
+		assert.Equal(t, "refs/merge-requests/42/head", p.GetReference())
+		assert.Equal(t, "PullRequest", p.GetBuildReason())
+		assert.Equal(t, "feat/test-gitlab", c.Branch)
+		assert.Equal(t, "main", c.Base)
+		assert.Equal(t, "42", c.Key)
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_COMMIT_TAG", "v1.0.0")
+		os.Setenv("CI_COMMIT_REF_NAME", "v1.0.0")
+
+		p := GitLabConfigProvider{}
+
+		assert.Equal(t, "v1.0.0", p.GetBranch())
+		assert.Equal(t, "refs/tags/v1.0.0", p.GetReference())
+	})
+
+	t.Run("Build status", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		p := GitLabConfigProvider{}
+
+		os.Setenv("CI_JOB_STATUS", "success")
+		assert.Equal(t, "SUCCESS", p.GetBuildStatus())
+		os.Setenv("CI_JOB_STATUS", "canceled")
+		assert.Equal(t, "ABORTED", p.GetBuildStatus())
+		os.Setenv("CI_JOB_STATUS", "failed")
+		assert.Equal(t, "FAILURE", p.GetBuildStatus())
+	})
+
+	t.Run("Init with job token", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("CI_JOB_TOKEN", "jobToken")
+
+		p := GitLabConfigProvider{}
+		p.InitOrchestratorProvider(&OrchestratorSettings{})
+
+		assert.Equal(t, "jobToken", p.header.Get("JOB-TOKEN"))
+	})
+}
+
+func TestGitLabGetLog(t *testing.T) {
+	defer resetEnv(os.Environ())
+	os.Clearenv()
+	os.Setenv("CI_API_V4_URL", "https://gitlab.com/api/v4")
+	os.Setenv("CI_PROJECT_ID", "7")
+	os.Setenv("CI_PIPELINE_ID", "1234")
+	os.Setenv("CI_JOB_ID", "13")
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	t.Run("success", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/pipelines/1234/jobs?per_page=100&page=1",
+			httpmock.NewStringResponder(200, `[{"id": 13, "status": "running"}, {"id": 12, "status": "failed"}, {"id": 11, "status": "success"}, {"id": 14, "status": "created"}]`))
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/jobs/11/trace",
+			httpmock.NewStringResponder(200, "log_record1\n"))
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/jobs/12/trace",
+			httpmock.NewStringResponder(200, "log_record2\n"))
+
+		actual, err := gitLabTestProvider().GetLog()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "log_record1\nlog_record2\n", string(actual))
+	})
+
+	t.Run("jobs on multiple pages", func(t *testing.T) {
+		httpmock.Reset()
+		firstPage := httpmock.NewStringResponse(200, `[{"id": 12, "status": "failed"}]`)
+		firstPage.Header.Set("X-Next-Page", "2")
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/pipelines/1234/jobs?per_page=100&page=1",
+			httpmock.ResponderFromResponse(firstPage))
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/pipelines/1234/jobs?per_page=100&page=2",
+			httpmock.NewStringResponder(200, `[{"id": 11, "status": "success"}]`))
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/jobs/11/trace",
+			httpmock.NewStringResponder(200, "log_record1\n"))
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/jobs/12/trace",
+			httpmock.NewStringResponder(200, "log_record2\n"))
+
+		actual, err := gitLabTestProvider().GetLog()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "log_record1\nlog_record2\n", string(actual))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/pipelines/1234/jobs?per_page=100&page=1",
+			httpmock.NewStringResponder(401, `{"message": "401 Unauthorized"}`))
+
+		_, err := gitLabTestProvider().GetLog()
+
+		assert.Contains(t, err.Error(), "failed to get jobs of pipeline")
+	})
+}
+
+func TestGitLabGetChangeSet(t *testing.T) {
+	defer resetEnv(os.Environ())
+	os.Clearenv()
+	os.Setenv("CI_API_V4_URL", "https://gitlab.com/api/v4")
+	os.Setenv("CI_PROJECT_ID", "7")
+	os.Setenv("CI_COMMIT_SHA", "def")
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	t.Run("merge request", func(t *testing.T) {
+		defer os.Unsetenv("CI_MERGE_REQUEST_IID")
+		os.Setenv("CI_MERGE_REQUEST_IID", "42")
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/merge_requests/42/commits",
+			httpmock.NewStringResponder(200, `[{"id": "def", "committed_date": "2023-03-01T10:15:30.000Z"}, {"id": "abc", "committed_date": "2023-03-01T09:15:30.000Z"}]`))
+
+		changeSet := gitLabTestProvider().GetChangeSet()
+
+		assert.Equal(t, []ChangeSet{
+			{CommitId: "def", Timestamp: "1677665730000", PrNumber: 42},
+			{CommitId: "abc", Timestamp: "1677662130000", PrNumber: 42},
+		}, changeSet)
+	})
+
+	t.Run("push", func(t *testing.T) {
+		defer os.Unsetenv("CI_COMMIT_BEFORE_SHA")
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "abc")
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/repository/compare?from=abc&to=def",
+			httpmock.NewStringResponder(200, `{"commits": [{"id": "def", "committed_date": "2023-03-01T10:15:30.000Z"}]}`))
+
+		changeSet := gitLabTestProvider().GetChangeSet()
+
+		assert.Equal(t, []ChangeSet{{CommitId: "def", Timestamp: "1677665730000"}}, changeSet)
+	})
+
+	t.Run("first push of branch", func(t *testing.T) {
+		defer os.Unsetenv("CI_COMMIT_BEFORE_SHA")
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "0000000000000000000000000000000000000000")
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/repository/commits/def",
+			httpmock.NewStringResponder(200, `{"id": "def", "committed_date": "2023-03-01T10:15:30.000Z"}`))
+
+		changeSet := gitLabTestProvider().GetChangeSet()
+
+		assert.Equal(t, []ChangeSet{{CommitId: "def", Timestamp: "1677665730000"}}, changeSet)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		defer os.Unsetenv("CI_COMMIT_BEFORE_SHA")
+		os.Setenv("CI_COMMIT_BEFORE_SHA", "abc")
+		httpmock.Reset()
+		httpmock.RegisterResponder(http.MethodGet, "https://gitlab.com/api/v4/projects/7/repository/compare?from=abc&to=def",
+			httpmock.NewStringResponder(404, `{"message": "404 Not Found"}`))
+
+		changeSet := gitLabTestProvider().GetChangeSet()
+
+		assert.Equal(t, []ChangeSet{}, changeSet)
+	})
+}
@@ -15,6 +15,9 @@ const (
 	AzureDevOps
 	GitHubActions
 	Jenkins
+	GitLab
+	Tekton
+	Generic
 )
 
 type OrchestratorSpecificConfigProviding interface {
@@ -57,6 +60,7 @@ type OrchestratorSettings struct {
 	JenkinsToken string
 	AzureToken   string
 	GitHubToken  string
+	GitLabToken  string
 }
 
 func NewOrchestratorSpecificConfigProvider() (OrchestratorSpecificConfigProviding, error) {
@@ -67,26 +71,43 @@ func NewOrchestratorSpecificConfigProvider() (OrchestratorSpecificConfigProvidin
 		return &GitHubActionsConfigProvider{}, nil
 	case Jenkins:
 		return &JenkinsConfigProvider{}, nil
+	case GitLab:
+		return &GitLabConfigProvider{}, nil
+	case Tekton:
+		return newTektonConfigProvider(), nil
+	case Generic:
+		provider, err := newGenericConfigProvider(os.Getenv(GenericMappingEnv))
+		if err != nil {
+			return &UnknownOrchestratorConfigProvider{}, err
+		}
+		return provider, nil
 	default:
-		return &UnknownOrchestratorConfigProvider{}, errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins)")
+		return &UnknownOrchestratorConfigProvider{}, errors.New("unable to detect a supported orchestrator (Azure DevOps, GitHub Actions, Jenkins, GitLab, Tekton)")
 	}
 }
 
-// DetectOrchestrator returns the name of the current orchestrator e.g. Jenkins, Azure, Unknown
+// DetectOrchestrator returns the name of the current orchestrator e.g. Jenkins, Azure, GitLab, Unknown
+// A generic orchestrator configured via PIPER_ORCHESTRATOR_MAPPING takes precedence over the detection.
 func DetectOrchestrator() Orchestrator {
-	if isAzure() {
+	if isGeneric() {
+		return Orchestrator(Generic)
+	} else if isAzure() {
 		return Orchestrator(AzureDevOps)
 	} else if isGitHubActions() {
 		return Orchestrator(GitHubActions)
 	} else if isJenkins() {
 		return Orchestrator(Jenkins)
+	} else if isGitLab() {
+		return Orchestrator(GitLab)
+	} else if isTekton() {
+		return Orchestrator(Tekton)
 	} else {
 		return Orchestrator(Unknown)
 	}
 }
 
 func (o Orchestrator) String() string {
-	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins"}[o]
+	return [...]string{"Unknown", "AzureDevOps", "GitHubActions", "Jenkins", "GitLab", "Tekton", "Generic"}[o]
 }
 
 func areIndicatingEnvVarsSet(envVars []string) bool {
@@ -0,0 +1,44 @@
+package orchestrator
+
+import (
+	"os"
+)
+
+// tektonDirectory is mounted into every step container of a Tekton TaskRun
+var tektonDirectory = "/tekton/results"
+
+// tektonEnvMapping defines the environment variables used for Tekton.
+// Since Tekton does not provide environment variables itself, the values need to be passed to the steps,
+// e.g. TEKTON_PIPELINE_RUN from $(context.pipelineRun.name) and GIT_COMMIT from a Tekton Triggers binding.
+var tektonEnvMapping = EnvMapping{
+	OrchestratorType:  "Tekton",
+	StageName:         "${TEKTON_PIPELINE_TASK}",
+	BuildID:           "${TEKTON_PIPELINE_RUN}",
+	BuildURL:          "${TEKTON_DASHBOARD_URL}/#/namespaces/${TEKTON_NAMESPACE}/pipelineruns/${TEKTON_PIPELINE_RUN}",
+	JobURL:            "${TEKTON_DASHBOARD_URL}/#/namespaces/${TEKTON_NAMESPACE}/pipelines/${TEKTON_PIPELINE}",
+	JobName:           "${TEKTON_PIPELINE}",
+	Branch:            "${GIT_BRANCH}",
+	Commit:            "${GIT_COMMIT}",
+	RepoURL:           "${GIT_URL}",
+	PullRequestKey:    "${PULL_REQUEST_NUMBER}",
+	PullRequestBranch: "${PULL_REQUEST_BRANCH}",
+	PullRequestBase:   "${PULL_REQUEST_BASE}",
+	PipelineStartTime: "${TEKTON_PIPELINE_RUN_START_TIME}",
+}
+
+// TektonConfigProvider provides the information about the current Tekton PipelineRun
+type TektonConfigProvider struct {
+	GenericConfigProvider
+}
+
+func newTektonConfigProvider() *TektonConfigProvider {
+	return &TektonConfigProvider{GenericConfigProvider{mapping: tektonEnvMapping}}
+}
+
+func isTekton() bool {
+	if truthy("TEKTON_PIPELINE_RUN") {
+		return true
+	}
+	info, err := os.Stat(tektonDirectory)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTekton(t *testing.T) {
+	t.Run("PipelineRun", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("TEKTON_PIPELINE_RUN", "build-run-x7k2")
+		os.Setenv("TEKTON_PIPELINE", "build")
+		os.Setenv("TEKTON_PIPELINE_TASK", "unit-tests")
+		os.Setenv("TEKTON_NAMESPACE", "ci")
+		os.Setenv("TEKTON_DASHBOARD_URL", "https://tekton.example.com")
+		os.Setenv("GIT_BRANCH", "refs/heads/main")
+		os.Setenv("GIT_COMMIT", "abcdef42713")
+		os.Setenv("GIT_URL", "https://github.com/foo/bar")
+
+		p, err := NewOrchestratorSpecificConfigProvider()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Tekton", DetectOrchestrator().String())
+		assert.Equal(t, "Tekton", p.OrchestratorType())
+		assert.False(t, p.IsPullRequest())
+		assert.Equal(t, "main", p.GetBranch())
+		assert.Equal(t, "refs/heads/main", p.GetReference())
+		assert.Equal(t, "abcdef42713", p.GetCommit())
+		assert.Equal(t, "https://github.com/foo/bar", p.GetRepoURL())
+		assert.Equal(t, "build-run-x7k2", p.GetBuildID())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelineruns/build-run-x7k2", p.GetBuildURL())
+		assert.Equal(t, "https://tekton.example.com/#/namespaces/ci/pipelines/build", p.GetJobURL())
+		assert.Equal(t, "build", p.GetJobName())
+		assert.Equal(t, "unit-tests", p.GetStageName())
+	})
+
+	t.Run("Pull request", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		os.Setenv("PULL_REQUEST_NUMBER", "42")
+		os.Setenv("PULL_REQUEST_BRANCH", "feat/test-tekton")
+		os.Setenv("PULL_REQUEST_BASE", "main")
+
+		p := newTektonConfigProvider()
+		c := p.GetPullRequestConfig()
+
+		assert.True(t, p.IsPullRequest())
+		assert.Equal(t, "PullRequest", p.GetBuildReason())
+		assert.Equal(t, PullRequestConfig{Branch: "feat/test-tekton", Base: "main", Key: "42"}, c)
+	})
+
+	t.Run("Detection via results directory", func(t *testing.T) {
+		defer resetEnv(os.Environ())
+		os.Clearenv()
+		defer func(dir string) { tektonDirectory = dir }(tektonDirectory)
+		tektonDirectory = t.TempDir()
+
+		assert.Equal(t, "Tekton", DetectOrchestrator().String())
+	})
+}
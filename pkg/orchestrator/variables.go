@@ -0,0 +1,18 @@
+package orchestrator
+
+import (
+	"io"
+	"os"
+)
+
+// VariableProviding is implemented by the providers of orchestrators with native pipeline variables,
+// e.g. output variables on Azure DevOps or step outputs on GitHub Actions
+type VariableProviding interface {
+	// SetVariable makes the value available to subsequent steps, jobs or stages of the pipeline
+	SetVariable(name, value string) error
+	// GetVariable returns the value of a variable which is passed to the current step
+	GetVariable(name string) (string, bool)
+}
+
+// variableOutput receives the logging commands setting variables, e.g. on Azure DevOps
+var variableOutput io.Writer = os.Stdout
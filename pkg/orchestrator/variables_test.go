@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package orchestrator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAzureDevOpsVariables(t *testing.T) {
+	t.Run("set output variable", func(t *testing.T) {
+		defer func() { variableOutput = os.Stdout }()
+		out := new(bytes.Buffer)
+		variableOutput = out
+		p := AzureDevOpsConfigProvider{}
+
+		assert.NoError(t, p.SetVariable("artifactVersion", "1.2.3"))
+		assert.NoError(t, p.SetVariable("git;commit]", "line 1\nline 2 with 100%"))
+
+		assert.Equal(t, "##vso[task.setvariable variable=artifactVersion;isOutput=true]1.2.3\n"+
+			"##vso[task.setvariable variable=git%3Bcommit%5D;isOutput=true]line 1%0Aline 2 with 100%AZP25\n", out.String())
+	})
+
+	t.Run("get variable", func(t *testing.T) {
+		t.Setenv("BUILD_ARTIFACT_VERSION", "1.2.3")
+		p := AzureDevOpsConfigProvider{}
+
+		value, ok := p.GetVariable("build.artifact_version")
+		assert.True(t, ok)
+		assert.Equal(t, "1.2.3", value)
+
+		_, ok = p.GetVariable("notSet")
+		assert.False(t, ok)
+	})
+}
+
+func TestGitHubActionsVariables(t *testing.T) {
+	t.Run("set step output", func(t *testing.T) {
+		outputFile := filepath.Join(t.TempDir(), "output")
+		t.Setenv("GITHUB_OUTPUT", outputFile)
+		p := GitHubActionsConfigProvider{}
+
+		assert.NoError(t, p.SetVariable("artifactVersion", "1.2.3"))
+		assert.NoError(t, p.SetVariable("imageNameTags", "[\"app:1.2.3\",\n\"sidecar:1.2.3\"]"))
+
+		content, err := os.ReadFile(outputFile)
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^artifactVersion<<(ghadelimiter_[0-9a-f]+)\n1\.2\.3\n(ghadelimiter_[0-9a-f]+)\n`+
+			`imageNameTags<<(ghadelimiter_[0-9a-f]+)\n\["app:1\.2\.3",\n"sidecar:1\.2\.3"\]\n(ghadelimiter_[0-9a-f]+)\n$`), string(content))
+	})
+
+	t.Run("output file not set", func(t *testing.T) {
+		t.Setenv("GITHUB_OUTPUT", "")
+		p := GitHubActionsConfigProvider{}
+
+		err := p.SetVariable("artifactVersion", "1.2.3")
+
+		assert.EqualError(t, err, "failed to set output 'artifactVersion': GITHUB_OUTPUT is not set")
+	})
+}
@@ -28,8 +28,12 @@ func (c *CPEMap) LoadFromDisk(path string) error {
 	return nil
 }
 
-// WriteToDisk writes the CPEMap to a disk and uses rootDirectory as the starting point
+// WriteToDisk writes the CPEMap to a disk and uses rootDirectory as the starting point.
+// Values of known keys are validated against their type before anything is written.
 func (c CPEMap) WriteToDisk(rootDirectory string) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
 	err := os.MkdirAll(rootDirectory, 0777)
 	if err != nil {
 		return err
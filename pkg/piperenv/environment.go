@@ -15,8 +15,14 @@ import (
 // This file contains functions used to read/write pipeline environment data from/to disk.
 // The content of a written file is the value. For the custom parameters this could for example also be a JSON representation of a more complex value.
 
-// SetResourceParameter sets a resource parameter in the environment stored in the file system
+// SetResourceParameter sets a resource parameter in the environment stored in the file system.
+// Values of known keys of the common pipeline environment are validated against their type.
 func SetResourceParameter(path, resourceName, paramName string, value interface{}) error {
+	if resourceName == "commonPipelineEnvironment" {
+		if err := ValidateCPEValue(filepath.ToSlash(paramName), value); err != nil {
+			return err
+		}
+	}
 	var content []byte
 	paramPath := filepath.Join(path, resourceName, paramName)
 	switch typedValue := value.(type) {
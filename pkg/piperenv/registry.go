@@ -0,0 +1,136 @@
+package piperenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CPEKey describes a key of the common pipeline environment which is written by steps
+type CPEKey struct {
+	// Name is the path of the key within the common pipeline environment, e.g. "git/commitId"
+	Name string `json:"name"`
+	// Type is the type of the value as defined in the step metadata, e.g. "string" or "[]string"
+	Type string `json:"type"`
+	// Producers are the steps writing the key
+	Producers []string `json:"producers"`
+}
+
+// KnownCPEKeys returns the keys of the common pipeline environment which are written by steps, sorted by name
+func KnownCPEKeys() []CPEKey {
+	keys := make([]CPEKey, 0, len(cpeKeys))
+	for _, key := range cpeKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Name < keys[j].Name })
+	return keys
+}
+
+// LookupCPEKey returns the description of a key of the common pipeline environment
+func LookupCPEKey(name string) (CPEKey, bool) {
+	key, ok := cpeKeys[strings.TrimSuffix(name, ".json")]
+	return key, ok
+}
+
+// ValidateCPEValue checks the value against the type of a known key, values of unknown keys are not validated.
+// Empty values are valid for all keys since they reset the value.
+func ValidateCPEValue(name string, value interface{}) error {
+	key, ok := LookupCPEKey(name)
+	if !ok || value == nil || value == "" {
+		return nil
+	}
+	if !matchesType(key.Type, value) {
+		return errors.Errorf("invalid value for common pipeline environment key '%v': expected %v but got %v (written by %v)", key.Name, key.Type, describeType(value), strings.Join(key.Producers, ", "))
+	}
+	return nil
+}
+
+// Validate checks all values of the map against the types of the known keys
+func (c CPEMap) Validate() error {
+	var messages []string
+	for name, value := range c {
+		if err := ValidateCPEValue(name, value); err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		return errors.New(strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+func matchesType(paramType string, value interface{}) bool {
+	switch paramType {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool":
+		switch typedValue := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(typedValue)
+			return err == nil
+		}
+		return false
+	case "int":
+		switch typedValue := value.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return typedValue == float64(int64(typedValue))
+		case json.Number:
+			_, err := typedValue.Int64()
+			return err == nil
+		case string:
+			_, err := strconv.Atoi(typedValue)
+			return err == nil
+		}
+		return false
+	case "[]string":
+		return isSliceOf(value, func(element interface{}) bool {
+			_, ok := element.(string)
+			return ok
+		})
+	case "map[string]interface{}":
+		return reflect.ValueOf(value).Kind() == reflect.Map
+	case "[]map[string]interface{}", "piperenv.Artifacts":
+		return isSliceOf(value, func(element interface{}) bool {
+			kind := reflect.Indirect(reflect.ValueOf(element)).Kind()
+			return kind == reflect.Map || kind == reflect.Struct
+		})
+	}
+	// types without validation
+	return true
+}
+
+func isSliceOf(value interface{}, matchesElement func(interface{}) bool) bool {
+	slice := reflect.ValueOf(value)
+	if slice.Kind() != reflect.Slice {
+		return false
+	}
+	for i := 0; i < slice.Len(); i++ {
+		if !matchesElement(slice.Index(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+func describeType(value interface{}) string {
+	switch value.(type) {
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "a map"
+	case json.Number:
+		return "a number"
+	}
+	return fmt.Sprintf("%T", value)
+}
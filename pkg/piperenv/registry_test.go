@@ -0,0 +1,75 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupCPEKey(t *testing.T) {
+	key, ok := LookupCPEKey("container/imageNameTags")
+	if assert.True(t, ok) {
+		assert.Equal(t, "[]string", key.Type)
+		assert.Contains(t, key.Producers, "kanikoExecute")
+	}
+
+	_, ok = LookupCPEKey("custom/myValue")
+	assert.False(t, ok)
+}
+
+func TestValidateCPEValue(t *testing.T) {
+	tt := []struct {
+		name  string
+		key   string
+		value interface{}
+		err   string
+	}{
+		{name: "string", key: "git/commitId", value: "0123abcd"},
+		{name: "list instead of string", key: "git/commitId", value: []interface{}{"0123abcd"}, err: "invalid value for common pipeline environment key 'git/commitId': expected string but got a list (written by artifactPrepareVersion)"},
+		{name: "list of strings", key: "container/imageNameTags", value: []interface{}{"app:1.0", "sidecar:1.0"}},
+		{name: "typed list of strings", key: "container/imageNameTags.json", value: []string{"app:1.0"}},
+		{name: "string instead of list", key: "container/imageNameTags", value: "app:1.0", err: "invalid value for common pipeline environment key 'container/imageNameTags': expected []string but got string (written by cnbBuild, kanikoExecute)"},
+		{name: "list of numbers", key: "container/imageNameTags", value: []interface{}{json.Number("1")}, err: "invalid value for common pipeline environment key 'container/imageNameTags': expected []string but got a list (written by cnbBuild, kanikoExecute)"},
+		{name: "artifacts", key: "custom/artifacts", value: Artifacts{{Id: "app", Name: "app.jar"}}},
+		{name: "artifacts from JSON", key: "custom/artifacts", value: []interface{}{map[string]interface{}{"id": "app"}}},
+		{name: "bool", key: "custom/isChangeInDevelopment", value: true},
+		{name: "bool as string", key: "custom/isChangeInDevelopment", value: "false"},
+		{name: "empty value", key: "container/imageNameTags", value: ""},
+		{name: "unknown key", key: "custom/myValue", value: []interface{}{1, 2}},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateCPEValue(test.key, test.value)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCPEMapWriteToDiskValidation(t *testing.T) {
+	dir := t.TempDir()
+	cpe := CPEMap{"git/commitId": []interface{}{"0123abcd"}, "artifactVersion": "1.0.0"}
+
+	err := cpe.WriteToDisk(dir)
+
+	assert.EqualError(t, err, "invalid value for common pipeline environment key 'git/commitId': expected string but got a list (written by artifactPrepareVersion)")
+	assert.NoFileExists(t, dir+"/artifactVersion")
+}
+
+func TestSetResourceParameterValidation(t *testing.T) {
+	dir := t.TempDir()
+
+	err := SetResourceParameter(dir, "commonPipelineEnvironment", "container/imageNameTag", []string{"app:1.0"})
+	assert.Contains(t, err.Error(), "expected string but got []string")
+
+	// other resources are not validated
+	err = SetResourceParameter(dir, "influx", "container/imageNameTag", []string{"app:1.0"})
+	assert.NoError(t, err)
+}
@@ -3,13 +3,38 @@ package piperenv
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"text/template"
+
+	"github.com/Masterminds/semver"
+	"github.com/Masterminds/sprig"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 )
 
 const DEFAULT_START_DELIMITER = "{{"
 const DEFAULT_END_DELIMITER = "}}"
 
+// SecretReader reads secrets from Vault for the vaultSecret template function
+type SecretReader interface {
+	GetKvSecret(path string) (map[string]string, error)
+}
+
+// TemplateOptions configures the rendering of CPE templates
+type TemplateOptions struct {
+	StartDelimiter string
+	EndDelimiter   string
+	// Strict fails the rendering in case a referenced value does not exist instead of rendering "<no value>"
+	Strict bool
+	// ReadFile enables the readFile function, only paths relative to the workspace are accepted
+	ReadFile func(path string) ([]byte, error)
+	// SecretReader enables the vaultSecret function, the secrets are masked in the log
+	SecretReader SecretReader
+	// Orchestrator enables the orchestrator function providing metadata of the current pipeline run
+	Orchestrator orchestrator.OrchestratorSpecificConfigProviding
+}
+
 // ParseTemplate allows to parse a template which contains references to the CPE
 // Utility functions make it simple to access specific parts of the CPE
 func (c *CPEMap) ParseTemplate(cpeTemplate string) (*bytes.Buffer, error) {
@@ -17,18 +42,31 @@ func (c *CPEMap) ParseTemplate(cpeTemplate string) (*bytes.Buffer, error) {
 }
 
 func (c *CPEMap) ParseTemplateWithDelimiter(cpeTemplate string, startDelimiter string, endDelimiter string) (*bytes.Buffer, error) {
-	funcMap := template.FuncMap{
-		"cpe":         c.cpe,
-		"cpecustom":   c.custom,
-		"git":         c.git,
-		"imageDigest": c.imageDigest,
-		"imageTag":    c.imageTag,
+	return c.ParseTemplateWithOptions(cpeTemplate, TemplateOptions{StartDelimiter: startDelimiter, EndDelimiter: endDelimiter})
+}
 
-		// ToDo: add template function for artifacts
-		// This requires alignment on artifact handling before, though
+// ParseTemplateWithOptions parses a template which contains references to the CPE.
+// Besides the CPE functions the hermetic sprig functions and semver functions are available,
+// the functions vaultSecret, readFile and orchestrator are available in case they are enabled via the options.
+func (c *CPEMap) ParseTemplateWithOptions(cpeTemplate string, options TemplateOptions) (*bytes.Buffer, error) {
+	startDelimiter, endDelimiter := options.StartDelimiter, options.EndDelimiter
+	if len(startDelimiter) == 0 {
+		startDelimiter = DEFAULT_START_DELIMITER
+	}
+	if len(endDelimiter) == 0 {
+		endDelimiter = DEFAULT_END_DELIMITER
 	}
 
-	tmpl, err := template.New("cpetemplate").Delims(startDelimiter, endDelimiter).Funcs(funcMap).Parse(cpeTemplate)
+	funcMap := template.FuncMap(sprig.HermeticTxtFuncMap())
+	for name, function := range c.templateFunctions(options) {
+		funcMap[name] = function
+	}
+
+	tmpl := template.New("cpetemplate").Delims(startDelimiter, endDelimiter).Funcs(funcMap)
+	if options.Strict {
+		tmpl = tmpl.Option("missingkey=error")
+	}
+	tmpl, err := tmpl.Parse(cpeTemplate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse cpe template '%v': %w", cpeTemplate, err)
 	}
@@ -48,6 +86,133 @@ func (c *CPEMap) ParseTemplateWithDelimiter(cpeTemplate string, startDelimiter s
 	return &generated, nil
 }
 
+func (c *CPEMap) templateFunctions(options TemplateOptions) template.FuncMap {
+	funcMap := template.FuncMap{
+		"cpe":         c.cpe,
+		"cpecustom":   c.custom,
+		"git":         c.git,
+		"imageDigest": c.imageDigest,
+		"imageTag":    c.imageTag,
+
+		// ToDo: add template function for artifacts
+		// This requires alignment on artifact handling before, though
+
+		"bumpMajor": bumpVersion(semver.Version.IncMajor),
+		"bumpMinor": bumpVersion(semver.Version.IncMinor),
+		"bumpPatch": bumpVersion(semver.Version.IncPatch),
+		"vaultSecret": func(path, field string) (string, error) {
+			return vaultSecret(options.SecretReader, path, field)
+		},
+		"readFile": func(path string) (string, error) {
+			return readWorkspaceFile(options.ReadFile, path)
+		},
+		"orchestrator": func(element string) (string, error) {
+			return orchestratorValue(options.Orchestrator, element)
+		},
+	}
+
+	if options.Strict {
+		funcMap["cpe"] = strictLookup("cpe", c.cpe, c.hasValue)
+		funcMap["cpecustom"] = strictLookup("cpecustom", c.custom, func(element string) bool { return c.hasValue(fmt.Sprintf("custom/%v", element)) })
+		funcMap["git"] = strictLookup("git", c.git, func(element string) bool { return c.hasValue(gitKey(element)) })
+		funcMap["imageDigest"] = strictLookup("imageDigest", c.imageDigest, func(imageName string) bool { return len(c.imageDigest(imageName)) > 0 })
+		funcMap["imageTag"] = strictLookup("imageTag", c.imageTag, func(imageName string) bool { return len(c.imageTag(imageName)) > 0 })
+	}
+	return funcMap
+}
+
+// strictLookup wraps a lookup function so that it fails for values which do not exist
+func strictLookup(function string, lookup func(string) string, exists func(string) bool) func(string) (string, error) {
+	return func(element string) (string, error) {
+		if !exists(element) {
+			return "", fmt.Errorf("%v: no value for '%v'", function, element)
+		}
+		return lookup(element), nil
+	}
+}
+
+func (c *CPEMap) hasValue(element string) bool {
+	value, ok := map[string]interface{}(*c)[element]
+	return ok && value != nil
+}
+
+func bumpVersion(increment func(semver.Version) semver.Version) func(interface{}) (string, error) {
+	return func(version interface{}) (string, error) {
+		parsed, err := semver.NewVersion(fmt.Sprint(version))
+		if err != nil {
+			return "", fmt.Errorf("invalid semantic version '%v': %w", version, err)
+		}
+		bumped := increment(*parsed)
+		return bumped.String(), nil
+	}
+}
+
+func vaultSecret(reader SecretReader, path, field string) (string, error) {
+	if reader == nil {
+		return "", fmt.Errorf("vaultSecret: Vault is not configured")
+	}
+	secret, err := reader.GetKvSecret(path)
+	if err != nil {
+		return "", fmt.Errorf("vaultSecret: failed to read secret '%v': %w", path, err)
+	}
+	value, ok := secret[field]
+	if !ok {
+		return "", fmt.Errorf("vaultSecret: secret '%v' has no field '%v'", path, field)
+	}
+	log.RegisterSecret(value)
+	return value, nil
+}
+
+func readWorkspaceFile(readFile func(string) ([]byte, error), path string) (string, error) {
+	if readFile == nil {
+		return "", fmt.Errorf("readFile: reading files is not enabled")
+	}
+	cleaned := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(cleaned) || filepath.VolumeName(cleaned) != "" || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("readFile: path '%v' is outside of the workspace", path)
+	}
+	content, err := readFile(cleaned)
+	if err != nil {
+		return "", fmt.Errorf("readFile: failed to read '%v': %w", path, err)
+	}
+	return string(content), nil
+}
+
+func orchestratorValue(provider orchestrator.OrchestratorSpecificConfigProviding, element string) (string, error) {
+	if provider == nil {
+		return "", fmt.Errorf("orchestrator: no orchestrator information available")
+	}
+	switch element {
+	case "type":
+		return provider.OrchestratorType(), nil
+	case "branch":
+		return provider.GetBranch(), nil
+	case "reference":
+		return provider.GetReference(), nil
+	case "commit":
+		return provider.GetCommit(), nil
+	case "buildID":
+		return provider.GetBuildID(), nil
+	case "buildURL":
+		return provider.GetBuildURL(), nil
+	case "jobName":
+		return provider.GetJobName(), nil
+	case "jobURL":
+		return provider.GetJobURL(), nil
+	case "stageName":
+		return provider.GetStageName(), nil
+	case "isPullRequest":
+		return fmt.Sprint(provider.IsPullRequest()), nil
+	case "pullRequestKey":
+		return provider.GetPullRequestConfig().Key, nil
+	case "pullRequestBranch":
+		return provider.GetPullRequestConfig().Branch, nil
+	case "pullRequestBase":
+		return provider.GetPullRequestConfig().Base, nil
+	}
+	return "", fmt.Errorf("orchestrator: unknown element '%v'", element)
+}
+
 func (c *CPEMap) cpe(element string) string {
 	// ToDo: perform validity checks to allow only selected fields for now?
 	// This would allow a stable contract and could perform conversions in case a contract changes.
@@ -60,13 +225,14 @@ func (c *CPEMap) custom(element string) string {
 }
 
 func (c *CPEMap) git(element string) string {
-	var el string
+	return fmt.Sprint(map[string]interface{}(*c)[gitKey(element)])
+}
+
+func gitKey(element string) string {
 	if element == "organization" || element == "repository" {
-		el = fmt.Sprint(map[string]interface{}(*c)[fmt.Sprintf("github/%v", element)])
-	} else {
-		el = fmt.Sprint(map[string]interface{}(*c)[fmt.Sprintf("git/%v", element)])
+		return fmt.Sprintf("github/%v", element)
 	}
-	return el
+	return fmt.Sprintf("git/%v", element)
 }
 
 func (c *CPEMap) imageDigest(imageName string) string {
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -249,3 +250,80 @@ func TestTemplateFunctionImageTag(t *testing.T) {
 		assert.Equal(t, "tag2", (*res).String())
 	})
 }
+
+type secretReaderMock struct {
+	secrets map[string]map[string]string
+}
+
+func (s *secretReaderMock) GetKvSecret(path string) (map[string]string, error) {
+	secret, ok := s.secrets[path]
+	if !ok {
+		return nil, fmt.Errorf("secret not found")
+	}
+	return secret, nil
+}
+
+func TestParseTemplateWithOptions(t *testing.T) {
+	cpe := CPEMap{
+		"artifactVersion": "1.2.3",
+		"git/commitId":    "thisIsMyTestSha",
+	}
+	readFile := func(path string) ([]byte, error) {
+		if path == filepath.Join("config", "values.txt") {
+			return []byte("fileContent"), nil
+		}
+		return nil, fmt.Errorf("file not found")
+	}
+	options := TemplateOptions{
+		ReadFile:     readFile,
+		SecretReader: &secretReaderMock{secrets: map[string]map[string]string{"team/app": {"password": "s3cr3t"}}},
+		Orchestrator: &orchestrator.UnknownOrchestratorConfigProvider{},
+	}
+
+	tt := []struct {
+		name          string
+		template      string
+		strict        bool
+		expected      string
+		expectedError string
+	}{
+		{name: "sprig functions", template: `{{cpe "artifactVersion" | replace "." "-" | upper}}`, expected: "1-2-3"},
+		{name: "semver functions", template: `{{bumpMajor (cpe "artifactVersion")}} {{bumpMinor (cpe "artifactVersion")}} {{bumpPatch .CPE.artifactVersion}}`, expected: "2.0.0 1.3.0 1.2.4"},
+		{name: "semver parsing", template: `{{(semver .CPE.artifactVersion).Minor}} {{semverCompare ">=1.0.0" (cpe "artifactVersion")}}`, expected: "2 true"},
+		{name: "invalid semver", template: `{{bumpPatch (git "commitId")}}`, expectedError: "invalid semantic version 'thisIsMyTestSha'"},
+		{name: "vault secret", template: `{{vaultSecret "team/app" "password"}}`, expected: "s3cr3t"},
+		{name: "vault secret without field", template: `{{vaultSecret "team/app" "user"}}`, expectedError: "vaultSecret: secret 'team/app' has no field 'user'"},
+		{name: "read file", template: `{{readFile "config/../config/values.txt"}}`, expected: "fileContent"},
+		{name: "read file outside of workspace", template: `{{readFile "../values.txt"}}`, expectedError: "readFile: path '../values.txt' is outside of the workspace"},
+		{name: "read absolute file", template: `{{readFile "/etc/passwd"}}`, expectedError: "readFile: path '/etc/passwd' is outside of the workspace"},
+		{name: "orchestrator", template: `{{orchestrator "branch"}} {{orchestrator "pullRequestKey"}}`, expected: "n/a n/a"},
+		{name: "unknown orchestrator element", template: `{{orchestrator "foo"}}`, expectedError: "orchestrator: unknown element 'foo'"},
+		{name: "missing value", template: `{{cpe "custom/foo"}} {{.CPE.foo}}`, expected: "<nil> <no value>"},
+		{name: "strict mode", template: `{{cpe "artifactVersion"}} {{.CPE.artifactVersion}}`, strict: true, expected: "1.2.3 1.2.3"},
+		{name: "strict mode with missing cpe value", template: `{{cpe "custom/foo"}}`, strict: true, expectedError: "cpe: no value for 'custom/foo'"},
+		{name: "strict mode with missing git value", template: `{{git "branch"}}`, strict: true, expectedError: "git: no value for 'branch'"},
+		{name: "strict mode with missing image tag", template: `{{imageTag "image"}}`, strict: true, expectedError: "imageTag: no value for 'image'"},
+		{name: "strict mode with missing key", template: `{{.CPE.foo}}`, strict: true, expectedError: `map has no entry for key "foo"`},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			options.Strict = test.strict
+			res, err := cpe.ParseTemplateWithOptions(test.template, options)
+			if len(test.expectedError) > 0 {
+				assert.Contains(t, fmt.Sprint(err), test.expectedError)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, res.String())
+			}
+		})
+	}
+
+	t.Run("functions not enabled", func(t *testing.T) {
+		_, err := cpe.ParseTemplate(`{{vaultSecret "team/app" "password"}}`)
+		assert.Contains(t, fmt.Sprint(err), "vaultSecret: Vault is not configured")
+		_, err = cpe.ParseTemplate(`{{readFile "values.txt"}}`)
+		assert.Contains(t, fmt.Sprint(err), "readFile: reading files is not enabled")
+		_, err = cpe.ParseTemplate(`{{orchestrator "branch"}}`)
+		assert.Contains(t, fmt.Sprint(err), "orchestrator: no orchestrator information available")
+	})
+}
@@ -0,0 +1,97 @@
+package piperenv
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// VariableSetter sets native variables of the orchestrator
+type VariableSetter interface {
+	SetVariable(name, value string) error
+}
+
+// VariableGetter reads native variables of the orchestrator
+type VariableGetter interface {
+	GetVariable(name string) (string, bool)
+}
+
+// ParseVariableMapping parses entries in the format <CPE key>=<variable name>.
+// Without variable name the name is derived from the key, e.g. "git/commitId" becomes "git_commitId".
+func ParseVariableMapping(entries []string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, entry := range entries {
+		key, name := entry, ""
+		if parts := strings.SplitN(entry, "=", 2); len(parts) == 2 {
+			key, name = strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if len(name) == 0 {
+				return nil, errors.Errorf("invalid variable mapping '%v', expected <key>=<variable name>", entry)
+			}
+		}
+		if len(key) == 0 {
+			return nil, errors.Errorf("invalid variable mapping '%v', expected <key>=<variable name>", entry)
+		}
+		if len(name) == 0 {
+			name = strings.ReplaceAll(key, "/", "_")
+		}
+		mapping[key] = name
+	}
+	return mapping, nil
+}
+
+// ExportVariables sets a variable for each mapped key which has a value, values which are not strings are JSON encoded
+func (c CPEMap) ExportVariables(mapping map[string]string, setter VariableSetter) error {
+	for _, key := range sortedKeys(mapping) {
+		value, ok := c[key]
+		if !ok || value == nil {
+			continue
+		}
+		content, ok := value.(string)
+		if !ok {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return errors.Wrapf(err, "failed to encode value of %v", key)
+			}
+			content = string(encoded)
+		}
+		if err := setter.SetVariable(mapping[key], content); err != nil {
+			return errors.Wrapf(err, "failed to export %v", key)
+		}
+	}
+	return nil
+}
+
+// ImportVariables adds the values of the mapped variables which are set, values of known keys which are not strings are JSON decoded
+func (c CPEMap) ImportVariables(mapping map[string]string, getter VariableGetter) error {
+	for _, key := range sortedKeys(mapping) {
+		content, ok := getter.GetVariable(mapping[key])
+		if !ok {
+			continue
+		}
+		var value interface{} = content
+		if cpeKey, known := LookupCPEKey(key); known && cpeKey.Type != "string" && len(content) > 0 {
+			decoder := json.NewDecoder(strings.NewReader(content))
+			decoder.UseNumber()
+			err := decoder.Decode(&value)
+			if err == nil && decoder.More() {
+				err = errors.New("unexpected content after JSON value")
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to decode variable %v as %v", mapping[key], cpeKey.Type)
+			}
+		}
+		c[key] = value
+	}
+	return nil
+}
+
+func sortedKeys(mapping map[string]string) []string {
+	keys := make([]string, 0, len(mapping))
+	for key := range mapping {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
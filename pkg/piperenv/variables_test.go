@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package piperenv
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type variablesMock map[string]string
+
+func (v variablesMock) SetVariable(name, value string) error {
+	if name == "failing" {
+		return errors.New("failed")
+	}
+	v[name] = value
+	return nil
+}
+
+func (v variablesMock) GetVariable(name string) (string, bool) {
+	value, ok := v[name]
+	return value, ok
+}
+
+func TestParseVariableMapping(t *testing.T) {
+	mapping, err := ParseVariableMapping([]string{"artifactVersion=VERSION", "git/commitId"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{"artifactVersion": "VERSION", "git/commitId": "git_commitId"}, mapping)
+	}
+
+	_, err = ParseVariableMapping([]string{"artifactVersion="})
+	assert.EqualError(t, err, "invalid variable mapping 'artifactVersion=', expected <key>=<variable name>")
+}
+
+func TestExportVariables(t *testing.T) {
+	cpe := CPEMap{
+		"artifactVersion":         "1.2.3",
+		"container/imageNameTags": []interface{}{"app:1.2.3"},
+		"custom/other":            "not exported",
+	}
+	variables := variablesMock{}
+
+	err := cpe.ExportVariables(map[string]string{"artifactVersion": "version", "container/imageNameTags": "images", "git/commitId": "commit"}, variables)
+
+	assert.NoError(t, err)
+	assert.Equal(t, variablesMock{"version": "1.2.3", "images": `["app:1.2.3"]`}, variables)
+
+	err = cpe.ExportVariables(map[string]string{"artifactVersion": "failing"}, variables)
+	assert.EqualError(t, err, "failed to export artifactVersion: failed")
+}
+
+func TestImportVariables(t *testing.T) {
+	variables := variablesMock{"version": "1.2.3", "images": `["app:1.2.3"]`, "custom": `["kept as string"]`}
+
+	t.Run("success case", func(t *testing.T) {
+		cpe := CPEMap{}
+		err := cpe.ImportVariables(map[string]string{"artifactVersion": "version", "container/imageNameTags": "images", "custom/value": "custom", "git/commitId": "commit"}, variables)
+
+		assert.NoError(t, err)
+		assert.Equal(t, CPEMap{"artifactVersion": "1.2.3", "container/imageNameTags": []interface{}{"app:1.2.3"}, "custom/value": `["kept as string"]`}, cpe)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		cpe := CPEMap{}
+		err := cpe.ImportVariables(map[string]string{"container/imageNameTags": "version"}, variables)
+
+		assert.Contains(t, err.Error(), "failed to decode variable version as []string")
+	})
+}
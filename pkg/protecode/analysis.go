@@ -1,6 +1,11 @@
 package protecode
 
-import "strconv"
+import (
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/package-url/packageurl-go"
+)
 
 const (
 	vulnerabilitySeverityThreshold = 7.0
@@ -39,3 +44,48 @@ func isSevere(vulnerability Vulnerability) bool {
 	}
 	return false
 }
+
+// ToFindings returns the scanner independent representation of the vulnerabilities,
+// triaged, excluded and historic vulnerabilities are considered as not relevant
+func ToFindings(result Result, excludeCVEs string) []format.Finding {
+	findings := []format.Finding{}
+	for _, component := range result.Components {
+		packageURL := packageurl.NewPackageURL(packageurl.TypeGeneric, "", component.Lib, component.Version, nil, "").ToString()
+		for _, vulnerability := range component.Vulns {
+			score, _ := strconv.ParseFloat(vulnerability.Vuln.Cvss3Score, 64)
+			if score == 0 {
+				// CVSS v3 not set, fallback to CVSS v2
+				score, _ = strconv.ParseFloat(vulnerability.Vuln.Cvss, 64)
+			}
+			finding := format.Finding{
+				Scanner:     "Protecode",
+				ID:          vulnerability.Vuln.Cve,
+				Title:       vulnerability.Vuln.Cve + " " + component.Lib,
+				Description: vulnerability.Vuln.Summary,
+				Severity:    format.SeverityFromScore(score),
+				Score:       score,
+				CVE:         format.ExtractCVE(vulnerability.Vuln.Cve),
+				PackageURL:  packageURL,
+				AuditState:  format.AuditStateNew,
+				Fingerprint: format.PackageFingerprint(packageURL, vulnerability.Vuln.Cve),
+				Link:        result.ReportURL,
+			}
+			if len(vulnerability.Vuln.Cwe) > 0 {
+				finding.CWE = []string{vulnerability.Vuln.Cwe}
+			}
+			switch {
+			case isTriaged(vulnerability):
+				finding.AuditState = format.AuditStateNotRelevant
+				finding.AuditComment = vulnerability.Triage[0].Description
+			case isExcluded(vulnerability, excludeCVEs):
+				finding.AuditState = format.AuditStateNotRelevant
+				finding.AuditComment = "excluded"
+			case !isExact(vulnerability):
+				finding.AuditState = format.AuditStateNotRelevant
+				finding.AuditComment = "historic vulnerability"
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
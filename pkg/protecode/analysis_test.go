@@ -6,6 +6,8 @@ package protecode
 import (
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/format"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -128,3 +130,44 @@ func TestHasSevereVulnerabilities(t *testing.T) {
 		assert.False(t, HasSevereVulnerabilities(data, ""))
 	})
 }
+
+func TestToFindings(t *testing.T) {
+	result := Result{
+		ReportURL: "https://protecode.example.com/products/1/",
+		Components: []Component{{
+			Lib:     "openssl",
+			Version: "1.1.1k",
+			Vulns: []Vulnerability{
+				{Exact: true, Vuln: Vuln{Cve: "CVE-2021-3711", Cvss: "7.5", Cvss3Score: "9.8", Cwe: "CWE-120", Summary: "buffer overflow"}},
+				{Exact: true, Vuln: Vuln{Cve: "CVE-2021-3712", Cvss: "5.8"}, Triage: []Triage{{Description: "not reachable"}}},
+				{Exact: true, Vuln: Vuln{Cve: "CVE-2021-23840", Cvss3Score: "7.5"}},
+				{Exact: false, Vuln: Vuln{Cve: "CVE-2016-2105", Cvss: "5.0"}},
+			},
+		}},
+	}
+
+	findings := ToFindings(result, "CVE-2021-23840")
+
+	if assert.Len(t, findings, 4) {
+		assert.Equal(t, format.Finding{
+			Scanner:     "Protecode",
+			ID:          "CVE-2021-3711",
+			Title:       "CVE-2021-3711 openssl",
+			Description: "buffer overflow",
+			Severity:    format.SeverityCritical,
+			Score:       9.8,
+			CVE:         "CVE-2021-3711",
+			CWE:         []string{"CWE-120"},
+			PackageURL:  "pkg:generic/openssl@1.1.1k",
+			AuditState:  format.AuditStateNew,
+			Fingerprint: format.PackageFingerprint("pkg:generic/openssl@1.1.1k", "CVE-2021-3711"),
+			Link:        "https://protecode.example.com/products/1/",
+		}, findings[0])
+
+		assert.Equal(t, format.SeverityMedium, findings[1].Severity)
+		assert.Equal(t, format.AuditStateNotRelevant, findings[1].AuditState)
+		assert.Equal(t, "not reachable", findings[1].AuditComment)
+		assert.Equal(t, "excluded", findings[2].AuditComment)
+		assert.Equal(t, "historic vulnerability", findings[3].AuditComment)
+	}
+}
@@ -46,7 +46,9 @@ type Result struct {
 
 // Component the protecode component information
 type Component struct {
-	Vulns []Vulnerability `json:"vulns,omitempty"`
+	Lib     string          `json:"lib,omitempty"`
+	Version string          `json:"version,omitempty"`
+	Vulns   []Vulnerability `json:"vulns,omitempty"`
 }
 
 // Vulnerability the protecode vulnerability information
@@ -61,6 +63,8 @@ type Vuln struct {
 	Cve        string `json:"cve,omitempty"`
 	Cvss       string `json:"cvss,omitempty"`
 	Cvss3Score string `json:"cvss3_score,omitempty"`
+	Cwe        string `json:"cwe,omitempty"`
+	Summary    string `json:"summary,omitempty"`
 }
 
 // Triage holds the triaging information
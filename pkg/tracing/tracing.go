@@ -0,0 +1,162 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/SAP/jenkins-library"
+
+// shutdownTimeout limits the time spent for exporting the remaining spans at the end of a step
+const shutdownTimeout = 10 * time.Second
+
+var (
+	provider    *sdktrace.TracerProvider
+	outputFile  *os.File
+	stepContext = context.Background()
+	stepSpan    trace.Span
+)
+
+// Initialize sets up the export of OpenTelemetry spans and is called in generated part of the steps.
+// Spans are sent to an OTLP/HTTP endpoint, e.g. https://otel-collector:4318, and/or appended to a file for offline analysis.
+// Without endpoint and file no spans are recorded.
+func Initialize(correlationID, endpoint, token, file string) error {
+	if len(endpoint) == 0 && len(file) == 0 {
+		return nil
+	}
+
+	var exporters []sdktrace.SpanExporter
+	if len(endpoint) > 0 {
+		exporter, err := newOTLPExporter(endpoint, token)
+		if err != nil {
+			return err
+		}
+		exporters = append(exporters, exporter)
+	}
+	if len(file) > 0 {
+		exporter, err := newFileExporter(file)
+		if err != nil {
+			return err
+		}
+		exporters = append(exporters, exporter)
+	}
+	initializeProvider(correlationID, exporters...)
+	return nil
+}
+
+func initializeProvider(correlationID string, exporters ...sdktrace.SpanExporter) {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String("piper"),
+			attribute.String("piper.correlation_id", correlationID),
+		)),
+	}
+	for _, exporter := range exporters {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+	provider = sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	log.Entry().Debug("OpenTelemetry tracing initialized")
+}
+
+func newOTLPExporter(endpoint, token string) (sdktrace.SpanExporter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil || len(endpointURL.Host) == 0 {
+		return nil, fmt.Errorf("invalid OpenTelemetry endpoint '%v', expected e.g. https://otel-collector:4318", endpoint)
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpointURL.Host)}
+	if endpointURL.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	if path := strings.TrimSuffix(endpointURL.Path, "/"); len(path) > 0 {
+		options = append(options, otlptracehttp.WithURLPath(path))
+	}
+	if len(token) > 0 {
+		log.RegisterSecret(token)
+		options = append(options, otlptracehttp.WithHeaders(map[string]string{"Authorization": token}))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry exporter for '%v': %w", endpoint, err)
+	}
+	return exporter, nil
+}
+
+func newFileExporter(file string) (sdktrace.SpanExporter, error) {
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OpenTelemetry output file '%v': %w", file, err)
+	}
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to create OpenTelemetry file exporter: %w", err)
+	}
+	outputFile = f
+	return exporter, nil
+}
+
+// StartStepSpan starts the root span of a step, spans created via StartSpan become its children
+func StartStepSpan(stepName, stageName string) {
+	stepContext, stepSpan = otel.Tracer(instrumentationName).Start(context.Background(), stepName,
+		trace.WithAttributes(
+			attribute.String("piper.step.name", stepName),
+			attribute.String("piper.stage.name", stageName),
+		))
+}
+
+// EndStepSpan ends the root span of a step and exports all pending spans.
+// It is safe to call it several times, e.g. from the deferred exit handler of the step.
+func EndStepSpan(errorCode, errorCategory string) {
+	if stepSpan != nil {
+		stepSpan.SetAttributes(
+			attribute.String("piper.error_code", errorCode),
+			attribute.String("piper.error_category", errorCategory),
+		)
+		if errorCode != "0" {
+			stepSpan.SetStatus(codes.Error, fmt.Sprintf("step failed with error category %v", errorCategory))
+		}
+		stepSpan.End()
+		stepSpan = nil
+		stepContext = context.Background()
+	}
+	shutdown()
+}
+
+func shutdown() {
+	if provider != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Entry().WithError(err).Warn("failed to export OpenTelemetry spans")
+		}
+		provider = nil
+	}
+	if outputFile != nil {
+		outputFile.Close()
+		outputFile = nil
+	}
+}
+
+// StartSpan starts a span as child of the span contained in ctx or, if there is none, as child of the current step span
+func StartSpan(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = trace.ContextWithSpan(ctx, trace.SpanFromContext(stepContext))
+	}
+	return otel.Tracer(instrumentationName).Start(ctx, name, options...)
+}
@@ -0,0 +1,101 @@
+//go:build unit
+// +build unit
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type spanRecorder struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (r *spanRecorder) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	r.spans = append(r.spans, spans...)
+	return nil
+}
+
+func (r *spanRecorder) Shutdown(context.Context) error {
+	return nil
+}
+
+func attributeValue(span sdktrace.ReadOnlySpan, key string) string {
+	for _, a := range span.Attributes() {
+		if string(a.Key) == key {
+			return a.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestStepSpan(t *testing.T) {
+	t.Run("child spans", func(t *testing.T) {
+		recorder := &spanRecorder{}
+		initializeProvider("correlation-42", recorder)
+
+		StartStepSpan("mavenBuild", "Build")
+		_, span := StartSpan(context.Background(), "HTTP GET example.com")
+		span.End()
+		EndStepSpan("0", "undefined")
+
+		if assert.Len(t, recorder.spans, 2) {
+			child, root := recorder.spans[0], recorder.spans[1]
+			assert.Equal(t, "mavenBuild", root.Name())
+			assert.False(t, root.Parent().IsValid())
+			assert.Equal(t, "Build", attributeValue(root, "piper.stage.name"))
+			assert.Equal(t, codes.Unset, root.Status().Code)
+			assert.Contains(t, root.Resource().Attributes(), attribute.String("piper.correlation_id", "correlation-42"))
+			assert.Equal(t, root.SpanContext().SpanID(), child.Parent().SpanID())
+			assert.Equal(t, root.SpanContext().TraceID(), child.SpanContext().TraceID())
+		}
+	})
+
+	t.Run("failed step", func(t *testing.T) {
+		recorder := &spanRecorder{}
+		initializeProvider("", recorder)
+
+		StartStepSpan("mavenBuild", "Build")
+		EndStepSpan("1", "build")
+		// second call from the exit handler must not fail
+		EndStepSpan("1", "build")
+
+		if assert.Len(t, recorder.spans, 1) {
+			assert.Equal(t, codes.Error, recorder.spans[0].Status().Code)
+			assert.Equal(t, "build", attributeValue(recorder.spans[0], "piper.error_category"))
+		}
+	})
+}
+
+func TestInitialize(t *testing.T) {
+	t.Run("file exporter", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "spans.json")
+
+		err := Initialize("correlation-42", "", "", file)
+		assert.NoError(t, err)
+		StartStepSpan("mavenBuild", "Build")
+		EndStepSpan("0", "undefined")
+
+		content, err := os.ReadFile(file)
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"Name":"mavenBuild"`)
+	})
+
+	t.Run("nothing configured", func(t *testing.T) {
+		assert.NoError(t, Initialize("", "", "", ""))
+		assert.Nil(t, provider)
+	})
+
+	t.Run("invalid endpoint", func(t *testing.T) {
+		err := Initialize("", "otel-collector", "", "")
+		assert.EqualError(t, err, "invalid OpenTelemetry endpoint 'otel-collector', expected e.g. https://otel-collector:4318")
+	})
+}
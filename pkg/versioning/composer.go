@@ -0,0 +1,46 @@
+package versioning
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Composer defines an artifact using a composer.json file of PHP for versioning
+type Composer struct {
+	JSONfile
+}
+
+// GetVersion returns the current version of the artifact with a composer.json build descriptor
+func (c *Composer) GetVersion() (string, error) {
+	version, err := c.JSONfile.GetVersion()
+	if err != nil {
+		return "", err
+	}
+	if _, ok := c.content.Get(c.versionField); !ok {
+		return "", errors.Errorf("no version found in '%v', composer derives the version from tags in this case", c.path)
+	}
+	return version, nil
+}
+
+// GetCoordinates returns the coordinates, the package name "<vendor>/<project>" is split into group and artifact
+func (c *Composer) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	projectVersion, err := c.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	if name, ok := c.content.Get("name"); ok {
+		if parts := strings.SplitN(fmt.Sprint(name), "/", 2); len(parts) == 2 {
+			result.GroupID, result.ArtifactID = parts[0], parts[1]
+		} else {
+			result.ArtifactID = parts[0]
+		}
+	}
+	if packageType, ok := c.content.Get("type"); ok {
+		result.Packaging, _ = packageType.(string)
+	}
+	result.Version = projectVersion
+	return result, nil
+}
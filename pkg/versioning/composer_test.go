@@ -0,0 +1,56 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposerGetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		composer := Composer{JSONfile{
+			path:     "composer.json",
+			readFile: func(filename string) ([]byte, error) { return []byte(`{"name": "acme/shop", "version": "1.2.3"}`), nil },
+		}}
+		version, err := composer.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("error case - version derived from tags", func(t *testing.T) {
+		composer := Composer{JSONfile{
+			path:     "composer.json",
+			readFile: func(filename string) ([]byte, error) { return []byte(`{"name": "acme/shop"}`), nil },
+		}}
+		_, err := composer.GetVersion()
+		assert.EqualError(t, err, "no version found in 'composer.json', composer derives the version from tags in this case")
+	})
+}
+
+func TestComposerSetVersion(t *testing.T) {
+	var content []byte
+	composer := Composer{JSONfile{
+		path:      "composer.json",
+		readFile:  func(filename string) ([]byte, error) { return []byte(`{"name": "acme/shop", "version": "1.2.3"}`), nil },
+		writeFile: func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil },
+	}}
+	err := composer.SetVersion("1.3.0")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"version": "1.3.0"`)
+}
+
+func TestComposerGetCoordinates(t *testing.T) {
+	composer := Composer{JSONfile{
+		path: "composer.json",
+		readFile: func(filename string) ([]byte, error) {
+			return []byte(`{"name": "acme/shop", "type": "library", "version": "1.2.3"}`), nil
+		},
+	}}
+	coordinates, err := composer.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{GroupID: "acme", ArtifactID: "shop", Version: "1.2.3", Packaging: "library"}, coordinates)
+}
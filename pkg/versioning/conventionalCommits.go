@@ -0,0 +1,147 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+)
+
+// Bump is the increment of a semantic version derived from commit messages
+type Bump int
+
+const (
+	BumpNone Bump = iota
+	BumpPatch
+	BumpMinor
+	BumpMajor
+)
+
+func (b Bump) String() string {
+	switch b {
+	case BumpPatch:
+		return "patch"
+	case BumpMinor:
+		return "minor"
+	case BumpMajor:
+		return "major"
+	}
+	return "none"
+}
+
+// ConventionalCommit is a commit message in the format of https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Description string
+	Breaking    bool
+}
+
+var conventionalCommitHeader = regexp.MustCompile(`^(\w+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+var breakingChangeFooter = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: `)
+
+// ParseConventionalCommit parses the header and footers of a commit message, false is returned in case the message does not follow the convention
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	header := strings.TrimSpace(strings.SplitN(strings.TrimSpace(message), "\n", 2)[0])
+	match := conventionalCommitHeader.FindStringSubmatch(header)
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	return ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       match[2],
+		Description: strings.TrimSpace(match[4]),
+		Breaking:    match[3] == "!" || breakingChangeFooter.MatchString(message),
+	}, true
+}
+
+// Bump returns the increment required by the commit: major for breaking changes, minor for features and patch for fixes
+func (c ConventionalCommit) Bump() Bump {
+	switch {
+	case c.Breaking:
+		return BumpMajor
+	case c.Type == "feat":
+		return BumpMinor
+	case c.Type == "fix" || c.Type == "perf":
+		return BumpPatch
+	}
+	return BumpNone
+}
+
+// ConventionalCommitBump returns the highest increment required by the commit messages, messages not following the convention are ignored
+func ConventionalCommitBump(messages []string) Bump {
+	bump := BumpNone
+	for _, message := range messages {
+		if commit, ok := ParseConventionalCommit(message); ok && commit.Bump() > bump {
+			bump = commit.Bump()
+		}
+	}
+	return bump
+}
+
+// BumpVersion increments a semantic version, pre-release and build metadata are dropped
+func BumpVersion(version string, bump Bump) (string, error) {
+	parsed, err := semver.NewVersion(version)
+	if err != nil {
+		return "", errors.Wrapf(err, "version '%v' is not a semantic version", version)
+	}
+	// drop pre-release and build metadata, thus e.g. 1.2.3-rc.1 is bumped to 1.2.4 instead of 1.2.3
+	release := semver.MustParse(fmt.Sprintf("%d.%d.%d", parsed.Major(), parsed.Minor(), parsed.Patch()))
+	var bumped semver.Version
+	switch bump {
+	case BumpMajor:
+		bumped = release.IncMajor()
+	case BumpMinor:
+		bumped = release.IncMinor()
+	case BumpPatch:
+		bumped = release.IncPatch()
+	default:
+		return version, nil
+	}
+	return bumped.String(), nil
+}
+
+// Changelog renders the changes of a version as markdown, grouped into breaking changes, features and fixes
+func Changelog(version string, messages []string, date time.Time) string {
+	sections := []struct {
+		title   string
+		matches func(ConventionalCommit) bool
+		entries []string
+	}{
+		{title: "Breaking Changes", matches: func(c ConventionalCommit) bool { return c.Breaking }},
+		{title: "Features", matches: func(c ConventionalCommit) bool { return c.Type == "feat" }},
+		{title: "Bug Fixes", matches: func(c ConventionalCommit) bool { return c.Type == "fix" || c.Type == "perf" }},
+	}
+	for _, message := range messages {
+		commit, ok := ParseConventionalCommit(message)
+		if !ok {
+			continue
+		}
+		entry := commit.Description
+		if len(commit.Scope) > 0 {
+			entry = fmt.Sprintf("**%v:** %v", commit.Scope, entry)
+		}
+		for i := range sections {
+			if sections[i].matches(commit) {
+				sections[i].entries = append(sections[i].entries, entry)
+				break
+			}
+		}
+	}
+
+	var changelog strings.Builder
+	fmt.Fprintf(&changelog, "## %v (%v)\n", version, date.Format("2006-01-02"))
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(&changelog, "\n### %v\n\n", section.title)
+		for _, entry := range section.entries {
+			fmt.Fprintf(&changelog, "* %v\n", entry)
+		}
+	}
+	return changelog.String()
+}
@@ -0,0 +1,94 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tt := []struct {
+		message  string
+		expected ConventionalCommit
+		ok       bool
+	}{
+		{message: "feat: add login", expected: ConventionalCommit{Type: "feat", Description: "add login"}, ok: true},
+		{message: "fix(api): handle timeouts\n\nsome details", expected: ConventionalCommit{Type: "fix", Scope: "api", Description: "handle timeouts"}, ok: true},
+		{message: "refactor!: drop old API", expected: ConventionalCommit{Type: "refactor", Description: "drop old API", Breaking: true}, ok: true},
+		{message: "feat(ui): new layout\n\nBREAKING CHANGE: themes are removed", expected: ConventionalCommit{Type: "feat", Scope: "ui", Description: "new layout", Breaking: true}, ok: true},
+		{message: "Merge pull request #1 from branch", ok: false},
+		{message: "update version 1.2.3", ok: false},
+	}
+	for _, test := range tt {
+		t.Run(test.message, func(t *testing.T) {
+			commit, ok := ParseConventionalCommit(test.message)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, commit)
+		})
+	}
+}
+
+func TestConventionalCommitBump(t *testing.T) {
+	assert.Equal(t, BumpNone, ConventionalCommitBump([]string{"docs: typo", "update version 1.2.3"}))
+	assert.Equal(t, BumpPatch, ConventionalCommitBump([]string{"docs: typo", "fix: crash"}))
+	assert.Equal(t, BumpMinor, ConventionalCommitBump([]string{"fix: crash", "feat: login", "chore: deps"}))
+	assert.Equal(t, BumpMajor, ConventionalCommitBump([]string{"feat: login", "chore!: drop go 1.17"}))
+}
+
+func TestBumpVersion(t *testing.T) {
+	tt := []struct {
+		version  string
+		bump     Bump
+		expected string
+	}{
+		{version: "1.2.3", bump: BumpPatch, expected: "1.2.4"},
+		{version: "1.2.3", bump: BumpMinor, expected: "1.3.0"},
+		{version: "1.2.3", bump: BumpMajor, expected: "2.0.0"},
+		{version: "1.2.3", bump: BumpNone, expected: "1.2.3"},
+		{version: "1.2.3-SNAPSHOT", bump: BumpPatch, expected: "1.2.4"},
+		{version: "v0.1", bump: BumpMinor, expected: "0.2.0"},
+	}
+	for _, test := range tt {
+		t.Run(test.version+" "+test.bump.String(), func(t *testing.T) {
+			version, err := BumpVersion(test.version, test.bump)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+		})
+	}
+
+	_, err := BumpVersion("20200101", BumpPatch)
+	assert.NoError(t, err)
+	_, err = BumpVersion("latest", BumpPatch)
+	assert.EqualError(t, err, "version 'latest' is not a semantic version: Invalid Semantic Version")
+}
+
+func TestChangelog(t *testing.T) {
+	messages := []string{
+		"feat(api)!: remove v1 endpoints",
+		"fix: handle empty response",
+		"docs: update readme",
+		"feat: add export",
+		"Merge branch 'main'",
+	}
+
+	changelog := Changelog("2.0.0", messages, time.Date(2022, 10, 3, 12, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, `## 2.0.0 (2022-10-03)
+
+### Breaking Changes
+
+* **api:** remove v1 endpoints
+
+### Features
+
+* add export
+
+### Bug Fixes
+
+* handle empty response
+`, changelog)
+}
@@ -0,0 +1,131 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MSBuild defines an artifact using a .NET project file (*.csproj, Directory.Build.props) or a NuGet *.nuspec file for versioning.
+// The file is updated via text replacement in order to keep comments and formatting.
+type MSBuild struct {
+	path string
+	// parentElement is the element containing the project level properties, e.g. elements named Version within a PackageReference refer to dependencies
+	parentElement string
+	// versionElements are the elements containing the version, the first one present is used
+	versionElements []string
+	// nameElements are the elements containing the package name, the first one present is used
+	nameElements []string
+	content      string
+	readFile     func(string) ([]byte, error)
+	writeFile    func(string, []byte, os.FileMode) error
+}
+
+func newMSBuild(path string) *MSBuild {
+	if strings.EqualFold(filepath.Ext(path), ".nuspec") {
+		return &MSBuild{path: path, parentElement: "metadata", versionElements: []string{"version"}, nameElements: []string{"id"}}
+	}
+	return &MSBuild{path: path, parentElement: "PropertyGroup", versionElements: []string{"Version", "VersionPrefix"}, nameElements: []string{"PackageId", "AssemblyName"}}
+}
+
+func xmlElement(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(<` + regexp.QuoteMeta(name) + `(?:\s[^>]*)?>)\s*([^<]*?)\s*(</` + regexp.QuoteMeta(name) + `>)`)
+}
+
+func xmlSection(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?s)<` + regexp.QuoteMeta(name) + `(?:\s[^>]*)?>.*?</` + regexp.QuoteMeta(name) + `>`)
+}
+
+func (m *MSBuild) init() error {
+	if m.readFile == nil {
+		m.readFile = ioutil.ReadFile
+	}
+	if m.writeFile == nil {
+		m.writeFile = ioutil.WriteFile
+	}
+	if len(m.content) == 0 {
+		content, err := m.readFile(m.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", m.path)
+		}
+		m.content = string(content)
+	}
+	return nil
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (m *MSBuild) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the current version of the artifact with a .NET or NuGet build descriptor
+func (m *MSBuild) GetVersion() (string, error) {
+	if err := m.init(); err != nil {
+		return "", err
+	}
+	location := m.firstElement(m.versionElements)
+	if location == nil {
+		return "", fmt.Errorf("no version found in '%v', expected one of the elements %v", m.path, strings.Join(m.versionElements, ", "))
+	}
+	return m.content[location[4]:location[5]], nil
+}
+
+// SetVersion updates the version of the artifact with a .NET or NuGet build descriptor
+func (m *MSBuild) SetVersion(version string) error {
+	if err := m.init(); err != nil {
+		return err
+	}
+	location := m.firstElement(m.versionElements)
+	if location == nil {
+		return fmt.Errorf("no version found in '%v', expected one of the elements %v", m.path, strings.Join(m.versionElements, ", "))
+	}
+	m.content = m.content[:location[4]] + version + m.content[location[5]:]
+
+	err := m.writeFile(m.path, []byte(m.content), 0700)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", m.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates, without package name the name of the project file is used
+func (m *MSBuild) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	projectVersion, err := m.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	if location := m.firstElement(m.nameElements); location != nil {
+		result.ArtifactID = m.content[location[4]:location[5]]
+	} else if ext := filepath.Ext(m.path); strings.EqualFold(ext, ".csproj") {
+		result.ArtifactID = strings.TrimSuffix(filepath.Base(m.path), ext)
+	}
+	result.Packaging = "nupkg"
+	result.Version = projectVersion
+	return result, nil
+}
+
+// firstElement returns the submatch indices of the first of the elements present within the parent element
+func (m *MSBuild) firstElement(elements []string) []int {
+	sections := xmlSection(m.parentElement).FindAllStringIndex(m.content, -1)
+	for _, element := range elements {
+		for _, section := range sections {
+			location := xmlElement(element).FindStringSubmatchIndex(m.content[section[0]:section[1]])
+			if location == nil {
+				continue
+			}
+			for i := range location {
+				if location[i] >= 0 {
+					location[i] += section[0]
+				}
+			}
+			return location
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csproj = `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+    <!-- maintained by the pipeline -->
+    <Version>1.4.2</Version>
+  </PropertyGroup>
+</Project>
+`
+
+const csprojWithPackageReferences = `<Project Sdk="Microsoft.NET.Sdk">
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json">
+      <Version>13.0.1</Version>
+    </PackageReference>
+  </ItemGroup>
+  <PropertyGroup>
+    <Version>1.4.2</Version>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Serilog">
+      <Version>2.12.0</Version>
+    </PackageReference>
+  </ItemGroup>
+</Project>
+`
+
+const nuspec = `<?xml version="1.0" encoding="utf-8"?>
+<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+  <metadata>
+    <id>Acme.Logging</id>
+    <version>0.9.0</version>
+  </metadata>
+</package>
+`
+
+func TestMSBuildGetVersion(t *testing.T) {
+	t.Run("success case - csproj", func(t *testing.T) {
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(csproj), nil }
+		version, err := project.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.4.2", version)
+	})
+
+	t.Run("success case - package references", func(t *testing.T) {
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(csprojWithPackageReferences), nil }
+		version, err := project.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.4.2", version)
+	})
+
+	t.Run("error case - only package references", func(t *testing.T) {
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) {
+			return []byte(`<Project><ItemGroup><PackageReference Include="Serilog"><Version>2.12.0</Version></PackageReference></ItemGroup></Project>`), nil
+		}
+		_, err := project.GetVersion()
+		assert.EqualError(t, err, "no version found in 'App.csproj', expected one of the elements Version, VersionPrefix")
+	})
+
+	t.Run("success case - VersionPrefix", func(t *testing.T) {
+		project := newMSBuild("Directory.Build.props")
+		project.readFile = func(filename string) ([]byte, error) {
+			return []byte("<Project><PropertyGroup><VersionPrefix> 2.0.0 </VersionPrefix></PropertyGroup></Project>"), nil
+		}
+		version, err := project.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("success case - nuspec", func(t *testing.T) {
+		project := newMSBuild("Acme.Logging.nuspec")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(nuspec), nil }
+		version, err := project.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "0.9.0", version)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte("<Project></Project>"), nil }
+		_, err := project.GetVersion()
+		assert.EqualError(t, err, "no version found in 'App.csproj', expected one of the elements Version, VersionPrefix")
+	})
+
+	t.Run("error case - read error", func(t *testing.T) {
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte{}, fmt.Errorf("read error") }
+		_, err := project.GetVersion()
+		assert.EqualError(t, err, "failed to read file 'App.csproj': read error")
+	})
+}
+
+func TestMSBuildSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(csproj), nil }
+		project.writeFile = func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil }
+		err := project.SetVersion("1.5.0")
+		assert.NoError(t, err)
+		assert.Equal(t, `<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+    <!-- maintained by the pipeline -->
+    <Version>1.5.0</Version>
+  </PropertyGroup>
+</Project>
+`, string(content))
+	})
+
+	t.Run("success case - package references", func(t *testing.T) {
+		var content []byte
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(csprojWithPackageReferences), nil }
+		project.writeFile = func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil }
+		err := project.SetVersion("1.5.0")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(csprojWithPackageReferences, "<Version>1.4.2</Version>", "<Version>1.5.0</Version>", 1), string(content))
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		project := newMSBuild("App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(csproj), nil }
+		project.writeFile = func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") }
+		err := project.SetVersion("1.5.0")
+		assert.EqualError(t, err, "failed to write file 'App.csproj': write error")
+	})
+}
+
+func TestMSBuildGetCoordinates(t *testing.T) {
+	t.Run("project file name", func(t *testing.T) {
+		project := newMSBuild("src/App.csproj")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(csproj), nil }
+		coordinates, err := project.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "App", Version: "1.4.2", Packaging: "nupkg"}, coordinates)
+	})
+
+	t.Run("package id", func(t *testing.T) {
+		project := newMSBuild("Acme.Logging.nuspec")
+		project.readFile = func(filename string) ([]byte, error) { return []byte(nuspec), nil }
+		coordinates, err := project.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{ArtifactID: "Acme.Logging", Version: "0.9.0", Packaging: "nupkg"}, coordinates)
+	})
+}
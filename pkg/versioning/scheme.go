@@ -0,0 +1,431 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+)
+
+// Scheme defines how automatic versions are created for a versioning scheme and how versions are ordered
+type Scheme interface {
+	// Template returns the template for an automatic version based on .Version, .Timestamp and .CommitID
+	Template() string
+	// Compare returns a negative number, zero or a positive number in case v1 is lower than, equal to or greater than v2
+	Compare(v1, v2 string) (int, error)
+}
+
+var (
+	schemesMutex sync.RWMutex
+	schemes      = map[string]Scheme{
+		"calver":  calverScheme{},
+		"docker":  dockerScheme{},
+		"maven":   mavenScheme{},
+		"pep440":  pep440Scheme{},
+		"semver2": semverScheme{},
+	}
+)
+
+// RegisterScheme adds a versioning scheme to the registry, an existing scheme with the same name is replaced
+func RegisterScheme(name string, scheme Scheme) {
+	schemesMutex.Lock()
+	defer schemesMutex.Unlock()
+	schemes[name] = scheme
+}
+
+// GetScheme returns the versioning scheme registered with the name
+func GetScheme(name string) (Scheme, error) {
+	schemesMutex.RLock()
+	defer schemesMutex.RUnlock()
+	scheme, ok := schemes[name]
+	if !ok {
+		return nil, fmt.Errorf("versioning scheme '%v' not supported", name)
+	}
+	return scheme, nil
+}
+
+// LatestVersion returns the highest of the versions according to the scheme, versions not following the scheme are ignored
+func LatestVersion(scheme Scheme, versions []string) string {
+	latest := ""
+	for _, version := range versions {
+		if _, err := scheme.Compare(version, version); err != nil {
+			continue
+		}
+		if len(latest) == 0 {
+			latest = version
+			continue
+		}
+		if result, _ := scheme.Compare(version, latest); result > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// ValidateVersionIncrease checks that the new version is strictly greater than the last released version, any version is valid without released version
+func ValidateVersionIncrease(scheme Scheme, lastVersion, newVersion string) error {
+	if len(lastVersion) == 0 {
+		return nil
+	}
+	result, err := scheme.Compare(newVersion, lastVersion)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare versions")
+	}
+	if result <= 0 {
+		return fmt.Errorf("new version '%v' is not greater than last released version '%v'", newVersion, lastVersion)
+	}
+	return nil
+}
+
+// semverScheme orders versions according to https://semver.org/spec/v2.0.0.html
+type semverScheme struct{}
+
+func (semverScheme) Template() string {
+	return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}"
+}
+
+func (semverScheme) Compare(v1, v2 string) (int, error) {
+	version1, err := semver.NewVersion(v1)
+	if err != nil {
+		return 0, errors.Wrapf(err, "version '%v' is not a semantic version", v1)
+	}
+	version2, err := semver.NewVersion(v2)
+	if err != nil {
+		return 0, errors.Wrapf(err, "version '%v' is not a semantic version", v2)
+	}
+	return version1.Compare(version2), nil
+}
+
+// mavenScheme orders versions similar to Maven's ComparableVersion, i.e. considering qualifiers like alpha, rc, SNAPSHOT or sp,
+// see https://maven.apache.org/ref/current/maven-artifact/apidocs/org/apache/maven/artifact/versioning/ComparableVersion.html
+type mavenScheme struct{}
+
+func (mavenScheme) Template() string {
+	// according to https://www.mojohaus.org/versions-maven-plugin/version-rules.html
+	return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}_{{.CommitID}}{{end}}{{end}}"
+}
+
+func (mavenScheme) Compare(v1, v2 string) (int, error) {
+	if len(strings.TrimSpace(v1)) == 0 || len(strings.TrimSpace(v2)) == 0 {
+		return 0, fmt.Errorf("empty version cannot be compared")
+	}
+	return compareItems(mavenItems(v1), mavenItems(v2)), nil
+}
+
+var mavenQualifiers = map[string]int{
+	"alpha": 0, "a": 0,
+	"beta": 1, "b": 1,
+	"milestone": 2, "m": 2,
+	"rc": 3, "cr": 3,
+	"snapshot": 4,
+	"":         5, "ga": 5, "final": 5, "release": 5,
+	"sp": 6,
+}
+
+// mavenItem is either a number or a qualifier of a Maven version
+type mavenItem struct {
+	number    int64
+	qualifier string
+	isNumber  bool
+}
+
+var mavenItemPattern = regexp.MustCompile(`\d+|[^\d.\-_+]+`)
+
+func mavenItems(version string) []mavenItem {
+	items := []mavenItem{}
+	for _, token := range mavenItemPattern.FindAllString(strings.ToLower(version), -1) {
+		if number, err := strconv.ParseInt(token, 10, 64); err == nil {
+			items = append(items, mavenItem{number: number, isNumber: true})
+			continue
+		}
+		items = append(items, mavenItem{qualifier: token})
+	}
+	// trailing zeros and release qualifiers are not relevant, e.g. 1.0.0 equals 1 and 1-final
+	for len(items) > 0 && items[len(items)-1].isNull() {
+		items = items[:len(items)-1]
+	}
+	return items
+}
+
+func (i mavenItem) isNull() bool {
+	if i.isNumber {
+		return i.number == 0
+	}
+	return qualifierRank(i.qualifier) == mavenQualifiers[""]
+}
+
+func qualifierRank(qualifier string) int {
+	if rank, ok := mavenQualifiers[qualifier]; ok {
+		return rank
+	}
+	// unknown qualifiers are considered newer than the known ones
+	return len(mavenQualifiers)
+}
+
+func compareItems(items1, items2 []mavenItem) int {
+	for i := 0; i < len(items1) || i < len(items2); i++ {
+		item1, item2 := mavenItem{isNumber: true}, mavenItem{isNumber: true}
+		if i < len(items1) {
+			item1 = items1[i]
+		} else if !items2[i].isNumber {
+			item1 = mavenItem{}
+		}
+		if i < len(items2) {
+			item2 = items2[i]
+		} else if !items1[i].isNumber {
+			item2 = mavenItem{}
+		}
+		if result := item1.compare(item2); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func (i mavenItem) compare(other mavenItem) int {
+	switch {
+	case i.isNumber && other.isNumber:
+		return compareInt(i.number, other.number)
+	case i.isNumber:
+		// a number is newer than a qualifier, e.g. 1.1 > 1-sp
+		return 1
+	case other.isNumber:
+		return -1
+	}
+	if result := compareInt(int64(qualifierRank(i.qualifier)), int64(qualifierRank(other.qualifier))); result != 0 {
+		return result
+	}
+	return strings.Compare(i.qualifier, other.qualifier)
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// dockerScheme orders tags like Maven versions since Docker does not define an order for tags
+type dockerScheme struct {
+	mavenScheme
+}
+
+func (dockerScheme) Template() string {
+	// from Docker documentation:
+	// A tag name must be valid ASCII and may contain lowercase and uppercase letters, digits, underscores, periods and dashes.
+	// A tag name may not start with a period or a dash and may contain a maximum of 128 characters.
+	return "{{.Version}}{{if .Timestamp}}-{{.Timestamp}}{{if .CommitID}}-{{.CommitID}}{{end}}{{end}}"
+}
+
+// pep440Scheme orders versions according to https://www.python.org/dev/peps/pep-0440/
+type pep440Scheme struct{}
+
+func (pep440Scheme) Template() string {
+	return "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}"
+}
+
+func (pep440Scheme) Compare(v1, v2 string) (int, error) {
+	version1, err := parsePEP440(v1)
+	if err != nil {
+		return 0, err
+	}
+	version2, err := parsePEP440(v2)
+	if err != nil {
+		return 0, err
+	}
+	return version1.compare(version2), nil
+}
+
+// pattern taken from https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+var pep440Pattern = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+var pep440PreReleases = map[string]int64{"a": 0, "alpha": 0, "b": 1, "beta": 1, "c": 2, "rc": 2, "pre": 2, "preview": 2}
+
+type pep440Version struct {
+	epoch   int64
+	release []int64
+	// pre, post and dev are nil if not present
+	pre   []int64
+	post  *int64
+	dev   *int64
+	local []string
+}
+
+func parsePEP440(version string) (pep440Version, error) {
+	match := pep440Pattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if match == nil {
+		return pep440Version{}, fmt.Errorf("version '%v' is not a PEP 440 version", version)
+	}
+	result := pep440Version{epoch: atoi(match[1])}
+	for _, part := range strings.Split(match[2], ".") {
+		result.release = append(result.release, atoi(part))
+	}
+	if len(match[3]) > 0 {
+		result.pre = []int64{pep440PreReleases[match[3]], atoi(match[4])}
+	}
+	if len(match[5]) > 0 || len(match[6]) > 0 {
+		post := atoi(match[5] + match[7])
+		result.post = &post
+	}
+	if len(match[8]) > 0 {
+		dev := atoi(match[9])
+		result.dev = &dev
+	}
+	if len(match[10]) > 0 {
+		result.local = strings.FieldsFunc(match[10], func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	}
+	return result, nil
+}
+
+func (v pep440Version) compare(other pep440Version) int {
+	if result := compareInt(v.epoch, other.epoch); result != 0 {
+		return result
+	}
+	if result := compareNumbers(v.release, other.release); result != 0 {
+		return result
+	}
+	if result := compareNumbers(v.preKey(), other.preKey()); result != 0 {
+		return result
+	}
+	if result := compareOptional(v.post, other.post, -1); result != 0 {
+		return result
+	}
+	if result := compareOptional(v.dev, other.dev, 1); result != 0 {
+		return result
+	}
+	return compareLocal(v.local, other.local)
+}
+
+// preKey orders developmental releases before pre-releases and pre-releases before final releases, e.g. 1.0.dev1 < 1.0a1 < 1.0
+func (v pep440Version) preKey() []int64 {
+	switch {
+	case v.pre != nil:
+		return v.pre
+	case v.post == nil && v.dev != nil:
+		return []int64{-1}
+	}
+	return []int64{int64(len(pep440PreReleases))}
+}
+
+// compareNumbers compares the numbers of the slices, missing numbers are considered to be 0, e.g. 1.0 equals 1.0.0
+func compareNumbers(numbers1, numbers2 []int64) int {
+	for i := 0; i < len(numbers1) || i < len(numbers2); i++ {
+		var number1, number2 int64
+		if i < len(numbers1) {
+			number1 = numbers1[i]
+		}
+		if i < len(numbers2) {
+			number2 = numbers2[i]
+		}
+		if result := compareInt(number1, number2); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// compareOptional compares optional numbers, missing is the result for a missing number, e.g. -1 in case a missing number is lower than any number
+func compareOptional(number1, number2 *int64, missing int) int {
+	switch {
+	case number1 == nil && number2 == nil:
+		return 0
+	case number1 == nil:
+		return missing
+	case number2 == nil:
+		return -missing
+	}
+	return compareInt(*number1, *number2)
+}
+
+// compareLocal compares local version labels, numeric segments are greater than alphanumeric ones
+func compareLocal(local1, local2 []string) int {
+	for i := 0; i < len(local1) && i < len(local2); i++ {
+		number1, err1 := strconv.ParseInt(local1[i], 10, 64)
+		number2, err2 := strconv.ParseInt(local2[i], 10, 64)
+		var result int
+		switch {
+		case err1 == nil && err2 == nil:
+			result = compareInt(number1, number2)
+		case err1 == nil:
+			result = 1
+		case err2 == nil:
+			result = -1
+		default:
+			result = strings.Compare(local1[i], local2[i])
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return compareInt(int64(len(local1)), int64(len(local2)))
+}
+
+func atoi(number string) int64 {
+	result, _ := strconv.ParseInt(number, 10, 64)
+	return result
+}
+
+// calverScheme orders calendar versions YYYY.MM.DD.N, see https://calver.org
+type calverScheme struct{}
+
+func (calverScheme) Template() string {
+	return "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}"
+}
+
+func (calverScheme) Compare(v1, v2 string) (int, error) {
+	numbers1, err := calendarVersionNumbers(v1)
+	if err != nil {
+		return 0, err
+	}
+	numbers2, err := calendarVersionNumbers(v2)
+	if err != nil {
+		return 0, err
+	}
+	return compareNumbers(numbers1, numbers2), nil
+}
+
+var calendarVersionPattern = regexp.MustCompile(`^\d{4}(\.\d+)+$`)
+
+func calendarVersionNumbers(version string) ([]int64, error) {
+	if !calendarVersionPattern.MatchString(version) {
+		return nil, fmt.Errorf("version '%v' is not a calendar version", version)
+	}
+	numbers := []int64{}
+	for _, part := range strings.Split(version, ".") {
+		numbers = append(numbers, atoi(part))
+	}
+	return numbers, nil
+}
+
+// NextCalendarVersion returns the calendar version YYYY.MM.DD.N of the date with the build number N following the highest
+// build number released on that date. For semantic versions YYYY.MMDD.N is used since these are limited to three numbers.
+func NextCalendarVersion(date time.Time, released []string, versioningScheme string) string {
+	prefix := date.Format("2006.01.02") + "."
+	if versioningScheme == "semver2" {
+		prefix = fmt.Sprintf("%d.%d.", date.Year(), int(date.Month())*100+date.Day())
+	}
+	buildNumbers := []int64{0}
+	for _, version := range released {
+		if !strings.HasPrefix(version, prefix) {
+			continue
+		}
+		if buildNumber, err := strconv.ParseInt(strings.TrimPrefix(version, prefix), 10, 64); err == nil {
+			buildNumbers = append(buildNumbers, buildNumber)
+		}
+	}
+	sort.Slice(buildNumbers, func(i, j int) bool { return buildNumbers[i] < buildNumbers[j] })
+	return fmt.Sprintf("%v%d", prefix, buildNumbers[len(buildNumbers)-1]+1)
+}
@@ -0,0 +1,117 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetScheme(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		scheme, err := GetScheme("pep440")
+		assert.NoError(t, err)
+		assert.Equal(t, "{{.Version}}{{if .Timestamp}}.{{.Timestamp}}{{if .CommitID}}+{{.CommitID}}{{end}}{{end}}", scheme.Template())
+	})
+
+	t.Run("registered scheme", func(t *testing.T) {
+		RegisterScheme("test", calverScheme{})
+		defer func() { delete(schemes, "test") }()
+		scheme, err := GetScheme("test")
+		assert.NoError(t, err)
+		assert.Equal(t, calverScheme{}, scheme)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		_, err := GetScheme("notSupported")
+		assert.EqualError(t, err, "versioning scheme 'notSupported' not supported")
+	})
+}
+
+func TestSchemeCompare(t *testing.T) {
+	tt := []struct {
+		scheme  string
+		lower   string
+		higher  string
+		equalTo string
+	}{
+		{scheme: "semver2", lower: "1.2.3-rc.1", higher: "1.2.3"},
+		{scheme: "semver2", lower: "1.9.0", higher: "1.10.0"},
+		{scheme: "semver2", lower: "1.2.3-20200101000000+abc", higher: "1.2.3-20200102000000+abc"},
+		{scheme: "maven", lower: "1.0-SNAPSHOT", higher: "1.0"},
+		{scheme: "maven", lower: "1.0-alpha-1", higher: "1.0-beta-1"},
+		{scheme: "maven", lower: "1.0-rc1", higher: "1.0-SNAPSHOT"},
+		{scheme: "maven", lower: "1.0", higher: "1.0-sp1"},
+		{scheme: "maven", lower: "1.0-sp1", higher: "1.0.1"},
+		{scheme: "maven", lower: "1.9", higher: "1.10"},
+		{scheme: "maven", lower: "1.2.3-20200101000000_abc", higher: "1.2.3-20200102000000_abc"},
+		{scheme: "maven", equalTo: "1.0", lower: "1.0.0", higher: "1.0.0"},
+		{scheme: "maven", equalTo: "1-final", lower: "1", higher: "1"},
+		{scheme: "docker", lower: "1.2.3-20200101000000", higher: "1.2.4"},
+		{scheme: "pep440", lower: "1.0.dev1", higher: "1.0a1"},
+		{scheme: "pep440", lower: "1.0a1", higher: "1.0b2"},
+		{scheme: "pep440", lower: "1.0rc1", higher: "1.0"},
+		{scheme: "pep440", lower: "1.0", higher: "1.0.post1"},
+		{scheme: "pep440", lower: "1.0.post1.dev1", higher: "1.0.post1"},
+		{scheme: "pep440", lower: "1.0+abc", higher: "1.0+5"},
+		{scheme: "pep440", lower: "2.0", higher: "1!1.0"},
+		{scheme: "pep440", lower: "1.2.3.20200101000000+abc", higher: "1.2.3.20200102000000"},
+		{scheme: "pep440", equalTo: "1.0-RC1", lower: "1.0rc1", higher: "1.0rc1"},
+		{scheme: "calver", lower: "2022.09.30.3", higher: "2022.10.01.1"},
+		{scheme: "calver", lower: "2022.10.01.9", higher: "2022.10.01.10"},
+	}
+	for _, test := range tt {
+		t.Run(test.scheme+" "+test.lower+" "+test.higher, func(t *testing.T) {
+			scheme, err := GetScheme(test.scheme)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if len(test.equalTo) > 0 {
+				result, err := scheme.Compare(test.equalTo, test.lower)
+				assert.NoError(t, err)
+				assert.Equal(t, 0, result)
+				return
+			}
+			result, err := scheme.Compare(test.lower, test.higher)
+			assert.NoError(t, err)
+			assert.Negative(t, result)
+			result, err = scheme.Compare(test.higher, test.lower)
+			assert.NoError(t, err)
+			assert.Positive(t, result)
+		})
+	}
+
+	t.Run("error cases", func(t *testing.T) {
+		_, err := semverScheme{}.Compare("1.0.0", "latest")
+		assert.EqualError(t, err, "version 'latest' is not a semantic version: Invalid Semantic Version")
+		_, err = pep440Scheme{}.Compare("1.0~1", "1.0")
+		assert.EqualError(t, err, "version '1.0~1' is not a PEP 440 version")
+		_, err = calverScheme{}.Compare("22.1", "2022.1")
+		assert.EqualError(t, err, "version '22.1' is not a calendar version")
+	})
+}
+
+func TestLatestVersion(t *testing.T) {
+	assert.Equal(t, "1.10.0", LatestVersion(semverScheme{}, []string{"1.9.0", "next", "1.10.0", "1.10.0-rc.1"}))
+	assert.Equal(t, "", LatestVersion(semverScheme{}, []string{"next"}))
+	assert.Equal(t, "2.0", LatestVersion(pep440Scheme{}, []string{"2.0rc1", "2.0", "1.9.post3"}))
+}
+
+func TestValidateVersionIncrease(t *testing.T) {
+	assert.NoError(t, ValidateVersionIncrease(mavenScheme{}, "", "1.0.0"))
+	assert.NoError(t, ValidateVersionIncrease(mavenScheme{}, "1.0.0-SNAPSHOT", "1.0.0"))
+	assert.EqualError(t, ValidateVersionIncrease(mavenScheme{}, "1.0.0", "1.0.0"), "new version '1.0.0' is not greater than last released version '1.0.0'")
+	assert.EqualError(t, ValidateVersionIncrease(pep440Scheme{}, "1.0", "1.0rc1"), "new version '1.0rc1' is not greater than last released version '1.0'")
+	assert.EqualError(t, ValidateVersionIncrease(semverScheme{}, "1.0.0", "next"), "failed to compare versions: version 'next' is not a semantic version: Invalid Semantic Version")
+}
+
+func TestNextCalendarVersion(t *testing.T) {
+	date := time.Date(2022, 10, 3, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "2022.10.03.1", NextCalendarVersion(date, nil, "maven"))
+	assert.Equal(t, "2022.10.03.3", NextCalendarVersion(date, []string{"2022.10.02.5", "2022.10.03.2", "2022.10.03.1"}, "pep440"))
+	assert.Equal(t, "2022.1003.2", NextCalendarVersion(date, []string{"2022.1003.1", "2022.10.03.7"}, "semver2"))
+}
@@ -0,0 +1,152 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TOMLfile defines an artifact using a TOML file for versioning, e.g. Cargo.toml or pyproject.toml.
+// The file is updated line by line in order to keep comments and formatting.
+type TOMLfile struct {
+	path string
+	// sections are the tables containing name and version, the first one containing a version is used,
+	// e.g. "project" (PEP 621) and "tool.poetry" (Poetry) for pyproject.toml
+	sections         []string
+	versioningScheme string
+	lines            []string
+	readFile         func(string) ([]byte, error)
+	writeFile        func(string, []byte, os.FileMode) error
+}
+
+// newCargoToml returns the artifact of a Rust Cargo.toml, the version of a workspace is supported as well
+func newCargoToml(path string) *TOMLfile {
+	return &TOMLfile{path: path, sections: []string{"package", "workspace.package"}}
+}
+
+// newPyprojectToml returns the artifact of a Python pyproject.toml according to PEP 621 or Poetry
+func newPyprojectToml(path string) *TOMLfile {
+	return &TOMLfile{path: path, sections: []string{"project", "tool.poetry"}, versioningScheme: "pep440"}
+}
+
+var tomlTableHeader = regexp.MustCompile(`^\s*\[([^\[\]]+)\]\s*(#.*)?$`)
+
+func tomlKeyValue(key string) *regexp.Regexp {
+	return regexp.MustCompile(`^(\s*` + regexp.QuoteMeta(key) + `\s*=\s*)(["'])([^"']*)(["'])(.*)$`)
+}
+
+func (t *TOMLfile) init() error {
+	if t.readFile == nil {
+		t.readFile = ioutil.ReadFile
+	}
+	if t.writeFile == nil {
+		t.writeFile = ioutil.WriteFile
+	}
+	if t.lines == nil {
+		content, err := t.readFile(t.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", t.path)
+		}
+		t.lines = strings.Split(string(content), "\n")
+	}
+	return nil
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (t *TOMLfile) VersioningScheme() string {
+	if len(t.versioningScheme) == 0 {
+		return "semver2"
+	}
+	return t.versioningScheme
+}
+
+// GetVersion returns the current version of the artifact with a TOML-based build descriptor
+func (t *TOMLfile) GetVersion() (string, error) {
+	if err := t.init(); err != nil {
+		return "", err
+	}
+	_, line, ok := t.find("version")
+	if !ok {
+		return "", fmt.Errorf("no version found in section %v of '%v'", t.describeSections(), t.path)
+	}
+	return tomlKeyValue("version").FindStringSubmatch(t.lines[line])[3], nil
+}
+
+// SetVersion updates the version of the artifact with a TOML-based build descriptor
+func (t *TOMLfile) SetVersion(version string) error {
+	if err := t.init(); err != nil {
+		return err
+	}
+	_, line, ok := t.find("version")
+	if !ok {
+		return fmt.Errorf("no version found in section %v of '%v'", t.describeSections(), t.path)
+	}
+	t.lines[line] = tomlKeyValue("version").ReplaceAllString(t.lines[line], "${1}${2}"+strings.ReplaceAll(version, "$", "$$")+"${4}${5}")
+
+	err := t.writeFile(t.path, []byte(strings.Join(t.lines, "\n")), 0700)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", t.path)
+	}
+	return nil
+}
+
+// GetCoordinates returns the coordinates
+func (t *TOMLfile) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	projectVersion, err := t.GetVersion()
+	if err != nil {
+		return result, err
+	}
+	section, _, _ := t.find("version")
+	if name, ok := t.value(section, "name"); ok {
+		result.ArtifactID = name
+	}
+	result.Version = projectVersion
+	return result, nil
+}
+
+// find returns the section and the line of the key in the first section containing it
+func (t *TOMLfile) find(key string) (string, int, bool) {
+	for _, section := range t.sections {
+		if line, ok := t.line(section, key); ok {
+			return section, line, true
+		}
+	}
+	return "", 0, false
+}
+
+func (t *TOMLfile) value(section, key string) (string, bool) {
+	line, ok := t.line(section, key)
+	if !ok {
+		return "", false
+	}
+	return tomlKeyValue(key).FindStringSubmatch(t.lines[line])[3], true
+}
+
+// line returns the index of the line assigning a string to the key within the table
+func (t *TOMLfile) line(section, key string) (int, bool) {
+	keyValue := tomlKeyValue(key)
+	current := ""
+	for i, line := range t.lines {
+		if header := tomlTableHeader.FindStringSubmatch(line); header != nil {
+			current = strings.TrimSpace(header[1])
+			continue
+		}
+		if current == section && keyValue.MatchString(line) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (t *TOMLfile) describeSections() string {
+	sections := make([]string, 0, len(t.sections))
+	for _, section := range t.sections {
+		sections = append(sections, fmt.Sprintf("[%v]", section))
+	}
+	return strings.Join(sections, " or ")
+}
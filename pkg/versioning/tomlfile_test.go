@@ -0,0 +1,116 @@
+//go:build unit
+// +build unit
+
+package versioning
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const cargoToml = `[package]
+name = "my-crate" # the crate
+version = "0.3.1"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+`
+
+const poetryToml = `[build-system]
+requires = ["poetry-core"]
+
+[tool.poetry]
+name = 'my-package'
+version = '1.0.0a1'
+`
+
+func TestTOMLfileGetVersion(t *testing.T) {
+	t.Run("success case - Cargo", func(t *testing.T) {
+		cargo := newCargoToml("Cargo.toml")
+		cargo.readFile = func(filename string) ([]byte, error) { return []byte(cargoToml), nil }
+		version, err := cargo.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "0.3.1", version)
+	})
+
+	t.Run("success case - Cargo workspace", func(t *testing.T) {
+		cargo := newCargoToml("Cargo.toml")
+		cargo.readFile = func(filename string) ([]byte, error) {
+			return []byte("[workspace]\nmembers = [\"a\", \"b\"]\n\n[workspace.package]\nversion = \"2.1.0\"\n"), nil
+		}
+		version, err := cargo.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "2.1.0", version)
+	})
+
+	t.Run("success case - Poetry", func(t *testing.T) {
+		pyproject := newPyprojectToml("pyproject.toml")
+		pyproject.readFile = func(filename string) ([]byte, error) { return []byte(poetryToml), nil }
+		version, err := pyproject.GetVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0a1", version)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		cargo := newCargoToml("Cargo.toml")
+		cargo.readFile = func(filename string) ([]byte, error) { return []byte("[dependencies]\nversion = \"1.0\"\n"), nil }
+		_, err := cargo.GetVersion()
+		assert.EqualError(t, err, "no version found in section [package] or [workspace.package] of 'Cargo.toml'")
+	})
+
+	t.Run("error case - read error", func(t *testing.T) {
+		cargo := newCargoToml("Cargo.toml")
+		cargo.readFile = func(filename string) ([]byte, error) { return []byte{}, fmt.Errorf("read error") }
+		_, err := cargo.GetVersion()
+		assert.EqualError(t, err, "failed to read file 'Cargo.toml': read error")
+	})
+}
+
+func TestTOMLfileSetVersion(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		var content []byte
+		cargo := newCargoToml("Cargo.toml")
+		cargo.readFile = func(filename string) ([]byte, error) { return []byte(cargoToml), nil }
+		cargo.writeFile = func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil }
+		err := cargo.SetVersion("0.4.0")
+		assert.NoError(t, err)
+		assert.Equal(t, `[package]
+name = "my-crate" # the crate
+version = "0.4.0"
+edition = "2021"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+`, string(content))
+	})
+
+	t.Run("success case - single quotes", func(t *testing.T) {
+		var content []byte
+		pyproject := newPyprojectToml("pyproject.toml")
+		pyproject.readFile = func(filename string) ([]byte, error) { return []byte(poetryToml), nil }
+		pyproject.writeFile = func(filename string, filecontent []byte, mode os.FileMode) error { content = filecontent; return nil }
+		err := pyproject.SetVersion("1.0.0")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), "version = '1.0.0'\n")
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		cargo := newCargoToml("Cargo.toml")
+		cargo.readFile = func(filename string) ([]byte, error) { return []byte(cargoToml), nil }
+		cargo.writeFile = func(filename string, filecontent []byte, mode os.FileMode) error { return fmt.Errorf("write error") }
+		err := cargo.SetVersion("0.4.0")
+		assert.EqualError(t, err, "failed to write file 'Cargo.toml': write error")
+	})
+}
+
+func TestTOMLfileGetCoordinates(t *testing.T) {
+	pyproject := newPyprojectToml("pyproject.toml")
+	pyproject.readFile = func(filename string) ([]byte, error) { return []byte(poetryToml), nil }
+	coordinates, err := pyproject.GetCoordinates()
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "my-package", Version: "1.0.0a1"}, coordinates)
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/maven"
 )
@@ -76,6 +77,21 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 		fileExists = piperutils.FileExists
 	}
 	switch buildTool {
+	case "cargo":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "Cargo.toml"
+		}
+		artifact = newCargoToml(buildDescriptorFilePath)
+	case "composer":
+		if len(buildDescriptorFilePath) == 0 {
+			buildDescriptorFilePath = "composer.json"
+		}
+		artifact = &Composer{
+			JSONfile: JSONfile{
+				path:         buildDescriptorFilePath,
+				versionField: "version",
+			},
+		}
 	case "custom":
 		var err error
 		artifact, err = customArtifact(buildDescriptorFilePath, opts.VersionField, opts.VersionSection, opts.VersioningScheme)
@@ -90,6 +106,15 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			versionSource:    opts.VersionSource,
 			versioningScheme: opts.VersioningScheme,
 		}
+	case "dotnet":
+		if len(buildDescriptorFilePath) == 0 {
+			var err error
+			buildDescriptorFilePath, err = searchDotnetDescriptor(utils)
+			if err != nil {
+				return artifact, err
+			}
+		}
+		artifact = newMSBuild(buildDescriptorFilePath)
 	case "dub":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "dub.json"
@@ -164,11 +189,15 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 	case "pip":
 		if len(buildDescriptorFilePath) == 0 {
 			var err error
-			buildDescriptorFilePath, err = searchDescriptor([]string{"setup.py", "version.txt", "VERSION"}, fileExists)
+			buildDescriptorFilePath, err = searchDescriptor([]string{"setup.py", "version.txt", "VERSION", "pyproject.toml"}, fileExists)
 			if err != nil {
 				return artifact, err
 			}
 		}
+		if filepath.Base(buildDescriptorFilePath) == "pyproject.toml" {
+			artifact = newPyprojectToml(buildDescriptorFilePath)
+			break
+		}
 		artifact = &Pip{
 			path:       buildDescriptorFilePath,
 			fileExists: fileExists,
@@ -192,6 +221,48 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 	return artifact, nil
 }
 
+// descriptorBuildTools maps the file names of build descriptors to the build tool managing them
+var descriptorBuildTools = map[string]string{
+	"Cargo.toml":            "cargo",
+	"Chart.yaml":            "helm",
+	"Directory.Build.props": "dotnet",
+	"Dockerfile":            "docker",
+	"build.sbt":             "sbt",
+	"composer.json":         "composer",
+	"dub.json":              "dub",
+	"gradle.properties":     "gradle",
+	"mta.yaml":              "mta",
+	"package.json":          "npm",
+	"pom.xml":               "maven",
+	"pyproject.toml":        "pip",
+	"setup.py":              "pip",
+}
+
+// BuildToolForDescriptor returns the build tool of a build descriptor based on its file name, e.g. "maven" for "backend/pom.xml"
+func BuildToolForDescriptor(buildDescriptorFilePath string) (string, bool) {
+	switch filepath.Ext(buildDescriptorFilePath) {
+	case ".csproj", ".nuspec":
+		return "dotnet", true
+	}
+	buildTool, ok := descriptorBuildTools[filepath.Base(buildDescriptorFilePath)]
+	return buildTool, ok
+}
+
+// searchDotnetDescriptor returns Directory.Build.props or the only project file in the current directory
+func searchDotnetDescriptor(utils Utils) (string, error) {
+	if exists, _ := fileExists("Directory.Build.props"); exists {
+		return "Directory.Build.props", nil
+	}
+	projects, err := utils.Glob("*.csproj")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to search for .NET project files")
+	}
+	if len(projects) != 1 {
+		return "", fmt.Errorf("no unique build descriptor found, expected Directory.Build.props or a single *.csproj file but found %v project files", len(projects))
+	}
+	return projects[0], nil
+}
+
 func searchDescriptor(supported []string, existsFunc func(string) (bool, error)) (string, error) {
 	var descriptor string
 	for _, f := range supported {
@@ -226,6 +297,16 @@ func customArtifact(buildDescriptorFilePath, field, section, scheme string) (Art
 			path:         buildDescriptorFilePath,
 			versionField: field,
 		}, nil
+	case ".toml":
+		if filepath.Base(buildDescriptorFilePath) == "Cargo.toml" {
+			return newCargoToml(buildDescriptorFilePath), nil
+		}
+		if len(section) > 0 {
+			return &TOMLfile{path: buildDescriptorFilePath, sections: []string{section}, versioningScheme: scheme}, nil
+		}
+		return newPyprojectToml(buildDescriptorFilePath), nil
+	case ".csproj", ".props", ".nuspec":
+		return newMSBuild(buildDescriptorFilePath), nil
 	case ".txt", "":
 		return &Versionfile{
 			path:             buildDescriptorFilePath,
@@ -1,6 +1,8 @@
 package versioning
 
 import (
+	"sync"
+
 	"github.com/Masterminds/sprig"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -24,19 +26,29 @@ const (
 	VersioningModelMajor      string = "major"
 )
 
+var (
+	versioningModelsMutex sync.RWMutex
+	versioningModels      = map[string]string{
+		VersioningModelFull:       SchemeFullVersion,
+		VersioningModelSemantic:   SchemeSemanticVersion,
+		VersioningModelMajorMinor: SchemeMajorMinorVersion,
+		VersioningModelMajor:      SchemeMajorVersion,
+	}
+)
+
+// RegisterVersioningModel adds a versioning model defined by a template on .Version, sprig functions are available
+func RegisterVersioningModel(model, template string) {
+	versioningModelsMutex.Lock()
+	defer versioningModelsMutex.Unlock()
+	versioningModels[model] = template
+}
+
+// ApplyVersioningModel reduces the project version according to the versioning model, e.g. 1.2.3 to 1 for model 'major'
 func ApplyVersioningModel(model, projectVersion string) string {
-	var versioningScheme string
-
-	switch model {
-	case VersioningModelFull:
-		versioningScheme = SchemeFullVersion
-	case VersioningModelSemantic:
-		versioningScheme = SchemeSemanticVersion
-	case VersioningModelMajorMinor:
-		versioningScheme = SchemeMajorMinorVersion
-	case VersioningModelMajor:
-		versioningScheme = SchemeMajorVersion
-	default:
+	versioningModelsMutex.RLock()
+	versioningScheme, ok := versioningModels[model]
+	versioningModelsMutex.RUnlock()
+	if !ok {
 		log.Entry().Warnf("versioning model not supported: %s", model)
 	}
 
@@ -27,7 +27,10 @@ func Test_applyVersioningModel(t *testing.T) {
 		{"invalid - unknown versioning model", args{"snapshot", "1.2.3-SNAPSHOT"}, ""},
 		{"invalid - incorrect version", args{VersioningModelMajor, ".2.3"}, ""},
 		{"invalid - version to short", args{VersioningModelSemantic, "1.2"}, "1.2.<no value>"},
+		{"registered model", args{"year", "2022.10.03.1"}, "2022"},
 	}
+	RegisterVersioningModel("year", `{{(split "." .Version)._0}}`)
+	defer func() { delete(versioningModels, "year") }()
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := ApplyVersioningModel(tt.args.model, tt.args.version)
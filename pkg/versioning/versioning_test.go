@@ -30,6 +30,63 @@ func (v *versioningMockUtils) DownloadFile(url, filename string, header http.Hea
 }
 
 func TestGetArtifact(t *testing.T) {
+	t.Run("cargo", func(t *testing.T) {
+		cargo, err := GetArtifact("cargo", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := cargo.(*TOMLfile)
+		assert.True(t, ok)
+		assert.Equal(t, "Cargo.toml", theType.path)
+		assert.Equal(t, []string{"package", "workspace.package"}, theType.sections)
+		assert.Equal(t, "semver2", cargo.VersioningScheme())
+	})
+
+	t.Run("composer", func(t *testing.T) {
+		composer, err := GetArtifact("composer", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := composer.(*Composer)
+		assert.True(t, ok)
+		assert.Equal(t, "composer.json", theType.path)
+		assert.Equal(t, "version", theType.versionField)
+		assert.Equal(t, "semver2", composer.VersioningScheme())
+	})
+
+	t.Run("dotnet - Directory.Build.props", func(t *testing.T) {
+		fileExists = func(s string) (bool, error) { return s == "Directory.Build.props", nil }
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, newVersioningMockUtils())
+
+		assert.NoError(t, err)
+
+		theType, ok := dotnet.(*MSBuild)
+		assert.True(t, ok)
+		assert.Equal(t, "Directory.Build.props", theType.path)
+		assert.Equal(t, []string{"Version", "VersionPrefix"}, theType.versionElements)
+		assert.Equal(t, "semver2", dotnet.VersioningScheme())
+	})
+
+	t.Run("dotnet - project file", func(t *testing.T) {
+		fileExists = func(string) (bool, error) { return false, nil }
+		utils := newVersioningMockUtils()
+		utils.AddFile("App.csproj", []byte("<Project></Project>"))
+		dotnet, err := GetArtifact("dotnet", "", &Options{}, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "App.csproj", dotnet.(*MSBuild).path)
+	})
+
+	t.Run("dotnet - error", func(t *testing.T) {
+		fileExists = func(string) (bool, error) { return false, nil }
+		utils := newVersioningMockUtils()
+		utils.AddFile("App.csproj", []byte("<Project></Project>"))
+		utils.AddFile("App.Tests.csproj", []byte("<Project></Project>"))
+		_, err := GetArtifact("dotnet", "", &Options{}, utils)
+
+		assert.EqualError(t, err, "no unique build descriptor found, expected Directory.Build.props or a single *.csproj file but found 2 project files")
+	})
+
 	t.Run("custom", func(t *testing.T) {
 		custom, err := GetArtifact("custom", "test.ini", &Options{VersionField: "theversion", VersionSection: "test"}, nil)
 
@@ -202,7 +259,20 @@ func TestGetArtifact(t *testing.T) {
 		fileExists = func(string) (bool, error) { return false, nil }
 		_, err := GetArtifact("pip", "", &Options{}, nil)
 
-		assert.EqualError(t, err, "no build descriptor available, supported: [setup.py version.txt VERSION]")
+		assert.EqualError(t, err, "no build descriptor available, supported: [setup.py version.txt VERSION pyproject.toml]")
+	})
+
+	t.Run("pip - pyproject.toml", func(t *testing.T) {
+		fileExists = func(s string) (bool, error) { return s == "pyproject.toml", nil }
+		pip, err := GetArtifact("pip", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := pip.(*TOMLfile)
+		assert.True(t, ok)
+		assert.Equal(t, "pyproject.toml", theType.path)
+		assert.Equal(t, []string{"project", "tool.poetry"}, theType.sections)
+		assert.Equal(t, "pep440", pip.VersioningScheme())
 	})
 
 	t.Run("sbt", func(t *testing.T) {
@@ -240,6 +310,11 @@ func TestCustomArtifact(t *testing.T) {
 		{file: "test.json", field: "testField", expected: &JSONfile{path: "test.json", versionField: "testField"}},
 		{file: "test.yaml", field: "testField", expected: &YAMLfile{path: "test.yaml", versionField: "testField"}},
 		{file: "test.yml", field: "testField", expected: &YAMLfile{path: "test.yml", versionField: "testField"}},
+		{file: "Cargo.toml", expected: &TOMLfile{path: "Cargo.toml", sections: []string{"package", "workspace.package"}}},
+		{file: "pyproject.toml", expected: &TOMLfile{path: "pyproject.toml", sections: []string{"project", "tool.poetry"}, versioningScheme: "pep440"}},
+		{file: "test.toml", section: "tool.app", expected: &TOMLfile{path: "test.toml", sections: []string{"tool.app"}}},
+		{file: "App.csproj", expected: &MSBuild{path: "App.csproj", parentElement: "PropertyGroup", versionElements: []string{"Version", "VersionPrefix"}, nameElements: []string{"PackageId", "AssemblyName"}}},
+		{file: "App.nuspec", expected: &MSBuild{path: "App.nuspec", parentElement: "metadata", versionElements: []string{"version"}, nameElements: []string{"id"}}},
 		{file: "test.txt", expected: &Versionfile{path: "test.txt"}},
 		{file: "test", expected: &Versionfile{path: "test"}},
 		{file: "test", scheme: "maven", expected: &Versionfile{path: "test", versioningScheme: "maven"}},
@@ -258,3 +333,20 @@ func TestCustomArtifact(t *testing.T) {
 
 	}
 }
+
+func TestBuildToolForDescriptor(t *testing.T) {
+	buildTool, ok := BuildToolForDescriptor("backend/pom.xml")
+	assert.True(t, ok)
+	assert.Equal(t, "maven", buildTool)
+
+	buildTool, ok = BuildToolForDescriptor("charts/app/Chart.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, "helm", buildTool)
+
+	buildTool, ok = BuildToolForDescriptor("src/App/App.csproj")
+	assert.True(t, ok)
+	assert.Equal(t, "dotnet", buildTool)
+
+	_, ok = BuildToolForDescriptor("ui/version.txt")
+	assert.False(t, ok)
+}
@@ -229,6 +229,42 @@ Link: [%v](%v)`,
 	)
 }
 
+// ToFinding returns the scanner independent representation of the alert
+func (a Alert) ToFinding() format.Finding {
+	severity, _ := format.ParseSeverity(consolidate(a.Vulnerability.Severity, a.Vulnerability.CVSS3Severity, a.Vulnerability.Score, a.Vulnerability.CVSS3Score))
+	packageURL := a.Library.ToPackageUrl().ToString()
+	finding := format.Finding{
+		Scanner:     "Mend",
+		ID:          a.Vulnerability.Name,
+		Title:       a.Title(),
+		Description: a.Vulnerability.Description,
+		Severity:    severity,
+		Score:       consolidateScores(a.Vulnerability.Score, a.Vulnerability.CVSS3Score),
+		CVE:         format.ExtractCVE(a.Vulnerability.Name),
+		PackageURL:  packageURL,
+		AuditState:  format.AuditStateNew,
+		Fingerprint: format.PackageFingerprint(packageURL, a.Vulnerability.Name),
+		Link:        a.Vulnerability.URL,
+	}
+	if len(a.Library.Filename) > 0 {
+		finding.Location = &format.FindingLocation{Path: a.Library.Filename}
+	}
+	if a.Assessment != nil {
+		switch a.Assessment.Status {
+		case format.Relevant:
+			finding.AuditState = format.AuditStateRelevant
+		case format.NotRelevant:
+			finding.AuditState = format.AuditStateNotRelevant
+		case format.InProcess:
+			finding.AuditState = format.AuditStateInProcess
+		}
+		finding.AuditComment = string(a.Assessment.Analysis)
+	} else if a.Status == "IGNORED" {
+		finding.AuditState = format.AuditStateNotRelevant
+	}
+	return finding
+}
+
 func consolidateScores(cvss2score, cvss3score float64) float64 {
 	score := cvss3score
 	if score == 0 {
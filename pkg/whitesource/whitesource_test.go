@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/SAP/jenkins-library/pkg/format"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/package-url/packageurl-go"
 	"github.com/stretchr/testify/assert"
@@ -325,6 +326,54 @@ func TestTransformLibToPurlType(t *testing.T) {
 	}
 }
 
+func TestAlertToFinding(t *testing.T) {
+	alert := Alert{
+		Type:    "SECURITY_VULNERABILITY",
+		Library: Library{Filename: "log4j-core-2.14.1.jar", ArtifactID: "log4j-core", GroupID: "org.apache.logging.log4j", Version: "2.14.1", LibType: "MAVEN_ARTIFACT"},
+		Vulnerability: Vulnerability{
+			Name:          "CVE-2021-44228",
+			Severity:      "high",
+			Score:         9.3,
+			CVSS3Severity: "high",
+			CVSS3Score:    10,
+			URL:           "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+			Description:   "remote code execution",
+		},
+	}
+
+	t.Run("new alert", func(t *testing.T) {
+		finding := alert.ToFinding()
+		assert.Equal(t, format.Finding{
+			Scanner:     "Mend",
+			ID:          "CVE-2021-44228",
+			Title:       "Security Vulnerability CVE-2021-44228 log4j-core",
+			Description: "remote code execution",
+			Severity:    format.SeverityCritical,
+			Score:       10,
+			CVE:         "CVE-2021-44228",
+			PackageURL:  "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			Location:    &format.FindingLocation{Path: "log4j-core-2.14.1.jar"},
+			AuditState:  format.AuditStateNew,
+			Fingerprint: format.PackageFingerprint("pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", "CVE-2021-44228"),
+			Link:        "https://nvd.nist.gov/vuln/detail/CVE-2021-44228",
+		}, finding)
+	})
+
+	t.Run("assessed alert", func(t *testing.T) {
+		assessed := alert
+		assessed.Assessment = &format.Assessment{Status: format.NotRelevant, Analysis: format.NotUsed}
+		finding := assessed.ToFinding()
+		assert.Equal(t, format.AuditStateNotRelevant, finding.AuditState)
+		assert.Equal(t, "notUsed", finding.AuditComment)
+	})
+
+	t.Run("ignored alert", func(t *testing.T) {
+		ignored := alert
+		ignored.Status = "IGNORED"
+		assert.Equal(t, format.AuditStateNotRelevant, ignored.ToFinding().AuditState)
+	})
+}
+
 func TestGetProjectHierarchy(t *testing.T) {
 	myTestClient := whitesourceMockClient{
 		responseBody: `{